@@ -3,6 +3,7 @@ module github.com/mokiat/lacking-studio
 go 1.23
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/mokiat/gblob v0.3.0
 	github.com/mokiat/gog v0.15.0
 	github.com/mokiat/gomath v0.10.0
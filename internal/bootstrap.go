@@ -13,10 +13,16 @@ func BootstrapApplication(window *ui.Window, globalController *global.Controller
 	scope := co.RootScope(window)
 	scope = co.TypedValueScope(scope, eventBus)
 	scope = co.TypedValueScope(scope, &global.Context{
+		ProjectDir: globalController.ProjectDir(),
+		Project:    globalController.Project(),
+		Logs:       globalController.Logs(),
 		EventBus:   eventBus,
 		Registry:   globalController.Registry(),
+		Overrides:  globalController.Overrides(),
 		GameEngine: globalController.Engine(),
 		CommonData: globalController.CommonData(),
+
+		ReleaseQueue: globalController.ReleaseQueue(),
 	})
 	co.Initialize(scope, co.New(component, nil))
 }
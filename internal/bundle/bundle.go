@@ -0,0 +1,217 @@
+package bundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mokiat/lacking-studio/internal/depgraph"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// Selection determines which resources are placed in a bundle.
+type Selection struct {
+
+	// Patterns select resources by name, using path.Match syntax.
+	Patterns []string
+
+	// Models select resources by exact name.
+	Models []string
+}
+
+// IsEmpty returns whether the selection does not restrict the resources.
+func (s Selection) IsEmpty() bool {
+	return len(s.Patterns) == 0 && len(s.Models) == 0
+}
+
+// Select returns the selected resources of the registry together with
+// everything that they depend on. All resources are selected if the
+// selection is empty.
+func Select(registry *asset.Registry, selection Selection) ([]*asset.Resource, error) {
+	if selection.IsEmpty() {
+		return depgraph.Closure(registry.Resources()), nil
+	}
+
+	var roots []*asset.Resource
+	for _, name := range selection.Models {
+		resource := registry.ResourceByName(name)
+		if resource == nil {
+			return nil, fmt.Errorf("model %q not found", name)
+		}
+		roots = append(roots, resource)
+	}
+	for _, pattern := range selection.Patterns {
+		matches, err := depgraph.Match(registry.Resources(), pattern)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, matches...)
+	}
+	return depgraph.Closure(roots), nil
+}
+
+// Write creates a bundle with the specified resources of the storage. The
+// format is determined by the file extension: .zip, .tar.gz or .tgz.
+func Write(bundlePath string, storage asset.Storage, formatter asset.Formatter, formatterName string, resources []*asset.Resource) (Manifest, error) {
+	manifest := Manifest{
+		Version:   ManifestVersion,
+		Formatter: formatterName,
+	}
+
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		return manifest, fmt.Errorf("error creating bundle file: %w", err)
+	}
+	defer file.Close()
+
+	writer, err := newArchiveWriter(bundlePath, file)
+	if err != nil {
+		return manifest, err
+	}
+
+	ids := make(map[string]struct{}, len(resources))
+	for _, resource := range resources {
+		ids[resource.ID()] = struct{}{}
+
+		data, err := readContent(storage, resource.ID())
+		if err != nil {
+			return manifest, fmt.Errorf("error reading content of %q: %w", resource.Name(), err)
+		}
+		entry := newFileEntry(contentPath(resource.ID()), data)
+		if err := writer.WriteFile(entry.Path, data); err != nil {
+			return manifest, err
+		}
+		manifest.Resources = append(manifest.Resources, ResourceEntry{
+			ID:   resource.ID(),
+			Name: resource.Name(),
+			File: entry,
+		})
+	}
+
+	sourceRegistry, err := readRegistryFile(storage, formatter)
+	if err != nil {
+		return manifest, err
+	}
+	var registryData bytes.Buffer
+	if err := formatter.Encode(&registryData, sourceRegistry.subset(ids)); err != nil {
+		return manifest, fmt.Errorf("error encoding registry file: %w", err)
+	}
+	manifest.Registry = newFileEntry(registryPath, registryData.Bytes())
+	if err := writer.WriteFile(manifest.Registry.Path, registryData.Bytes()); err != nil {
+		return manifest, err
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, fmt.Errorf("error encoding manifest: %w", err)
+	}
+	if err := writer.WriteFile(ManifestFile, manifestData); err != nil {
+		return manifest, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return manifest, fmt.Errorf("error finishing bundle: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return manifest, fmt.Errorf("error closing bundle file: %w", err)
+	}
+	return manifest, nil
+}
+
+const registryPath = "resources.dat"
+
+func contentPath(id string) string {
+	return path.Join("content", id+".dat")
+}
+
+func readContent(storage asset.Storage, id string) ([]byte, error) {
+	in, err := storage.OpenContentRead(id)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	return io.ReadAll(in)
+}
+
+type archiveWriter interface {
+	WriteFile(name string, data []byte) error
+	Close() error
+}
+
+func newArchiveWriter(bundlePath string, out io.Writer) (archiveWriter, error) {
+	lowerPath := strings.ToLower(bundlePath)
+	switch {
+	case strings.HasSuffix(lowerPath, ".zip"):
+		return &zipWriter{
+			writer: zip.NewWriter(out),
+		}, nil
+	case strings.HasSuffix(lowerPath, ".tar.gz"), strings.HasSuffix(lowerPath, ".tgz"):
+		gzipWriter := gzip.NewWriter(out)
+		return &tarWriter{
+			gzipWriter: gzipWriter,
+			writer:     tar.NewWriter(gzipWriter),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported bundle file %q (use .zip, .tar.gz or .tgz)", bundlePath)
+	}
+}
+
+type zipWriter struct {
+	writer *zip.Writer
+}
+
+func (w *zipWriter) WriteFile(name string, data []byte) error {
+	out, err := w.writer.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("error adding %q to bundle: %w", name, err)
+	}
+	if _, err := out.Write(data); err != nil {
+		return fmt.Errorf("error writing %q to bundle: %w", name, err)
+	}
+	return nil
+}
+
+func (w *zipWriter) Close() error {
+	return w.writer.Close()
+}
+
+type tarWriter struct {
+	gzipWriter *gzip.Writer
+	writer     *tar.Writer
+}
+
+func (w *tarWriter) WriteFile(name string, data []byte) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+	}
+	if err := w.writer.WriteHeader(header); err != nil {
+		return fmt.Errorf("error adding %q to bundle: %w", name, err)
+	}
+	if _, err := w.writer.Write(data); err != nil {
+		return fmt.Errorf("error writing %q to bundle: %w", name, err)
+	}
+	return nil
+}
+
+func (w *tarWriter) Close() error {
+	if err := w.writer.Close(); err != nil {
+		return err
+	}
+	return w.gzipWriter.Close()
+}
@@ -0,0 +1,57 @@
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// ManifestFile is the path of the manifest inside a bundle.
+const ManifestFile = "manifest.json"
+
+// ManifestVersion is the version of the manifest format that is written.
+const ManifestVersion = 1
+
+// Manifest describes the content of a bundle.
+type Manifest struct {
+	Version int `json:"version"`
+
+	// Formatter is the formatter that the assets in the bundle are encoded
+	// with.
+	Formatter string `json:"formatter"`
+
+	Registry  FileEntry       `json:"registry"`
+	Resources []ResourceEntry `json:"resources"`
+}
+
+// Size returns the total size of the files in the bundle, excluding the
+// manifest.
+func (m Manifest) Size() int64 {
+	result := m.Registry.Size
+	for _, resource := range m.Resources {
+		result += resource.File.Size
+	}
+	return result
+}
+
+// FileEntry describes a file in a bundle.
+type FileEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ResourceEntry describes a resource in a bundle.
+type ResourceEntry struct {
+	ID   string    `json:"id"`
+	Name string    `json:"name"`
+	File FileEntry `json:"file"`
+}
+
+func newFileEntry(path string, data []byte) FileEntry {
+	hash := sha256.Sum256(data)
+	return FileEntry{
+		Path:   path,
+		Size:   int64(len(data)),
+		SHA256: hex.EncodeToString(hash[:]),
+	}
+}
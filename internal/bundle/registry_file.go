@@ -0,0 +1,106 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// NOTE: The engine does not expose the registry file, but bundles need to
+// contain a registry with only some of the resources. These types mirror
+// the ones of the engine and need to be kept in sync with them, since the
+// blob formatter relies on the field order.
+
+type registryFile struct {
+	Resources    []registryResource   `json:"resources"`
+	Dependencies []registryDependency `json:"dependencies"`
+}
+
+type registryResource struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	PreviewData  []byte `json:"preview"`
+	SourceDigest string `json:"source_digest"`
+}
+
+type registryDependency struct {
+	TargetID string `json:"target_id"`
+	SourceID string `json:"source_id"`
+}
+
+// readRegistryFile reads the registry file of the storage. A storage
+// without a registry file results in an empty one.
+func readRegistryFile(storage asset.Storage, formatter asset.Formatter) (registryFile, error) {
+	in, err := storage.OpenRegistryRead()
+	if err != nil {
+		if errors.Is(err, asset.ErrNotFound) {
+			return registryFile{}, nil
+		}
+		return registryFile{}, fmt.Errorf("error opening registry file: %w", err)
+	}
+	defer in.Close()
+
+	var result registryFile
+	if err := formatter.Decode(in, &result); err != nil {
+		return registryFile{}, fmt.Errorf("error decoding registry file: %w", err)
+	}
+	return result, nil
+}
+
+func writeRegistryFile(storage asset.Storage, formatter asset.Formatter, file registryFile) error {
+	out, err := storage.OpenRegistryWrite()
+	if err != nil {
+		return fmt.Errorf("error creating registry file: %w", err)
+	}
+	defer out.Close()
+
+	if err := formatter.Encode(out, file); err != nil {
+		return fmt.Errorf("error encoding registry file: %w", err)
+	}
+	return nil
+}
+
+// subset returns the part of the registry file that concerns only the
+// resources with the specified IDs.
+func (f registryFile) subset(ids map[string]struct{}) registryFile {
+	var result registryFile
+	for _, resource := range f.Resources {
+		if _, ok := ids[resource.ID]; ok {
+			result.Resources = append(result.Resources, resource)
+		}
+	}
+	for _, dependency := range f.Dependencies {
+		_, hasTarget := ids[dependency.TargetID]
+		_, hasSource := ids[dependency.SourceID]
+		if hasTarget && hasSource {
+			result.Dependencies = append(result.Dependencies, dependency)
+		}
+	}
+	return result
+}
+
+// merge adds the resources of the other registry file to this one. Those
+// that already exist are replaced, including their dependencies.
+func (f *registryFile) merge(other registryFile) {
+	ids := make(map[string]struct{}, len(other.Resources))
+	for _, resource := range other.Resources {
+		ids[resource.ID] = struct{}{}
+	}
+
+	resources := f.Resources[:0]
+	for _, resource := range f.Resources {
+		if _, ok := ids[resource.ID]; !ok {
+			resources = append(resources, resource)
+		}
+	}
+	f.Resources = append(resources, other.Resources...)
+
+	dependencies := f.Dependencies[:0]
+	for _, dependency := range f.Dependencies {
+		if _, ok := ids[dependency.TargetID]; !ok {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	f.Dependencies = append(dependencies, other.Dependencies...)
+}
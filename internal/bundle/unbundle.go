@@ -0,0 +1,175 @@
+package bundle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mokiat/lacking-studio/internal/storage"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// Extract adds the resources of the bundle to the target storage, after
+// verifying the hashes of all files against the manifest. Resources that
+// already exist in the target are replaced.
+func Extract(bundlePath string, target asset.Storage, formatter asset.Formatter, formatterName string) (Manifest, error) {
+	source, err := storage.NewArchiveStorage(bundlePath)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("error opening bundle: %w", err)
+	}
+
+	manifestData, err := readFile(source, ManifestFile)
+	if err != nil {
+		return Manifest{}, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return manifest, fmt.Errorf("error decoding manifest: %w", err)
+	}
+	if manifest.Version != ManifestVersion {
+		return manifest, fmt.Errorf("unsupported manifest version %d", manifest.Version)
+	}
+	if manifest.Formatter != formatterName {
+		return manifest, fmt.Errorf("bundle uses formatter %q but the project uses %q", manifest.Formatter, formatterName)
+	}
+
+	if err := validateManifest(manifest); err != nil {
+		return manifest, err
+	}
+
+	// All files are verified before anything is written, so that a corrupt
+	// bundle leaves the target untouched.
+	registryData, err := readVerifiedFile(source, manifest.Registry)
+	if err != nil {
+		return manifest, err
+	}
+	contents := make([][]byte, len(manifest.Resources))
+	for i, resource := range manifest.Resources {
+		if contents[i], err = readVerifiedFile(source, resource.File); err != nil {
+			return manifest, err
+		}
+	}
+
+	var bundleRegistry registryFile
+	if err := formatter.Decode(bytes.NewReader(registryData), &bundleRegistry); err != nil {
+		return manifest, fmt.Errorf("error decoding bundle registry: %w", err)
+	}
+	if err := validateRegistry(manifest, bundleRegistry); err != nil {
+		return manifest, err
+	}
+	targetRegistry, err := readRegistryFile(target, formatter)
+	if err != nil {
+		return manifest, err
+	}
+	targetRegistry.merge(bundleRegistry)
+
+	for i, resource := range manifest.Resources {
+		if err := writeContent(target, resource.ID, contents[i]); err != nil {
+			return manifest, fmt.Errorf("error writing content of %q: %w", resource.Name, err)
+		}
+	}
+	if err := writeRegistryFile(target, formatter, targetRegistry); err != nil {
+		return manifest, err
+	}
+	return manifest, nil
+}
+
+// validateManifest checks that the manifest refers only to the files that
+// a bundle contains. Resource IDs end up in the paths of content files, so
+// they must not be able to point outside of the target storage.
+func validateManifest(manifest Manifest) error {
+	if manifest.Registry.Path != registryPath {
+		return fmt.Errorf("unexpected registry file %q in manifest", manifest.Registry.Path)
+	}
+	ids := make(map[string]struct{}, len(manifest.Resources))
+	for _, resource := range manifest.Resources {
+		if err := validateID(resource.ID); err != nil {
+			return err
+		}
+		if _, ok := ids[resource.ID]; ok {
+			return fmt.Errorf("duplicate resource ID %q in manifest", resource.ID)
+		}
+		ids[resource.ID] = struct{}{}
+		if resource.File.Path != contentPath(resource.ID) {
+			return fmt.Errorf("unexpected content file %q for resource %q", resource.File.Path, resource.ID)
+		}
+	}
+	return nil
+}
+
+// validateRegistry checks that the registry of a bundle contains exactly
+// the resources of the manifest.
+func validateRegistry(manifest Manifest, registry registryFile) error {
+	ids := make(map[string]struct{}, len(manifest.Resources))
+	for _, resource := range manifest.Resources {
+		ids[resource.ID] = struct{}{}
+	}
+	registryIDs := make(map[string]struct{}, len(registry.Resources))
+	for _, resource := range registry.Resources {
+		if _, ok := ids[resource.ID]; !ok {
+			return fmt.Errorf("resource %q of bundle registry is not in the manifest", resource.ID)
+		}
+		registryIDs[resource.ID] = struct{}{}
+	}
+	for id := range ids {
+		if _, ok := registryIDs[id]; !ok {
+			return fmt.Errorf("resource %q of manifest is not in the bundle registry", id)
+		}
+	}
+	for _, dependency := range registry.Dependencies {
+		_, hasTarget := ids[dependency.TargetID]
+		_, hasSource := ids[dependency.SourceID]
+		if !hasTarget || !hasSource {
+			return fmt.Errorf("dependency of %q on %q refers to resources outside the bundle", dependency.TargetID, dependency.SourceID)
+		}
+	}
+	return nil
+}
+
+func validateID(id string) error {
+	if id == "" || id == "." || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return fmt.Errorf("invalid resource ID %q in manifest", id)
+	}
+	return nil
+}
+
+func readFile(source *storage.ArchiveStorage, name string) ([]byte, error) {
+	in, err := source.OpenFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %q in bundle: %w", name, err)
+	}
+	defer in.Close()
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q in bundle: %w", name, err)
+	}
+	return data, nil
+}
+
+func readVerifiedFile(source *storage.ArchiveStorage, entry FileEntry) ([]byte, error) {
+	data, err := readFile(source, entry.Path)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(data)
+	if int64(len(data)) != entry.Size || hex.EncodeToString(hash[:]) != entry.SHA256 {
+		return nil, fmt.Errorf("file %q in bundle does not match the manifest", entry.Path)
+	}
+	return data, nil
+}
+
+func writeContent(target asset.Storage, id string, data []byte) error {
+	out, err := target.OpenContentWrite(id)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(data); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
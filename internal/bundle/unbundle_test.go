@@ -0,0 +1,158 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mokiat/lacking-studio/internal/storage"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+func TestExtractRoundTrip(t *testing.T) {
+	formatter := asset.NewJSONFormatter()
+
+	source := storage.NewMemoryStorage()
+	registry, err := asset.NewRegistry(source, formatter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := registry.CreateResource("leaf", asset.Model{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.CreateResource("top", asset.Model{ModelDefinitions: []string{leaf.ID()}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.CreateResource("unrelated", asset.Model{}); err != nil {
+		t.Fatal(err)
+	}
+
+	resources, err := Select(registry, Selection{Models: []string{"top"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if _, err := Write(bundlePath, source, formatter, "json", resources); err != nil {
+		t.Fatal(err)
+	}
+
+	target := storage.NewMemoryStorage()
+	manifest, err := Extract(bundlePath, target, formatter, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Resources) != 2 {
+		t.Fatalf("expected 2 resources, got %d", len(manifest.Resources))
+	}
+
+	targetRegistry, err := asset.NewRegistry(target, formatter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	top := targetRegistry.ResourceByName("top")
+	if top == nil {
+		t.Fatal("expected the top resource to be extracted")
+	}
+	if dependencies := top.Dependencies(); len(dependencies) != 1 || dependencies[0].Name() != "leaf" {
+		t.Errorf("expected top to depend on leaf")
+	}
+	if targetRegistry.ResourceByName("unrelated") != nil {
+		t.Errorf("expected the unrelated resource to be left out")
+	}
+}
+
+func TestExtractRejectsEscapingID(t *testing.T) {
+	const id = "../../escaped"
+	projectDir := t.TempDir()
+	assetsDir := filepath.Join(projectDir, "a", "assets")
+
+	bundlePath := writeTestBundle(t, id, id)
+	target, err := asset.NewFSStorage(assetsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Extract(bundlePath, target, asset.NewJSONFormatter(), "json"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	matches, err := filepath.Glob(filepath.Join(projectDir, "*", "*.dat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, "escaped.dat")); err == nil || len(matches) > 0 {
+		t.Errorf("expected no files outside the assets dir")
+	}
+}
+
+func TestExtractRejectsRegistryMismatch(t *testing.T) {
+	bundlePath := writeTestBundle(t, "manifest-id", "registry-id")
+	target := storage.NewMemoryStorage()
+	if _, err := Extract(bundlePath, target, asset.NewJSONFormatter(), "json"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(target.Files()) != 0 {
+		t.Errorf("expected the target to be left untouched")
+	}
+}
+
+// writeTestBundle writes a bundle by hand, with valid hashes, so that the
+// manifest and the registry can refer to arbitrary IDs.
+func writeTestBundle(t *testing.T, manifestID, registryID string) string {
+	t.Helper()
+
+	var registryData bytes.Buffer
+	err := asset.NewJSONFormatter().Encode(&registryData, registryFile{
+		Resources: []registryResource{
+			{ID: registryID, Name: "model"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("{}")
+
+	manifest := Manifest{
+		Version:   ManifestVersion,
+		Formatter: "json",
+		Registry:  newFileEntry(registryPath, registryData.Bytes()),
+		Resources: []ResourceEntry{
+			{
+				ID:   manifestID,
+				Name: "model",
+				File: newFileEntry(contentPath(manifestID), content),
+			},
+		},
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.zip")
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	writer, err := newArchiveWriter(bundlePath, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		manifest.Registry.Path:          registryData.Bytes(),
+		manifest.Resources[0].File.Path: content,
+		ManifestFile:                    manifestData,
+	}
+	for name, data := range files {
+		if err := writer.WriteFile(name, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bundlePath
+}
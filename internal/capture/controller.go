@@ -0,0 +1,267 @@
+package capture
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"time"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking-studio/internal/global"
+	"github.com/mokiat/lacking-studio/internal/viewport"
+	"github.com/mokiat/lacking/app"
+	"github.com/mokiat/lacking/game"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/graphics"
+	"github.com/mokiat/lacking/render"
+)
+
+// warmupFrames specifies the number of frames that are rendered after the
+// model has loaded before the image is captured. This gives the renderer
+// a chance to settle shadows and other frame-dependent state.
+const warmupFrames = 3
+
+// fenceTimeout specifies how long to wait for the GPU to copy the pixels
+// before the capture is considered failed.
+//
+// NOTE: The native backend reports a fence that has errored as not ready,
+// so such a fence would otherwise be polled forever.
+const fenceTimeout = 10 * time.Second
+
+// Settings configures how a capture is performed.
+type Settings struct {
+	Resource   *asset.Resource
+	OutputFile string
+
+	Width  uint32
+	Height uint32
+
+	Yaw      dprec.Angle
+	Pitch    dprec.Angle
+	Zoom     float64
+	Exposure float32
+
+	ShowGrid             bool
+	ShowAmbientLight     bool
+	ShowDirectionalLight bool
+	ShowSky              bool
+}
+
+func NewController(globalController *global.Controller, settings Settings) *Controller {
+	return &Controller{
+		globalController: globalController,
+		settings:         settings,
+	}
+}
+
+var _ app.Controller = (*Controller)(nil)
+
+// Controller renders a single model resource into an offscreen framebuffer
+// and writes the result as a PNG file, after which it closes the window.
+type Controller struct {
+	app.NopController
+
+	globalController *global.Controller
+	settings         Settings
+
+	renderAPI  render.API
+	gameEngine *game.Engine
+	gameScene  *game.Scene
+
+	resourceSet *game.ResourceSet
+	model       *game.Model
+
+	colorTexture  render.Texture
+	framebuffer   render.Framebuffer
+	pixelBuffer   render.Buffer
+	commandBuffer render.CommandBuffer
+	fence         render.Fence
+	fenceTime     time.Time
+
+	frame int
+	err   error
+}
+
+// Err returns the error, if any, that occurred during the capture.
+func (c *Controller) Err() error {
+	return c.err
+}
+
+func (c *Controller) OnCreate(window app.Window) {
+	c.renderAPI = window.RenderAPI()
+	c.gameEngine = c.globalController.Engine()
+	c.gameScene = c.gameEngine.CreateScene()
+	c.gameEngine.SetActiveScene(c.gameScene)
+
+	environment := viewport.NewEnvironment(c.gameScene.Graphics(), c.globalController.CommonData())
+	environment.SetExposure(c.settings.Exposure)
+	environment.SetShowGrid(c.settings.ShowGrid)
+	environment.SetShowAmbientLight(c.settings.ShowAmbientLight)
+	environment.SetShowDirectionalLight(c.settings.ShowDirectionalLight)
+	environment.SetShowSky(c.settings.ShowSky)
+
+	cameraGizmo := viewport.NewCameraGizmo(environment.Camera())
+	cameraGizmo.SetYaw(c.settings.Yaw)
+	cameraGizmo.SetPitch(c.settings.Pitch)
+	cameraGizmo.SetZoom(c.settings.Zoom)
+
+	c.colorTexture = c.renderAPI.CreateColorTexture2D(render.ColorTexture2DInfo{
+		Label:           "Capture Color Texture",
+		GenerateMipmaps: false,
+		GammaCorrection: true,
+		Format:          render.DataFormatRGBA8,
+		MipmapLayers: []render.Mipmap2DLayer{
+			{
+				Width:  c.settings.Width,
+				Height: c.settings.Height,
+			},
+		},
+	})
+	c.framebuffer = c.renderAPI.CreateFramebuffer(render.FramebufferInfo{
+		Label: "Capture Framebuffer",
+		ColorAttachments: [4]opt.T[render.TextureAttachment]{
+			opt.V(render.PlainTextureAttachment(c.colorTexture)),
+		},
+	})
+	c.pixelBuffer = c.renderAPI.CreatePixelTransferBuffer(render.BufferInfo{
+		Label:   "Capture Pixel Buffer",
+		Dynamic: true,
+		Size:    c.settings.Width * c.settings.Height * 4,
+	})
+	c.commandBuffer = c.renderAPI.CreateCommandBuffer(1024)
+
+	c.resourceSet = c.gameEngine.CreateResourceSet()
+	promise := c.resourceSet.OpenModelByID(c.settings.Resource.ID())
+	promise.OnSuccess(func(modelDefinition *game.ModelDefinition) {
+		window.Schedule(func() {
+			c.handleModelLoaded(modelDefinition)
+		})
+	})
+	promise.OnError(func(err error) {
+		window.Schedule(func() {
+			c.fail(window, fmt.Errorf("error loading model: %w", err))
+		})
+	})
+}
+
+func (c *Controller) OnRender(window app.Window) {
+	defer window.Invalidate()
+
+	if c.model == nil || c.err != nil {
+		return
+	}
+
+	if c.fence != nil {
+		switch status := c.fence.Status(); status {
+		case render.FenceStatusSuccess:
+			c.handlePixelsReady(window)
+		case render.FenceStatusNotReady:
+			if time.Since(c.fenceTime) > fenceTimeout {
+				c.fail(window, fmt.Errorf("timed out waiting for pixels after %s", fenceTimeout))
+			}
+		default:
+			c.fail(window, fmt.Errorf("error waiting for pixels: fence status %s", status))
+		}
+		return
+	}
+
+	c.gameEngine.Render(c.framebuffer, graphics.NewViewport(0, 0, c.settings.Width, c.settings.Height))
+
+	c.frame++
+	if c.frame < warmupFrames {
+		return
+	}
+
+	c.commandBuffer.BeginRenderPass(render.RenderPassInfo{
+		Framebuffer: c.framebuffer,
+		Viewport: render.Area{
+			Width:  c.settings.Width,
+			Height: c.settings.Height,
+		},
+		DepthLoadOp:    render.LoadOperationLoad,
+		DepthStoreOp:   render.StoreOperationDiscard,
+		StencilLoadOp:  render.LoadOperationLoad,
+		StencilStoreOp: render.StoreOperationDiscard,
+		Colors: [4]render.ColorAttachmentInfo{
+			{
+				LoadOp:  render.LoadOperationLoad,
+				StoreOp: render.StoreOperationStore,
+			},
+		},
+	})
+	c.commandBuffer.CopyFramebufferToBuffer(render.CopyFramebufferToBufferInfo{
+		Buffer: c.pixelBuffer,
+		Width:  c.settings.Width,
+		Height: c.settings.Height,
+		Format: render.DataFormatRGBA8,
+	})
+	c.commandBuffer.EndRenderPass()
+
+	queue := c.renderAPI.Queue()
+	queue.Submit(c.commandBuffer)
+	c.fence = queue.TrackSubmittedWorkDone()
+	c.fenceTime = time.Now()
+}
+
+func (c *Controller) OnDestroy(window app.Window) {
+	if c.fence != nil {
+		c.fence.Release()
+	}
+	c.pixelBuffer.Release()
+	c.framebuffer.Release()
+	c.colorTexture.Release()
+	c.resourceSet.Delete()
+	c.gameScene.Delete()
+}
+
+func (c *Controller) handleModelLoaded(modelDefinition *game.ModelDefinition) {
+	c.model = c.gameScene.CreateModel(game.ModelInfo{
+		Name:       "Model",
+		Definition: modelDefinition,
+		IsDynamic:  false,
+	})
+}
+
+func (c *Controller) handlePixelsReady(window app.Window) {
+	width, height := int(c.settings.Width), int(c.settings.Height)
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	data := make([]byte, len(img.Pix))
+	c.renderAPI.Queue().ReadBuffer(c.pixelBuffer, 0, data)
+
+	// NOTE: The framebuffer origin is at the bottom-left corner, whereas
+	// images have their origin at the top-left corner.
+	stride := width * 4
+	for y := range height {
+		srcOffset := (height - y - 1) * stride
+		copy(img.Pix[y*img.Stride:y*img.Stride+stride], data[srcOffset:srcOffset+stride])
+	}
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xFF
+	}
+
+	if err := writeImage(c.settings.OutputFile, img); err != nil {
+		c.fail(window, err)
+		return
+	}
+	window.Close()
+}
+
+func (c *Controller) fail(window app.Window, err error) {
+	c.err = err
+	window.Close()
+}
+
+func writeImage(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating image file: %w", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		return fmt.Errorf("error encoding image: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	// FileNameTOML is the name of the project configuration file in TOML
+	// format. It takes precedence over FileNameJSON.
+	FileNameTOML = "studio.toml"
+
+	// FileNameJSON is the name of the project configuration file in JSON
+	// format.
+	FileNameJSON = "studio.json"
+)
+
+const (
+	// StorageFS stores the assets in a directory.
+	StorageFS StorageKind = "fs"
+
+	// StorageArchive reads the assets from a zip or tar archive.
+	StorageArchive StorageKind = "archive"
+
+	// StorageHTTP fetches the assets from a web server.
+	StorageHTTP StorageKind = "http"
+
+	// StorageMemory keeps the assets in memory, starting out empty.
+	StorageMemory StorageKind = "memory"
+)
+
+// StorageKind determines where the assets of a project are stored.
+type StorageKind string
+
+const (
+	FormatterBlob Formatter = "blob"
+	FormatterJSON Formatter = "json"
+)
+
+// Formatter is the encoding of the assets of a project.
+type Formatter string
+
+const (
+	// PackKindCommand packs the assets by running an external command.
+	PackKindCommand PackKind = "command"
+
+	// PackKindDSL packs the assets with the DSL that is registered in the
+	// studio process.
+	PackKindDSL PackKind = "dsl"
+
+	// PackKindRemote packs the assets through a pack server.
+	PackKindRemote PackKind = "remote"
+)
+
+// ModelPlaceholder is replaced with the name of the model in the model
+// arguments of a pack command.
+const ModelPlaceholder = "{model}"
+
+// PackKind determines how the assets of a project are packed.
+type PackKind string
+
+// Project holds the configuration of a project.
+type Project struct {
+	Assets   Assets   `json:"assets" toml:"assets"`
+	Pack     Pack     `json:"pack" toml:"pack"`
+	Window   Window   `json:"window" toml:"window"`
+	Viewport Viewport `json:"viewport" toml:"viewport"`
+
+	// Watch lists glob patterns, relative to the project directory, of the
+	// source files that the assets are built from. The patterns use the
+	// syntax of path.Match, so a "*" does not match across directories.
+	Watch []string `json:"watch,omitempty" toml:"watch,omitempty"`
+
+	// Roots lists name patterns of the resources that the game uses
+	// directly. Resources that are not reachable from them are removed by
+	// garbage collection.
+	Roots []string `json:"roots,omitempty" toml:"roots,omitempty"`
+}
+
+// Assets configures where the assets of a project are stored.
+type Assets struct {
+	Storage StorageKind `json:"storage" toml:"storage"`
+
+	// Path is the assets directory or archive, relative to the project
+	// directory, when the storage is StorageFS or StorageArchive.
+	Path string `json:"path,omitempty" toml:"path,omitempty"`
+
+	// URL is the address of the assets, when the storage is StorageHTTP.
+	URL string `json:"url,omitempty" toml:"url,omitempty"`
+
+	Formatter Formatter `json:"formatter" toml:"formatter"`
+}
+
+// Pack configures how the assets of a project are packed.
+type Pack struct {
+	Kind PackKind `json:"kind" toml:"kind"`
+
+	// Command is the command that packs all models, when the kind is
+	// PackKindCommand. It runs in the project directory.
+	Command []string `json:"command,omitempty" toml:"command,omitempty"`
+
+	// ModelArgs are appended to Command to pack a single model, after
+	// ModelPlaceholder is replaced with its name.
+	ModelArgs []string `json:"model_args,omitempty" toml:"model_args,omitempty"`
+
+	// URL is the address of the pack server, when the kind is
+	// PackKindRemote.
+	URL string `json:"url,omitempty" toml:"url,omitempty"`
+}
+
+// Window configures the initial size of the studio window.
+type Window struct {
+	Width  int `json:"width" toml:"width"`
+	Height int `json:"height" toml:"height"`
+}
+
+// Viewport holds the viewport settings that are used when a project is
+// opened for the first time.
+type Viewport struct {
+	ShowGrid             bool   `json:"show_grid" toml:"show_grid"`
+	ShowSky              bool   `json:"show_sky" toml:"show_sky"`
+	ShowAmbientLight     bool   `json:"show_ambient_light" toml:"show_ambient_light"`
+	ShowDirectionalLight bool   `json:"show_directional_light" toml:"show_directional_light"`
+	AutoExposure         bool   `json:"auto_exposure" toml:"auto_exposure"`
+	MeasureUnit          string `json:"measure_unit" toml:"measure_unit"`
+}
+
+// DefaultProject returns the configuration of projects that do not have a
+// configuration file.
+func DefaultProject() Project {
+	return Project{
+		Assets: Assets{
+			Storage:   StorageFS,
+			Path:      "assets",
+			Formatter: FormatterBlob,
+		},
+		Pack: Pack{
+			Kind:      PackKindCommand,
+			Command:   []string{"task", "pack"},
+			ModelArgs: []string{"--", ModelPlaceholder},
+		},
+		Window: Window{
+			Width:  1280,
+			Height: 800,
+		},
+		Viewport: Viewport{
+			ShowGrid:             true,
+			ShowSky:              true,
+			ShowAmbientLight:     true,
+			ShowDirectionalLight: true,
+			AutoExposure:         false,
+			MeasureUnit:          "m",
+		},
+	}
+}
+
+// ProjectFile returns the path of the configuration file of the project
+// in the specified directory or an empty string if it has none.
+func ProjectFile(projectDir string) string {
+	for _, name := range []string{FileNameTOML, FileNameJSON} {
+		path := filepath.Join(projectDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadProject loads the configuration of the project in the specified
+// directory. Settings that are missing from the file keep their defaults.
+func LoadProject(projectDir string) (Project, error) {
+	result := DefaultProject()
+
+	path := ProjectFile(projectDir)
+	if path == "" {
+		return result, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return result, nil
+		}
+		return result, fmt.Errorf("error reading config file: %w", err)
+	}
+	if filepath.Ext(path) == ".toml" {
+		if _, err := toml.Decode(string(data), &result); err != nil {
+			return DefaultProject(), fmt.Errorf("error decoding config file: %w", err)
+		}
+	} else {
+		if err := json.Unmarshal(data, &result); err != nil {
+			return DefaultProject(), fmt.Errorf("error decoding config file: %w", err)
+		}
+	}
+	if err := result.Validate(); err != nil {
+		return DefaultProject(), fmt.Errorf("invalid config file %q: %w", path, err)
+	}
+	return result, nil
+}
+
+// SaveProject writes the configuration to the specified file, in TOML or
+// JSON format depending on its extension.
+func SaveProject(path string, project Project) error {
+	var data []byte
+	switch filepath.Ext(path) {
+	case ".toml":
+		var buffer bytes.Buffer
+		if err := toml.NewEncoder(&buffer).Encode(project); err != nil {
+			return fmt.Errorf("error encoding config: %w", err)
+		}
+		data = buffer.Bytes()
+	case ".json":
+		var err error
+		if data, err = json.MarshalIndent(project, "", "  "); err != nil {
+			return fmt.Errorf("error encoding config: %w", err)
+		}
+		data = append(data, '\n')
+	default:
+		return fmt.Errorf("unsupported config file extension %q", filepath.Ext(path))
+	}
+	if err := os.WriteFile(path, data, 0664); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that the configuration can be used.
+func (p Project) Validate() error {
+	switch p.Assets.Storage {
+	case StorageFS, StorageArchive:
+		if p.Assets.Path == "" {
+			return errors.New("assets path is not specified")
+		}
+	case StorageHTTP:
+		if p.Assets.URL == "" {
+			return errors.New("assets URL is not specified")
+		}
+	case StorageMemory:
+	default:
+		return fmt.Errorf("unknown assets storage %q", p.Assets.Storage)
+	}
+	switch p.Assets.Formatter {
+	case FormatterBlob, FormatterJSON:
+	default:
+		return fmt.Errorf("unknown assets formatter %q", p.Assets.Formatter)
+	}
+
+	switch p.Pack.Kind {
+	case PackKindCommand:
+		if len(p.Pack.Command) == 0 {
+			return errors.New("pack command is not specified")
+		}
+	case PackKindDSL:
+	case PackKindRemote:
+		if p.Pack.URL == "" {
+			return errors.New("pack server URL is not specified")
+		}
+	default:
+		return fmt.Errorf("unknown pack kind %q", p.Pack.Kind)
+	}
+
+	if p.Window.Width <= 0 || p.Window.Height <= 0 {
+		return fmt.Errorf("invalid window size %dx%d", p.Window.Width, p.Window.Height)
+	}
+
+	for _, pattern := range p.Watch {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid watch pattern %q: %w", pattern, err)
+		}
+		if strings.Contains(pattern, "**") {
+			return fmt.Errorf("invalid watch pattern %q: recursive wildcards are not supported", pattern)
+		}
+	}
+	for _, pattern := range p.Roots {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid root pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/mokiat/lacking-studio/internal/storage"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// AssetsPath returns the path of the assets directory or archive of the
+// project in the specified directory.
+func (p Project) AssetsPath(projectDir string) string {
+	if filepath.IsAbs(p.Assets.Path) {
+		return p.Assets.Path
+	}
+	return filepath.Join(projectDir, p.Assets.Path)
+}
+
+// NewStorage creates the storage of the assets of the project in the
+// specified directory.
+func (p Project) NewStorage(projectDir string) (asset.Storage, error) {
+	switch p.Assets.Storage {
+	case StorageFS:
+		return asset.NewFSStorage(p.AssetsPath(projectDir))
+	case StorageArchive:
+		return storage.NewArchiveStorage(p.AssetsPath(projectDir))
+	case StorageHTTP:
+		return storage.NewHTTPStorage(p.Assets.URL, nil)
+	case StorageMemory:
+		return storage.NewMemoryStorage(), nil
+	default:
+		return nil, fmt.Errorf("unknown assets storage %q", p.Assets.Storage)
+	}
+}
+
+// NewRegistry creates a registry for the assets of the project in the
+// specified directory.
+func (p Project) NewRegistry(projectDir string) (*asset.Registry, error) {
+	storage, err := p.NewStorage(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("error creating storage: %w", err)
+	}
+	formatter, err := p.NewFormatter()
+	if err != nil {
+		return nil, err
+	}
+	return asset.NewRegistry(storage, formatter)
+}
+
+// NewFormatter creates the formatter of the assets of the project.
+func (p Project) NewFormatter() (asset.Formatter, error) {
+	switch p.Assets.Formatter {
+	case FormatterBlob:
+		return asset.NewBlobFormatter(), nil
+	case FormatterJSON:
+		return asset.NewJSONFormatter(), nil
+	default:
+		return nil, fmt.Errorf("unknown assets formatter %q", p.Assets.Formatter)
+	}
+}
+
+// NewOverrideRegistry creates a registry for the assets of the project in
+// the specified directory, together with the storage through which the
+// content of its resources can be overridden without changing the assets.
+func (p Project) NewOverrideRegistry(projectDir string) (*asset.Registry, *storage.OverrideStorage, error) {
+	assetsStorage, err := p.NewStorage(projectDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating storage: %w", err)
+	}
+	formatter, err := p.NewFormatter()
+	if err != nil {
+		return nil, nil, err
+	}
+	overrides := storage.NewOverrideStorage(assetsStorage, formatter)
+	registry, err := asset.NewRegistry(overrides, formatter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return registry, overrides, nil
+}
@@ -0,0 +1,42 @@
+package depgraph
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// Closure returns the specified resources together with all resources
+// that they depend on, directly or transitively, sorted by name.
+func Closure(roots []*asset.Resource) []*asset.Resource {
+	visited := make(map[string]struct{})
+	var result []*asset.Resource
+	queue := slices.Clone(roots)
+	for len(queue) > 0 {
+		resource := queue[0]
+		queue = queue[1:]
+		if resource == nil {
+			continue
+		}
+		if _, ok := visited[resource.ID()]; ok {
+			continue
+		}
+		visited[resource.ID()] = struct{}{}
+		result = append(result, resource)
+		queue = append(queue, resource.Dependencies()...)
+	}
+	SortByName(result)
+	return result
+}
+
+// SortByName sorts the resources by name and then by ID, for a stable
+// order.
+func SortByName(resources []*asset.Resource) {
+	slices.SortFunc(resources, func(a, b *asset.Resource) int {
+		if result := strings.Compare(a.Name(), b.Name()); result != 0 {
+			return result
+		}
+		return strings.Compare(a.ID(), b.ID())
+	})
+}
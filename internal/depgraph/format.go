@@ -0,0 +1,132 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	FormatDOT     Format = "dot"
+	FormatJSON    Format = "json"
+	FormatMermaid Format = "mermaid"
+)
+
+// Format is a text representation of a Graph.
+type Format string
+
+// Formats returns all supported formats.
+func Formats() []Format {
+	return []Format{
+		FormatDOT,
+		FormatJSON,
+		FormatMermaid,
+	}
+}
+
+// ParseFormat returns the format with the specified name.
+func ParseFormat(name string) (Format, error) {
+	format := Format(name)
+	if !slices.Contains(Formats(), format) {
+		return "", fmt.Errorf("unsupported graph format %q", name)
+	}
+	return format, nil
+}
+
+// Write writes the graph in the specified format.
+func (g Graph) Write(out io.Writer, format Format) error {
+	switch format {
+	case FormatDOT:
+		return g.WriteDOT(out)
+	case FormatJSON:
+		return g.WriteJSON(out)
+	case FormatMermaid:
+		return g.WriteMermaid(out)
+	default:
+		return fmt.Errorf("unsupported graph format %q", format)
+	}
+}
+
+// Text returns the graph in the specified format.
+func (g Graph) Text(format Format) (string, error) {
+	var builder strings.Builder
+	if err := g.Write(&builder, format); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+func (g Graph) WriteDOT(out io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString("digraph resources {\n")
+	builder.WriteString("\trankdir=LR;\n")
+	builder.WriteString("\tnode [shape=box];\n")
+	for _, node := range g.Nodes {
+		label := dotEscape(node.Name)
+		if node.Size != 0 {
+			label += `\n` + FormatSize(node.Size)
+		}
+		fmt.Fprintf(&builder, "\t\"%s\" [label=\"%s\"];\n", dotEscape(node.ID), label)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&builder, "\t\"%s\" -> \"%s\";\n", dotEscape(edge.From), dotEscape(edge.To))
+	}
+	builder.WriteString("}\n")
+	_, err := io.WriteString(out, builder.String())
+	return err
+}
+
+// WriteJSON writes the graph as a JSON document.
+func (g Graph) WriteJSON(out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+func (g Graph) WriteMermaid(out io.Writer) error {
+	// NOTE: Resource IDs are not valid Mermaid identifiers in general,
+	// so nodes are identified by their index instead.
+	nodeIDs := make(map[string]string, len(g.Nodes))
+	var builder strings.Builder
+	builder.WriteString("graph LR\n")
+	for i, node := range g.Nodes {
+		nodeID := fmt.Sprintf("n%d", i)
+		nodeIDs[node.ID] = nodeID
+		label := strings.ReplaceAll(node.Name, `"`, "#quot;")
+		if node.Size != 0 {
+			label += "<br/>" + FormatSize(node.Size)
+		}
+		fmt.Fprintf(&builder, "\t%s[\"%s\"]\n", nodeID, label)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&builder, "\t%s --> %s\n", nodeIDs[edge.From], nodeIDs[edge.To])
+	}
+	_, err := io.WriteString(out, builder.String())
+	return err
+}
+
+// FormatSize returns a human-readable representation of a size in bytes.
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TiB", value)
+}
+
+func dotEscape(text string) string {
+	return dotReplacer.Replace(text)
+}
+
+var dotReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
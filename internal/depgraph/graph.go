@@ -0,0 +1,99 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mokiat/lacking-studio/internal/storage"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// Graph is the dependency graph of a set of resources.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Node is a resource in a Graph.
+type Node struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// Size is the size in bytes of the content of the resource, if known.
+	Size int64 `json:"size,omitempty"`
+}
+
+// Edge indicates that the From resource depends on the To resource.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// New creates the dependency graph of the specified resources, sorted by
+// name. Dependencies on resources that are not included are omitted.
+func New(resources []*asset.Resource) Graph {
+	sorted := make([]*asset.Resource, len(resources))
+	copy(sorted, resources)
+	SortByName(sorted)
+
+	included := make(map[string]struct{}, len(sorted))
+	for _, resource := range sorted {
+		included[resource.ID()] = struct{}{}
+	}
+
+	graph := Graph{
+		Nodes: []Node{},
+		Edges: []Edge{},
+	}
+	for _, resource := range sorted {
+		graph.Nodes = append(graph.Nodes, Node{
+			ID:   resource.ID(),
+			Name: resource.Name(),
+		})
+		// NOTE: Dependencies on resources that are missing from the registry
+		// are returned as nil.
+		dependencies := slices.DeleteFunc(resource.Dependencies(), func(dependency *asset.Resource) bool {
+			return dependency == nil
+		})
+		SortByName(dependencies)
+		for _, dependency := range dependencies {
+			if _, ok := included[dependency.ID()]; ok {
+				graph.Edges = append(graph.Edges, Edge{
+					From: resource.ID(),
+					To:   dependency.ID(),
+				})
+			}
+		}
+	}
+	return graph
+}
+
+// Reachable creates the dependency graph of the specified resource and
+// everything that it depends on.
+func Reachable(resource *asset.Resource) Graph {
+	return New(Closure([]*asset.Resource{resource}))
+}
+
+// TotalSize returns the sum of the sizes of all nodes.
+func (g Graph) TotalSize() int64 {
+	var result int64
+	for _, node := range g.Nodes {
+		result += node.Size
+	}
+	return result
+}
+
+// LoadSizes sets the size of every node to the size of the content of the
+// resource in the specified storage. Resources without content are left
+// with a zero size.
+func (g Graph) LoadSizes(assetsStorage asset.Storage) error {
+	for i := range g.Nodes {
+		size, err := storage.ContentSize(assetsStorage, g.Nodes[i].ID)
+		if err != nil && !errors.Is(err, asset.ErrNotFound) {
+			return fmt.Errorf("error reading content of %q: %w", g.Nodes[i].Name, err)
+		}
+		g.Nodes[i].Size = size
+	}
+	return nil
+}
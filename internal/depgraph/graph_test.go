@@ -0,0 +1,38 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/mokiat/lacking-studio/internal/storage"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+func TestNewWithDanglingDependency(t *testing.T) {
+	registry, err := asset.NewRegistry(storage.NewMemoryStorage(), asset.NewJSONFormatter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := registry.CreateResource("leaf", asset.Model{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	top, err := registry.CreateResource("top", asset.Model{
+		ModelDefinitions: []string{"missing", leaf.ID()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	graph := New(registry.Resources())
+	if len(graph.Nodes) != 2 {
+		t.Errorf("expected 2 nodes, got %d", len(graph.Nodes))
+	}
+	if len(graph.Edges) != 1 || graph.Edges[0] != (Edge{From: top.ID(), To: leaf.ID()}) {
+		t.Errorf("unexpected edges %v", graph.Edges)
+	}
+
+	reachable := Reachable(top)
+	if len(reachable.Nodes) != 2 {
+		t.Errorf("expected 2 reachable nodes, got %d", len(reachable.Nodes))
+	}
+}
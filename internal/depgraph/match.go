@@ -0,0 +1,40 @@
+package depgraph
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// Match returns the resources whose name matches the specified pattern,
+// using path.Match syntax.
+func Match(resources []*asset.Resource, pattern string) ([]*asset.Resource, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	var result []*asset.Resource
+	for _, resource := range resources {
+		if matched, _ := path.Match(pattern, resource.Name()); matched {
+			result = append(result, resource)
+		}
+	}
+	return result, nil
+}
+
+// Unreachable returns the resources that are neither one of the roots nor
+// a dependency of one of them, sorted by name.
+func Unreachable(resources, roots []*asset.Resource) []*asset.Resource {
+	reachable := make(map[string]struct{})
+	for _, resource := range Closure(roots) {
+		reachable[resource.ID()] = struct{}{}
+	}
+	var result []*asset.Resource
+	for _, resource := range resources {
+		if _, ok := reachable[resource.ID()]; !ok {
+			result = append(result, resource)
+		}
+	}
+	SortByName(result)
+	return result
+}
@@ -0,0 +1,349 @@
+package dsledit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DSLPackage is the import path of the package whose calls are edited.
+const DSLPackage = "github.com/mokiat/lacking/game/asset/dsl"
+
+var (
+	// ErrNotFound indicates that the source does not contain the call.
+	ErrNotFound = errors.New("call not found")
+
+	// ErrAmbiguous indicates that the source contains the call more than
+	// once, so it is not clear which one should be edited.
+	ErrAmbiguous = errors.New("call is ambiguous")
+)
+
+// Call identifies a call to a function of the DSL package whose first
+// argument is a constant name, such as dsl.CreateNode("Wheel", ...).
+type Call struct {
+	Function string
+	Name     string
+}
+
+// String returns the call as it would appear in the source.
+func (c Call) String() string {
+	if c.Name == "" {
+		return fmt.Sprintf("dsl.%s(...)", c.Function)
+	}
+	return fmt.Sprintf("dsl.%s(%q, ...)", c.Function, c.Name)
+}
+
+// Operation is a DSL operation that is passed as an argument to a Call.
+type Operation struct {
+
+	// Call identifies the operation. An argument of the edited call that
+	// matches it is replaced, otherwise the operation is appended.
+	Call Call
+
+	// Code is the Go expression of the operation. It has to refer to the
+	// DSL package as dsl and to the packages in Imports by their names.
+	Code string
+
+	// Imports are the import paths of the packages, other than the DSL
+	// package, that are used by Code.
+	Imports []string
+}
+
+// Apply sets the specified operations on the single call in the Go sources
+// of the project in the specified directory that matches the target. It
+// returns the path of the file that was changed.
+//
+// NOTE: The sources of resources are Go code that is run by the packer, so
+// only the places where that code calls the DSL directly, with a constant
+// name, can be edited. Anything else, such as nodes that come from a glTF
+// file, has to be changed by hand, which is why the DSL of the operations
+// should also be offered to the user.
+func Apply(projectDir string, target Call, operations []Operation) (string, error) {
+	var matches []match
+	err := filepath.WalkDir(projectDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if filePath != projectDir && isIgnoredDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(filePath, ".go") || strings.HasSuffix(filePath, "_test.go") {
+			return nil
+		}
+		fileMatches, err := findCalls(filePath, target)
+		if err != nil {
+			return err
+		}
+		matches = append(matches, fileMatches...)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error searching sources: %w", err)
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrNotFound, target)
+	case 1:
+	default:
+		return "", fmt.Errorf("%w: %s is used %d times", ErrAmbiguous, target, len(matches))
+	}
+
+	match := matches[0]
+	if err := match.apply(operations); err != nil {
+		return "", fmt.Errorf("error editing %q: %w", match.path, err)
+	}
+	return match.path, nil
+}
+
+func isIgnoredDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata"
+}
+
+// match is a call in a source file that matches the target of an Apply.
+type match struct {
+	path    string
+	source  []byte
+	fileSet *token.FileSet
+	file    *ast.File
+	dslName string
+	call    *ast.CallExpr
+}
+
+func findCalls(filePath string, target Call) ([]match, error) {
+	source, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", filePath, err)
+	}
+	if !bytes.Contains(source, []byte(target.Function)) {
+		return nil, nil
+	}
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, filePath, source, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %q: %w", filePath, err)
+	}
+	dslName, ok := importName(file, DSLPackage)
+	if !ok {
+		return nil, nil
+	}
+
+	var result []match
+	ast.Inspect(file, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok && isCall(call, dslName, target) {
+			result = append(result, match{
+				path:    filePath,
+				source:  source,
+				fileSet: fileSet,
+				file:    file,
+				dslName: dslName,
+				call:    call,
+			})
+		}
+		return true
+	})
+	return result, nil
+}
+
+func (m match) apply(operations []Operation) error {
+	var edits []edit
+	var missingImports []string
+	appendOffset, appendPrefix, appendSuffix := m.appendPosition()
+	for _, operation := range operations {
+		code := m.qualify(operation.Code, DSLPackage, m.dslName)
+		for _, importPath := range operation.Imports {
+			if name, ok := importName(m.file, importPath); ok {
+				code = m.qualify(code, importPath, name)
+			} else if !slices.Contains(missingImports, importPath) {
+				missingImports = append(missingImports, importPath)
+			}
+		}
+
+		index := slices.IndexFunc(m.call.Args[1:], func(arg ast.Expr) bool {
+			argCall, ok := arg.(*ast.CallExpr)
+			return ok && isCall(argCall, m.dslName, operation.Call)
+		})
+		if index >= 0 {
+			arg := m.call.Args[index+1]
+			edits = append(edits, edit{
+				start: m.offset(arg.Pos()),
+				end:   m.offset(arg.End()),
+				text:  code,
+			})
+		} else {
+			edits = append(edits, edit{
+				start: appendOffset,
+				end:   appendOffset,
+				text:  appendPrefix + code + appendSuffix,
+			})
+		}
+	}
+	if len(missingImports) > 0 {
+		edits = append(edits, m.importEdit(missingImports))
+	}
+
+	source := applyEdits(m.source, edits)
+	formatted, err := format.Source(source)
+	if err != nil {
+		return fmt.Errorf("error formatting source: %w", err)
+	}
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return fmt.Errorf("error checking file: %w", err)
+	}
+	if err := os.WriteFile(m.path, formatted, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+	return nil
+}
+
+// appendPosition returns where new arguments are inserted into the call
+// and how they need to be separated from the existing ones. Calls that
+// list their arguments on separate lines end with a comma, which new
+// arguments need to follow.
+func (m match) appendPosition() (int, string, string) {
+	lastArg := m.call.Args[len(m.call.Args)-1]
+	offset := m.offset(lastArg.End())
+	tail := m.source[offset:m.offset(m.call.Rparen)]
+	if comma := bytes.IndexByte(tail, ','); comma >= 0 {
+		return offset + comma + 1, "\n", ","
+	}
+	return offset, ", ", ""
+}
+
+// importEdit adds the specified imports to the first import declaration
+// of the file, or as a new declaration after the package clause.
+func (m match) importEdit(importPaths []string) edit {
+	var lines []string
+	for _, importPath := range importPaths {
+		lines = append(lines, strconv.Quote(importPath))
+	}
+	for _, decl := range m.file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		if genDecl.Lparen.IsValid() {
+			offset := m.offset(genDecl.Rparen)
+			return edit{
+				start: offset,
+				end:   offset,
+				text:  strings.Join(lines, "\n") + "\n",
+			}
+		}
+		offset := m.offset(genDecl.End())
+		return edit{
+			start: offset,
+			end:   offset,
+			text:  "\nimport " + strings.Join(lines, "\nimport ") + "\n",
+		}
+	}
+	offset := m.offset(m.file.Name.End())
+	return edit{
+		start: offset,
+		end:   offset,
+		text:  "\n\nimport " + strings.Join(lines, "\nimport ") + "\n",
+	}
+}
+
+// qualify changes the references of the code to the package with the
+// specified path to use the name under which the file imports it.
+func (m match) qualify(code, importPath, name string) string {
+	defaultName := path.Base(importPath)
+	if name == defaultName {
+		return code
+	}
+	return strings.ReplaceAll(code, defaultName+".", name+".")
+}
+
+func (m match) offset(pos token.Pos) int {
+	return m.fileSet.Position(pos).Offset
+}
+
+type edit struct {
+	start int
+	end   int
+	text  string
+}
+
+// applyEdits applies the edits from the end of the source backwards, so
+// that the offsets of the remaining ones stay valid. Edits at the same
+// offset end up in the order in which they are specified.
+func applyEdits(source []byte, edits []edit) []byte {
+	result := slices.Clone(source)
+	for _, edit := range slices.Backward(sortedEdits(edits)) {
+		result = slices.Replace(result, edit.start, edit.end, []byte(edit.text)...)
+	}
+	return result
+}
+
+func sortedEdits(edits []edit) []edit {
+	result := slices.Clone(edits)
+	slices.SortStableFunc(result, func(a, b edit) int {
+		return a.start - b.start
+	})
+	return result
+}
+
+func importName(file *ast.File, importPath string) (string, bool) {
+	for _, spec := range file.Imports {
+		specPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || specPath != importPath {
+			continue
+		}
+		if spec.Name == nil {
+			return path.Base(importPath), true
+		}
+		if spec.Name.Name == "_" || spec.Name.Name == "." {
+			return "", false
+		}
+		return spec.Name.Name, true
+	}
+	return "", false
+}
+
+func isCall(call *ast.CallExpr, dslName string, target Call) bool {
+	fun := call.Fun
+	// NOTE: Generic functions can be called with explicit type arguments,
+	// such as dsl.BindProperty[float32](...).
+	switch expr := fun.(type) {
+	case *ast.IndexExpr:
+		fun = expr.X
+	case *ast.IndexListExpr:
+		fun = expr.X
+	}
+	selector, ok := fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != target.Function {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	if !ok || pkg.Name != dslName {
+		return false
+	}
+	if target.Name == "" {
+		return true
+	}
+	if len(call.Args) == 0 {
+		return false
+	}
+	literal, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return false
+	}
+	name, err := strconv.Unquote(literal.Value)
+	return err == nil && name == target.Name
+}
@@ -0,0 +1,155 @@
+package dsledit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testSource = `package main
+
+import (
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset/dsl"
+)
+
+var car = dsl.CreateModel(
+	dsl.AddNode(dsl.CreateNode("Body",
+		dsl.SetTranslation(dsl.Const(dprec.NewVec3(0, 1, 0))),
+	)),
+	dsl.AddNode(dsl.CreateNode("Wheel")),
+	dsl.AddNode(dsl.CreateNode("Spare")),
+	dsl.AddNode(dsl.CreateNode("Spare")),
+)
+`
+
+func writeTestSource(t *testing.T, source string) (string, string) {
+	t.Helper()
+	projectDir := t.TempDir()
+	path := filepath.Join(projectDir, "resources", "car.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("error writing source: %v", err)
+	}
+	return projectDir, path
+}
+
+func readTestSource(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading source: %v", err)
+	}
+	return string(data)
+}
+
+func TestApplyReplacesAndAppendsOperations(t *testing.T) {
+	projectDir, path := writeTestSource(t, testSource)
+
+	operations := []Operation{
+		{
+			Call:    Call{Function: "SetTranslation"},
+			Code:    "dsl.SetTranslation(dsl.Const(dprec.NewVec3(0, 2, 0)))",
+			Imports: []string{"github.com/mokiat/gomath/dprec"},
+		},
+		{
+			Call:    Call{Function: "SetScale"},
+			Code:    "dsl.SetScale(dsl.Const(dprec.NewVec3(2, 2, 2)))",
+			Imports: []string{"github.com/mokiat/gomath/dprec"},
+		},
+	}
+	editedPath, err := Apply(projectDir, Call{Function: "CreateNode", Name: "Body"}, operations)
+	if err != nil {
+		t.Fatalf("error applying: %v", err)
+	}
+	if editedPath != path {
+		t.Fatalf("expected %q to be edited, got %q", path, editedPath)
+	}
+
+	operations = []Operation{
+		{
+			Call:    Call{Function: "BindProperty", Name: "color"},
+			Code:    `dsl.BindProperty("color", dsl.Const(sprec.NewVec4(1, 0, 0, 1)))`,
+			Imports: []string{"github.com/mokiat/gomath/sprec"},
+		},
+		{
+			Call: Call{Function: "SetCulling"},
+			Code: "dsl.SetCulling(dsl.Const(mdl.CullModeNone))",
+		},
+	}
+	if _, err := Apply(projectDir, Call{Function: "CreateNode", Name: "Wheel"}, operations); err != nil {
+		t.Fatalf("error applying: %v", err)
+	}
+
+	expected := `package main
+
+import (
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset/dsl"
+)
+
+var car = dsl.CreateModel(
+	dsl.AddNode(dsl.CreateNode("Body",
+		dsl.SetTranslation(dsl.Const(dprec.NewVec3(0, 2, 0))),
+		dsl.SetScale(dsl.Const(dprec.NewVec3(2, 2, 2))),
+	)),
+	dsl.AddNode(dsl.CreateNode("Wheel", dsl.BindProperty("color", dsl.Const(sprec.NewVec4(1, 0, 0, 1))), dsl.SetCulling(dsl.Const(mdl.CullModeNone)))),
+	dsl.AddNode(dsl.CreateNode("Spare")),
+	dsl.AddNode(dsl.CreateNode("Spare")),
+)
+`
+	if source := readTestSource(t, path); source != expected {
+		t.Fatalf("unexpected source:\n%s", source)
+	}
+}
+
+func TestApplyUsesImportNames(t *testing.T) {
+	projectDir, path := writeTestSource(t, `package main
+
+import asset "github.com/mokiat/lacking/game/asset/dsl"
+
+var wheel = asset.CreateNode("Wheel")
+`)
+
+	operations := []Operation{
+		{
+			Call:    Call{Function: "SetScale"},
+			Code:    "dsl.SetScale(dsl.Const(dprec.NewVec3(2, 2, 2)))",
+			Imports: []string{"github.com/mokiat/gomath/dprec"},
+		},
+	}
+	if _, err := Apply(projectDir, Call{Function: "CreateNode", Name: "Wheel"}, operations); err != nil {
+		t.Fatalf("error applying: %v", err)
+	}
+
+	expected := `package main
+
+import asset "github.com/mokiat/lacking/game/asset/dsl"
+import "github.com/mokiat/gomath/dprec"
+
+var wheel = asset.CreateNode("Wheel", asset.SetScale(asset.Const(dprec.NewVec3(2, 2, 2))))
+`
+	if source := readTestSource(t, path); source != expected {
+		t.Fatalf("unexpected source:\n%s", source)
+	}
+}
+
+func TestApplyRequiresSingleCall(t *testing.T) {
+	projectDir, path := writeTestSource(t, testSource)
+
+	_, err := Apply(projectDir, Call{Function: "CreateNode", Name: "Spare"}, nil)
+	if !errors.Is(err, ErrAmbiguous) {
+		t.Fatalf("expected ambiguous error, got %v", err)
+	}
+	_, err = Apply(projectDir, Call{Function: "CreateNode", Name: "Missing"}, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if source := readTestSource(t, path); source != testSource {
+		t.Fatalf("expected source to be unchanged:\n%s", source)
+	}
+}
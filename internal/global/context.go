@@ -1,6 +1,9 @@
 package global
 
 import (
+	"github.com/mokiat/lacking-studio/internal/config"
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking-studio/internal/storage"
 	"github.com/mokiat/lacking-studio/internal/viewport"
 	"github.com/mokiat/lacking/game"
 	"github.com/mokiat/lacking/game/asset"
@@ -8,8 +11,14 @@ import (
 )
 
 type Context struct {
+	ProjectDir string
+	Project    config.Project
+	Logs       *logs.Buffer
 	EventBus   *mvc.EventBus
 	Registry   *asset.Registry
+	Overrides  *storage.OverrideStorage
 	GameEngine *game.Engine
 	CommonData *viewport.CommonData
+
+	ReleaseQueue *viewport.ReleaseQueue
 }
@@ -1,14 +1,21 @@
 package global
 
 import (
+	"github.com/mokiat/lacking-studio/internal/config"
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking-studio/internal/storage"
 	"github.com/mokiat/lacking-studio/internal/viewport"
 	"github.com/mokiat/lacking/app"
 	"github.com/mokiat/lacking/game"
 )
 
-func NewController(gameController *game.Controller) *Controller {
+func NewController(projectDir string, project config.Project, logBuffer *logs.Buffer, overrides *storage.OverrideStorage, gameController *game.Controller) *Controller {
 	return &Controller{
 		Controller: gameController,
+		projectDir: projectDir,
+		project:    project,
+		logBuffer:  logBuffer,
+		overrides:  overrides,
 	}
 }
 
@@ -17,7 +24,12 @@ var _ app.Controller = (*Controller)(nil)
 type Controller struct {
 	*game.Controller
 
-	commonData *viewport.CommonData
+	projectDir   string
+	project      config.Project
+	logBuffer    *logs.Buffer
+	overrides    *storage.OverrideStorage
+	commonData   *viewport.CommonData
+	releaseQueue *viewport.ReleaseQueue
 }
 
 func (c *Controller) OnCreate(window app.Window) {
@@ -28,14 +40,46 @@ func (c *Controller) OnCreate(window app.Window) {
 
 	c.commonData = viewport.NewCommonData(gfxEngine)
 	c.commonData.Create()
+
+	c.releaseQueue = viewport.NewReleaseQueue(window.RenderAPI())
 }
 
 func (c *Controller) OnDestroy(window app.Window) {
+	c.releaseQueue.Flush()
 	c.commonData.Delete()
 
 	c.Controller.OnDestroy(window)
 }
 
+func (c *Controller) OnRender(window app.Window) {
+	c.releaseQueue.Update()
+
+	c.Controller.OnRender(window)
+}
+
+func (c *Controller) ProjectDir() string {
+	return c.projectDir
+}
+
+// Project returns the configuration of the project.
+func (c *Controller) Project() config.Project {
+	return c.project
+}
+
+func (c *Controller) Logs() *logs.Buffer {
+	return c.logBuffer
+}
+
+// Overrides returns the storage through which the content of the resources
+// in the registry can be overridden.
+func (c *Controller) Overrides() *storage.OverrideStorage {
+	return c.overrides
+}
+
 func (c *Controller) CommonData() *viewport.CommonData {
 	return c.commonData
 }
+
+func (c *Controller) ReleaseQueue() *viewport.ReleaseQueue {
+	return c.releaseQueue
+}
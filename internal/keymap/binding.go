@@ -0,0 +1,92 @@
+package keymap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mokiat/lacking/ui"
+)
+
+var keyCodesByName = func() map[string]ui.KeyCode {
+	result := make(map[string]ui.KeyCode)
+	for code := ui.KeyCodeEscape; code <= ui.KeyCodeF12; code++ {
+		result[code.String()] = code
+	}
+	return result
+}()
+
+var modifiersByName = map[string]ui.KeyModifier{
+	"CTRL":    ui.KeyModifierControl,
+	"CONTROL": ui.KeyModifierControl,
+	"SHIFT":   ui.KeyModifierShift,
+	"ALT":     ui.KeyModifierAlt,
+	"OPTION":  ui.KeyModifierAlt,
+	"SUPER":   ui.KeyModifierSuper,
+	"CMD":     ui.KeyModifierSuper,
+}
+
+// NewBinding creates a new Binding for the specified key code and
+// modifiers.
+func NewBinding(code ui.KeyCode, modifiers ...ui.KeyModifier) Binding {
+	return Binding{
+		Code:      code,
+		Modifiers: ui.KeyModifiers(modifiers...),
+	}
+}
+
+// ParseBinding parses a key combination in the form "Ctrl+Shift+Z".
+// Modifier and key names are case-insensitive.
+func ParseBinding(text string) (Binding, error) {
+	segments := strings.Split(strings.ToUpper(strings.TrimSpace(text)), "+")
+	keyName := strings.TrimSpace(segments[len(segments)-1])
+	code, ok := keyCodesByName[keyName]
+	if !ok {
+		return Binding{}, fmt.Errorf("unknown key %q in binding %q", keyName, text)
+	}
+	var modifiers []ui.KeyModifier
+	for _, segment := range segments[:len(segments)-1] {
+		modifier, ok := modifiersByName[strings.TrimSpace(segment)]
+		if !ok {
+			return Binding{}, fmt.Errorf("unknown modifier %q in binding %q", segment, text)
+		}
+		modifiers = append(modifiers, modifier)
+	}
+	return NewBinding(code, modifiers...), nil
+}
+
+// Binding represents a key combination that triggers an action.
+type Binding struct {
+	Code      ui.KeyCode
+	Modifiers ui.KeyModifierSet
+}
+
+// Matches returns whether the specified keyboard event corresponds to
+// this binding. The state of Caps Lock is ignored.
+func (b Binding) Matches(event ui.KeyboardEvent) bool {
+	modifiers := event.Modifiers &^ ui.KeyModifiers(ui.KeyModifierCapsLock)
+	return event.Code == b.Code && modifiers == b.Modifiers
+}
+
+// String returns a human-readable representation of this binding that
+// can be parsed back with ParseBinding.
+func (b Binding) String() string {
+	var segments []string
+	if b.Modifiers.Contains(ui.KeyModifierControl) {
+		segments = append(segments, "Ctrl")
+	}
+	if b.Modifiers.Contains(ui.KeyModifierSuper) {
+		segments = append(segments, "Cmd")
+	}
+	if b.Modifiers.Contains(ui.KeyModifierAlt) {
+		segments = append(segments, "Alt")
+	}
+	if b.Modifiers.Contains(ui.KeyModifierShift) {
+		segments = append(segments, "Shift")
+	}
+	keyName := b.Code.String()
+	if len(keyName) > 1 && !strings.HasPrefix(keyName, "F") {
+		keyName = keyName[:1] + strings.ToLower(keyName[1:])
+	}
+	segments = append(segments, keyName)
+	return strings.Join(segments, "+")
+}
@@ -0,0 +1,124 @@
+package keymap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mokiat/lacking/ui"
+)
+
+const (
+	configDir  = "lacking-studio"
+	configFile = "keymap.json"
+)
+
+// Action identifies an operation that can be triggered through a key
+// binding.
+type Action string
+
+// Entry describes an action along with the key bindings that trigger it.
+type Entry struct {
+	Action      Action
+	Description string
+	Bindings    []Binding
+}
+
+// New creates a new Keymap with the specified entries. The order of the
+// entries is preserved when listing them.
+func New(entries ...Entry) *Keymap {
+	return &Keymap{
+		entries: entries,
+	}
+}
+
+// Keymap is a registry of actions and the key bindings that trigger them.
+type Keymap struct {
+	entries []Entry
+}
+
+// Entries returns all registered actions and their bindings.
+func (k *Keymap) Entries() []Entry {
+	return k.entries
+}
+
+// Bindings returns the key bindings for the specified action.
+func (k *Keymap) Bindings(action Action) []Binding {
+	if entry := k.entry(action); entry != nil {
+		return entry.Bindings
+	}
+	return nil
+}
+
+// SetBindings replaces the key bindings of the specified action.
+func (k *Keymap) SetBindings(action Action, bindings []Binding) error {
+	entry := k.entry(action)
+	if entry == nil {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	entry.Bindings = bindings
+	return nil
+}
+
+// Match returns the action that is bound to the specified keyboard event.
+func (k *Keymap) Match(event ui.KeyboardEvent) (Action, bool) {
+	for _, entry := range k.entries {
+		for _, binding := range entry.Bindings {
+			if binding.Matches(event) {
+				return entry.Action, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Load applies the bindings from the specified keymap file on top of the
+// current ones. The file is a JSON object that maps action names to lists
+// of key combinations. A missing file is not considered an error.
+func (k *Keymap) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("error reading keymap file: %w", err)
+	}
+	var overrides map[Action][]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("error decoding keymap file: %w", err)
+	}
+	for action, texts := range overrides {
+		bindings := make([]Binding, len(texts))
+		for i, text := range texts {
+			bindings[i], err = ParseBinding(text)
+			if err != nil {
+				return fmt.Errorf("error parsing binding of action %q: %w", action, err)
+			}
+		}
+		if err := k.SetBindings(action, bindings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *Keymap) entry(action Action) *Entry {
+	for i := range k.entries {
+		if k.entries[i].Action == action {
+			return &k.entries[i]
+		}
+	}
+	return nil
+}
+
+// DefaultPath returns the location of the user keymap file.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error determining config dir: %w", err)
+	}
+	return filepath.Join(dir, configDir, configFile), nil
+}
@@ -0,0 +1,251 @@
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxEntries is the number of entries that a Buffer keeps before it starts
+// dropping the oldest ones.
+const MaxEntries = 5000
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+// String returns a human-readable name of this level.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "Debug"
+	case LevelInfo:
+		return "Info"
+	case LevelWarn:
+		return "Warning"
+	case LevelError:
+		return "Error"
+	default:
+		return "Unknown"
+	}
+}
+
+// Levels returns all available levels, from the least to the most severe.
+func Levels() []Level {
+	return []Level{
+		LevelDebug,
+		LevelInfo,
+		LevelWarn,
+		LevelError,
+	}
+}
+
+// Entry is a single line of output.
+type Entry struct {
+	// ID increases with every entry, so it can be used to refer to the
+	// entries that were written after a certain point.
+	ID uint64
+
+	Time      time.Time
+	Level     Level
+	Source    string
+	Namespace string
+	Message   string
+}
+
+// String returns the entry as a single line of text.
+func (e Entry) String() string {
+	if e.Namespace != "" {
+		return fmt.Sprintf("%s [%s] [%s] %s", e.Time.Format(time.TimeOnly), e.Level, e.Namespace, e.Message)
+	}
+	return fmt.Sprintf("%s [%s] [%s] %s", e.Time.Format(time.TimeOnly), e.Level, e.Source, e.Message)
+}
+
+// logLinePattern matches the lines that are written by the engine logger,
+// optionally prefixed with a date and time.
+var logLinePattern = regexp.MustCompile(`^(?:\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} )?\[ (DEBUG|INFO|WARN|ERROR) \]\s*\[ ([^\]]*) \] (.*)$`)
+
+// NewBuffer creates a new empty Buffer.
+func NewBuffer() *Buffer {
+	return &Buffer{
+		nextID:   1,
+		terminal: os.Stderr,
+	}
+}
+
+// Buffer collects log entries from the engine logger and from external
+// processes. It is safe for concurrent use.
+type Buffer struct {
+	mu       sync.Mutex
+	entries  []Entry
+	nextID   uint64
+	onChange func()
+	terminal io.Writer
+	syncs    map[string]chan struct{}
+
+	subscribers      map[uint64]func(Entry)
+	nextSubscriberID uint64
+}
+
+// SetOnChange specifies a function that is called whenever entries are
+// added or removed. It can be called from any goroutine.
+func (b *Buffer) SetOnChange(onChange func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onChange = onChange
+}
+
+// Subscribe registers a function that is called with every entry that is
+// added, until the returned function is called. The function can be called
+// from any goroutine.
+func (b *Buffer) Subscribe(subscriber func(Entry)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[uint64]func(Entry))
+	}
+	id := b.nextSubscriberID
+	b.nextSubscriberID++
+	b.subscribers[id] = subscriber
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// Entries returns a copy of the entries in the buffer.
+func (b *Buffer) Entries() []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	result := make([]Entry, len(b.entries))
+	copy(result, b.entries)
+	return result
+}
+
+// NextID returns the ID that the next entry will receive.
+func (b *Buffer) NextID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nextID
+}
+
+// Clear removes all entries.
+func (b *Buffer) Clear() {
+	b.mu.Lock()
+	b.entries = nil
+	onChange := b.onChange
+	b.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
+}
+
+// Append adds a line of output from the specified source. Lines that are
+// formatted by the engine logger keep their own level and namespace,
+// otherwise the specified level is used.
+func (b *Buffer) Append(source string, level Level, line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return
+	}
+	entry := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Source:  source,
+		Message: line,
+	}
+	if match := logLinePattern.FindStringSubmatch(line); match != nil {
+		entry.Level = parseLevel(match[1])
+		entry.Namespace = match[2]
+		entry.Message = match[3]
+	}
+
+	b.mu.Lock()
+	entry.ID = b.nextID
+	b.nextID++
+	b.entries = append(b.entries, entry)
+	if overflow := len(b.entries) - MaxEntries; overflow > 0 {
+		b.entries = append(b.entries[:0], b.entries[overflow:]...)
+	}
+	onChange := b.onChange
+	subscribers := make([]func(Entry), 0, len(b.subscribers))
+	for _, subscriber := range b.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	b.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(entry)
+	}
+	if onChange != nil {
+		onChange()
+	}
+}
+
+// Writer returns a writer that adds every line that is written to it as
+// an entry from the specified source.
+func (b *Buffer) Writer(source string, level Level) io.Writer {
+	return &lineWriter{
+		buffer: b,
+		source: source,
+		level:  level,
+	}
+}
+
+// Terminal returns a writer to the standard error of the process that is
+// not captured by the buffer.
+func (b *Buffer) Terminal() io.Writer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.terminal
+}
+
+func parseLevel(text string) Level {
+	switch text {
+	case "DEBUG":
+		return LevelDebug
+	case "WARN":
+		return LevelWarn
+	case "ERROR":
+		return LevelError
+	default:
+		return LevelInfo
+	}
+}
+
+type lineWriter struct {
+	mu      sync.Mutex
+	buffer  *Buffer
+	source  string
+	level   Level
+	partial []byte
+}
+
+func (w *lineWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, data...)
+	for {
+		index := bytes.IndexByte(w.partial, '\n')
+		if index < 0 {
+			break
+		}
+		w.buffer.Append(w.source, w.level, string(w.partial[:index]))
+		w.partial = w.partial[index+1:]
+	}
+	return len(data), nil
+}
@@ -0,0 +1,108 @@
+package logs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// SourceLog is the source of entries that were written to the standard
+// error of the process, which is where the engine logger writes to.
+const SourceLog = "log"
+
+// syncMarker prefixes the lines that are written by Sync. Such lines are
+// neither stored nor forwarded.
+const syncMarker = "\x00logs-sync:"
+
+var syncCounter atomic.Uint64
+
+// CaptureStderr redirects the standard error of the process through the
+// buffer. Everything is still forwarded to the original standard error.
+//
+// NOTE: The engine logger holds on to the standard error file from its
+// initialization, so the redirection has to happen at the descriptor
+// level.
+func (b *Buffer) CaptureStderr() error {
+	original, err := duplicateStderr()
+	if err != nil {
+		return fmt.Errorf("error duplicating stderr: %w", err)
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		original.Close()
+		return fmt.Errorf("error creating pipe: %w", err)
+	}
+	if err := redirectStderr(writer); err != nil {
+		original.Close()
+		reader.Close()
+		writer.Close()
+		return fmt.Errorf("error redirecting stderr: %w", err)
+	}
+
+	// NOTE: A crashing process can no longer read the pipe, so crash
+	// reports need to go to the original standard error directly.
+	if err := debug.SetCrashOutput(original, debug.CrashOptions{}); err != nil {
+		fmt.Fprintf(original, "Error setting crash output: %v\n", err)
+	}
+
+	b.mu.Lock()
+	b.terminal = original
+	b.syncs = make(map[string]chan struct{})
+	b.mu.Unlock()
+
+	go b.forward(reader, original)
+	return nil
+}
+
+func (b *Buffer) forward(reader io.Reader, terminal io.Writer) {
+	bufReader := bufio.NewReader(reader)
+	for {
+		line, err := bufReader.ReadString('\n')
+		if strings.HasPrefix(line, syncMarker) {
+			b.completeSync(line)
+			continue
+		}
+		if line != "" {
+			io.WriteString(terminal, line)
+			b.Append(SourceLog, LevelInfo, line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Sync waits until everything that was written to the standard error so
+// far has been added to the buffer. It does nothing if the standard error
+// is not captured.
+func (b *Buffer) Sync() {
+	b.mu.Lock()
+	if b.syncs == nil {
+		b.mu.Unlock()
+		return
+	}
+	marker := syncMarker + strconv.FormatUint(syncCounter.Add(1), 10) + "\n"
+	done := make(chan struct{})
+	b.syncs[marker] = done
+	b.mu.Unlock()
+
+	if _, err := io.WriteString(os.Stderr, marker); err != nil {
+		return
+	}
+	<-done
+}
+
+func (b *Buffer) completeSync(marker string) {
+	b.mu.Lock()
+	done, ok := b.syncs[marker]
+	delete(b.syncs, marker)
+	b.mu.Unlock()
+	if ok {
+		close(done)
+	}
+}
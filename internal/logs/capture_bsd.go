@@ -0,0 +1,20 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package logs
+
+import (
+	"os"
+	"syscall"
+)
+
+func duplicateStderr() (*os.File, error) {
+	fd, err := syscall.Dup(int(os.Stderr.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(fd), "/dev/stderr"), nil
+}
+
+func redirectStderr(file *os.File) error {
+	return syscall.Dup2(int(file.Fd()), int(os.Stderr.Fd()))
+}
@@ -0,0 +1,20 @@
+package logs
+
+import (
+	"os"
+	"syscall"
+)
+
+func duplicateStderr() (*os.File, error) {
+	fd, err := syscall.Dup(int(os.Stderr.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(fd), "/dev/stderr"), nil
+}
+
+// NOTE: Dup2 is not available on all Linux architectures, so Dup3 is
+// used instead.
+func redirectStderr(file *os.File) error {
+	return syscall.Dup3(int(file.Fd()), int(os.Stderr.Fd()), 0)
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package logs
+
+import (
+	"errors"
+	"os"
+)
+
+func duplicateStderr() (*os.File, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func redirectStderr(file *os.File) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,140 @@
+package pack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EnvDiagnosticsFile is the environment variable that can be set to the
+// path of a file where the pack command writes its diagnostics in JSON.
+//
+// NOTE: An environment variable is used, since the pack command is usually
+// started through a project-specific task that does not forward flags.
+const EnvDiagnosticsFile = "STUDIO_DIAGNOSTICS_FILE"
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Severity indicates how serious a Diagnostic is.
+type Severity string
+
+// Diagnostic is a single problem that was encountered during packing.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+
+	// Model is the name of the model that the problem concerns, if known.
+	Model string `json:"model,omitempty"`
+
+	// Resource is the ID of the registry resource of the model, if known.
+	Resource string `json:"resource,omitempty"`
+
+	// File is the source file that caused the problem, if known.
+	File string `json:"file,omitempty"`
+
+	// Line is the line within File, if known.
+	Line int `json:"line,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// String returns the diagnostic in the common file:line: severity: message
+// form.
+func (d Diagnostic) String() string {
+	var builder strings.Builder
+	if d.File != "" {
+		builder.WriteString(d.File)
+		if d.Line > 0 {
+			builder.WriteString(":" + strconv.Itoa(d.Line))
+		}
+		builder.WriteString(": ")
+	}
+	builder.WriteString(string(d.Severity) + ": ")
+	if d.Model != "" {
+		builder.WriteString(fmt.Sprintf("model %q: ", d.Model))
+	}
+	builder.WriteString(d.Message)
+	return builder.String()
+}
+
+// HasErrors returns whether any of the diagnostics is an error.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteFile stores the diagnostics as JSON in the specified file.
+func WriteFile(path string, diagnostics []Diagnostic) error {
+	data, err := json.MarshalIndent(diagnostics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding diagnostics: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0664); err != nil {
+		return fmt.Errorf("error writing diagnostics file: %w", err)
+	}
+	return nil
+}
+
+// ReadFile loads diagnostics that were stored with WriteFile.
+func ReadFile(path string) ([]Diagnostic, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading diagnostics file: %w", err)
+	}
+	var diagnostics []Diagnostic
+	if err := json.Unmarshal(data, &diagnostics); err != nil {
+		return nil, fmt.Errorf("error decoding diagnostics: %w", err)
+	}
+	return diagnostics, nil
+}
+
+var (
+	// fileLinePattern matches references such as "models/door.go:42".
+	fileLinePattern = regexp.MustCompile(`([\w./\\-]+\.\w+):(\d+)`)
+
+	// quotedPattern matches quoted strings, which the DSL uses for paths.
+	quotedPattern = regexp.MustCompile(`"([^"]+)"`)
+)
+
+// sourceLocation finds the first file that is referenced by the message
+// and that exists in the project directory.
+func sourceLocation(projectDir, message string) (string, int) {
+	for _, match := range fileLinePattern.FindAllStringSubmatch(message, -1) {
+		if path, ok := projectFile(projectDir, match[1]); ok {
+			line, _ := strconv.Atoi(match[2])
+			return path, line
+		}
+	}
+	for _, match := range quotedPattern.FindAllStringSubmatch(message, -1) {
+		if path, ok := projectFile(projectDir, match[1]); ok {
+			return path, 0
+		}
+	}
+	return "", 0
+}
+
+func projectFile(projectDir, path string) (string, bool) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectDir, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+	// The path is made absolute, so that it can be opened from any
+	// working directory.
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	return path, true
+}
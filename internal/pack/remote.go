@@ -0,0 +1,151 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/mokiat/lacking/debug/log"
+)
+
+// RemotePath is the path on which a pack server accepts requests.
+const RemotePath = "/pack"
+
+// RemoteRequest is the body of a POST request to a pack server.
+type RemoteRequest struct {
+
+	// Model is the name of the model to pack or empty to pack all models.
+	Model string `json:"model,omitempty"`
+}
+
+// RemoteMessage is a JSON value in the response of a pack server. The
+// response is a stream of messages with the output of the pack, followed
+// by a single message with the result.
+type RemoteMessage struct {
+
+	// Output is a line of output of the pack.
+	Output string `json:"output,omitempty"`
+
+	// Result is set on the last message only.
+	Result *RemoteResponse `json:"result,omitempty"`
+}
+
+// RemoteResponse is the result of a pack on a pack server.
+type RemoteResponse struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+
+	// Error describes why packing failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// PackFunc packs the model with the specified name or all models if the
+// name is empty. Lines of output that are written to the writer are
+// streamed to the client.
+type PackFunc func(ctx context.Context, modelName string, output io.Writer) ([]Diagnostic, error)
+
+// NewHandler creates an http.Handler that serves pack requests with the
+// specified function. Requests are served one at a time, since packs of
+// the same project cannot run concurrently.
+func NewHandler(packFunc PackFunc) http.Handler {
+	return &handler{
+		packFunc: packFunc,
+	}
+}
+
+type handler struct {
+	mu       sync.Mutex
+	packFunc PackFunc
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != RemotePath {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var request RemoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	stream := &messageStream{
+		writer:  w,
+		encoder: json.NewEncoder(w),
+	}
+
+	h.mu.Lock()
+	diagnostics, err := h.packFunc(r.Context(), request.Model, stream)
+	h.mu.Unlock()
+
+	response := &RemoteResponse{
+		Diagnostics: diagnostics,
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	if err := stream.Close(response); err != nil {
+		log.Warn("Error writing pack response: %v", err)
+	}
+}
+
+// messageStream writes each line that is written to it as a RemoteMessage.
+// It is safe for concurrent use, since output can be produced by other
+// goroutines of the pack.
+type messageStream struct {
+	mu      sync.Mutex
+	writer  http.ResponseWriter
+	encoder *json.Encoder
+	partial []byte
+	closed  bool
+}
+
+func (s *messageStream) Write(data []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return len(data), nil
+	}
+
+	s.partial = append(s.partial, data...)
+	for {
+		index := bytes.IndexByte(s.partial, '\n')
+		if index < 0 {
+			break
+		}
+		line := string(bytes.TrimRight(s.partial[:index], "\r"))
+		s.partial = s.partial[index+1:]
+		if line == "" {
+			continue
+		}
+		if err := s.encoder.Encode(RemoteMessage{Output: line}); err != nil {
+			return 0, err
+		}
+	}
+	if flusher, ok := s.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return len(data), nil
+}
+
+// Close writes the remaining output and the result message. Output that
+// is written afterwards is dropped.
+func (s *messageStream) Close(response *RemoteResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if line := string(bytes.TrimSpace(s.partial)); line != "" {
+		if err := s.encoder.Encode(RemoteMessage{Output: line}); err != nil {
+			return err
+		}
+	}
+	return s.encoder.Encode(RemoteMessage{Result: response})
+}
@@ -0,0 +1,113 @@
+package pack
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/asset/dsl"
+)
+
+// modelLogPattern matches the engine log messages that concern a model.
+var modelLogPattern = regexp.MustCompile(`^Model "([^"]+)"`)
+
+// Run packs the specified models, or all models if none are specified,
+// and returns the problems that were encountered.
+//
+// If a log buffer is specified, any warnings and errors that are logged
+// during packing are reported as diagnostics as well.
+//
+// NOTE: The DSL stops at the first error and does not expose the models
+// that are declared, so when it fails, the models are packed once more one
+// at a time, in order to attribute the errors to them.
+func Run(registry *asset.Registry, projectDir string, modelNames []string, logBuffer *logs.Buffer) []Diagnostic {
+	var logID uint64
+	if logBuffer != nil {
+		logID = logBuffer.NextID()
+	}
+
+	var diagnostics []Diagnostic
+	if err := dsl.Run(registry, modelNames); err != nil {
+		names := modelNames
+		if len(names) == 0 {
+			for _, resource := range registry.Resources() {
+				names = append(names, resource.Name())
+			}
+		}
+		for _, name := range names {
+			if modelErr := dsl.Run(registry, []string{name}); modelErr != nil {
+				diagnostics = append(diagnostics, errorDiagnostic(registry, projectDir, name, modelErr))
+			}
+		}
+		if len(diagnostics) == 0 {
+			diagnostics = append(diagnostics, errorDiagnostic(registry, projectDir, "", err))
+		}
+	}
+
+	if logBuffer != nil {
+		logBuffer.Sync()
+		for _, entry := range logBuffer.Entries() {
+			if entry.ID < logID || entry.Level < logs.LevelWarn {
+				continue
+			}
+			diagnostics = append(diagnostics, logDiagnostic(registry, projectDir, entry))
+		}
+	}
+	return diagnostics
+}
+
+// Error returns an error that summarizes the diagnostics or nil if there
+// are no errors among them.
+func Error(diagnostics []Diagnostic) error {
+	var errs []error
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == SeverityError {
+			errs = append(errs, errors.New(diagnostic.String()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func errorDiagnostic(registry *asset.Registry, projectDir, modelName string, err error) Diagnostic {
+	message := err.Error()
+	file, line := sourceLocation(projectDir, message)
+	return Diagnostic{
+		Severity: SeverityError,
+		Model:    modelName,
+		Resource: resourceID(registry, modelName),
+		File:     file,
+		Line:     line,
+		Message:  message,
+	}
+}
+
+func logDiagnostic(registry *asset.Registry, projectDir string, entry logs.Entry) Diagnostic {
+	severity := SeverityWarning
+	if entry.Level >= logs.LevelError {
+		severity = SeverityError
+	}
+	var modelName string
+	if match := modelLogPattern.FindStringSubmatch(entry.Message); match != nil {
+		modelName = match[1]
+	}
+	file, line := sourceLocation(projectDir, entry.Message)
+	return Diagnostic{
+		Severity: severity,
+		Model:    modelName,
+		Resource: resourceID(registry, modelName),
+		File:     file,
+		Line:     line,
+		Message:  entry.Message,
+	}
+}
+
+func resourceID(registry *asset.Registry, name string) string {
+	if name == "" {
+		return ""
+	}
+	if resource := registry.ResourceByName(name); resource != nil {
+		return resource.ID()
+	}
+	return ""
+}
@@ -1,40 +1,124 @@
 package model
 
 import (
+	"context"
+	"io"
 	"os"
-	"os/exec"
-
+	"slices"
+
+	"github.com/mokiat/lacking-studio/internal/config"
+	"github.com/mokiat/lacking-studio/internal/keymap"
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking-studio/internal/pack"
+	"github.com/mokiat/lacking-studio/internal/storage"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/debug/log"
 	"github.com/mokiat/lacking/game/asset"
 	"github.com/mokiat/lacking/ui"
 	"github.com/mokiat/lacking/ui/mvc"
 	"github.com/mokiat/lacking/util/async"
 )
 
-func NewAppModel(window *ui.Window, eventBus *mvc.EventBus, registry *asset.Registry) *AppModel {
-	return &AppModel{
-		window:   window,
-		eventBus: eventBus,
-		registry: registry,
+// sourcePack is the log source of the output of the pack command.
+const sourcePack = "pack"
+
+func NewAppModel(window *ui.Window, eventBus *mvc.EventBus, registry *asset.Registry, overrides *storage.OverrideStorage, logBuffer *logs.Buffer, packer Packer, project config.Project, projectDir string) *AppModel {
+	settings, err := LoadSettings(projectDir, ProjectSettings(project.Viewport))
+	if err != nil {
+		log.Warn("Error loading settings: %v", err)
+	}
+
+	var openResources []*asset.Resource
+	for _, id := range settings.OpenResourceIDs {
+		if resource := registry.ResourceByID(id); resource != nil {
+			openResources = append(openResources, resource)
+		}
+	}
 
-		cameraSectionExpanded: true,
-		autoExposure:          false,
+	var selectedResource *asset.Resource
+	if settings.SelectedResourceID != "" {
+		selectedResource = registry.ResourceByID(settings.SelectedResourceID)
+	}
+	if selectedResource != nil && !slices.Contains(openResources, selectedResource) {
+		openResources = append(openResources, selectedResource)
+	}
 
-		sceneSectionExpanded: true,
-		showGrid:             true,
-		showAmbientLight:     true,
-		showDirectionalLight: true,
-		showSky:              true,
+	history := NewHistoryModel(eventBus)
+
+	assetsStorage, err := project.NewStorage(projectDir)
+	if err != nil {
+		log.Warn("Error creating storage for resource sizes: %v", err)
+		assetsStorage = nil
+	}
+
+	return &AppModel{
+		window:     window,
+		eventBus:   eventBus,
+		registry:   registry,
+		projectDir: projectDir,
+		logBuffer:  logBuffer,
+
+		openResources:    openResources,
+		selectedResource: selectedResource,
+		linkedCameras:    settings.LinkedCameras,
+		camera:           settings.Camera,
+		theme:            settings.Theme,
+
+		cameraSectionExpanded: settings.CameraSectionExpanded,
+		autoExposure:          settings.AutoExposure,
+
+		sceneSectionExpanded: settings.SceneSectionExpanded,
+		showGrid:             settings.ShowGrid,
+		showAmbientLight:     settings.ShowAmbientLight,
+		showDirectionalLight: settings.ShowDirectionalLight,
+		showSky:              settings.ShowSky,
+		showBounds:           settings.ShowBounds,
+		showNodeBounds:       settings.ShowNodeBounds,
+		showSkeleton:         settings.ShowSkeleton,
+		showNormals:          settings.ShowNormals,
+		showTangents:         settings.ShowTangents,
+
+		comparisonSectionExpanded: settings.ComparisonSectionExpanded,
+		hierarchySectionExpanded:  settings.HierarchySectionExpanded,
+		propertiesSectionExpanded: settings.PropertiesSectionExpanded,
+		transformSectionExpanded:  settings.TransformSectionExpanded,
+		materialsSectionExpanded:  settings.MaterialsSectionExpanded,
+		physicsSectionExpanded:    settings.PhysicsSectionExpanded,
+		measureSectionExpanded:    settings.MeasureSectionExpanded,
+
+		dependenciesSectionExpanded: settings.DependenciesSectionExpanded,
 
 		refreshEnabled: true,
+
+		history:      history,
+		comparison:   NewComparisonModel(eventBus),
+		selection:    NewSelectionModel(eventBus),
+		transforms:   NewTransformsModel(window, eventBus, history, projectDir),
+		materials:    NewMaterialsModel(window, eventBus, history, overrides, projectDir),
+		physics:      NewPhysicsModel(eventBus),
+		measure:      NewMeasureModel(eventBus, settings.MeasureUnit),
+		console:      NewConsoleModel(window, eventBus, logBuffer, settings.ConsoleVisible, settings.ConsoleDock),
+		problems:     NewProblemsModel(eventBus),
+		dependencies: NewDependenciesModel(window, eventBus, assetsStorage, settings.DependencyGraphFormat),
+		progress:     NewProgressModel(window, eventBus),
+		packer:       packer,
+		keymap:       NewKeymap(window.Platform().OS()),
 	}
 }
 
 type AppModel struct {
-	window   *ui.Window
-	eventBus *mvc.EventBus
-	registry *asset.Registry
+	window     *ui.Window
+	eventBus   *mvc.EventBus
+	registry   *asset.Registry
+	projectDir string
+	logBuffer  *logs.Buffer
 
+	openResources    []*asset.Resource
 	selectedResource *asset.Resource
+	splitResource    *asset.Resource
+	linkedCameras    bool
+	camera           *CameraSettings
+	theme            theme.Name
 
 	cameraSectionExpanded bool
 	autoExposure          bool
@@ -44,19 +128,275 @@ type AppModel struct {
 	showAmbientLight     bool
 	showDirectionalLight bool
 	showSky              bool
+	showBounds           bool
+	showNodeBounds       bool
+	showSkeleton         bool
+	showNormals          bool
+	showTangents         bool
+
+	comparisonSectionExpanded bool
+	hierarchySectionExpanded  bool
+	propertiesSectionExpanded bool
+	transformSectionExpanded  bool
+	materialsSectionExpanded  bool
+	physicsSectionExpanded    bool
+	measureSectionExpanded    bool
+
+	dependenciesSectionExpanded bool
 
 	refreshEnabled bool
+	refreshCancel  context.CancelFunc
+
+	history      *HistoryModel
+	comparison   *ComparisonModel
+	selection    *SelectionModel
+	transforms   *TransformsModel
+	materials    *MaterialsModel
+	physics      *PhysicsModel
+	measure      *MeasureModel
+	console      *ConsoleModel
+	problems     *ProblemsModel
+	dependencies *DependenciesModel
+	progress     *ProgressModel
+	packer       Packer
+	keymap       *keymap.Keymap
+
+	viewMode ViewMode
 }
 
+// History returns the undo/redo stack of the application.
+func (m *AppModel) History() *HistoryModel {
+	return m.history
+}
+
+// Comparison returns the settings for comparing resource versions.
+func (m *AppModel) Comparison() *ComparisonModel {
+	return m.comparison
+}
+
+// Selection returns the node selection within the selected resource.
+func (m *AppModel) Selection() *SelectionModel {
+	return m.selection
+}
+
+// Transforms returns the node editing tools and the unsaved node edits.
+func (m *AppModel) Transforms() *TransformsModel {
+	return m.transforms
+}
+
+// Materials returns the material edits of the resources.
+func (m *AppModel) Materials() *MaterialsModel {
+	return m.materials
+}
+
+// Physics returns the settings for visualizing and simulating bodies.
+func (m *AppModel) Physics() *PhysicsModel {
+	return m.physics
+}
+
+// Measure returns the state of the ruler tool.
+func (m *AppModel) Measure() *MeasureModel {
+	return m.measure
+}
+
+// Console returns the state of the log console.
+func (m *AppModel) Console() *ConsoleModel {
+	return m.console
+}
+
+// Problems returns the diagnostics of the last refresh.
+func (m *AppModel) Problems() *ProblemsModel {
+	return m.problems
+}
+
+// Dependencies returns the dependency graphs of the resources.
+func (m *AppModel) Dependencies() *DependenciesModel {
+	return m.dependencies
+}
+
+// Progress returns the progress of the running refresh.
+func (m *AppModel) Progress() *ProgressModel {
+	return m.progress
+}
+
+// Keymap returns the keyboard shortcuts of the application.
+func (m *AppModel) Keymap() *keymap.Keymap {
+	return m.keymap
+}
+
+// OpenResources returns the resources that are open in tabs.
+func (m *AppModel) OpenResources() []*asset.Resource {
+	return m.openResources
+}
+
+// SelectedResource returns the resource of the active tab or nil if the
+// registry is being shown.
 func (m *AppModel) SelectedResource() *asset.Resource {
 	return m.selectedResource
 }
 
+// SetSelectedResource activates the tab of the specified resource, opening
+// one if needed. Passing nil switches to the registry, keeping all tabs
+// open.
 func (m *AppModel) SetSelectedResource(resource *asset.Resource) {
+	if resource != nil && !slices.Contains(m.openResources, resource) {
+		m.openResources = append(m.openResources, resource)
+		m.eventBus.Notify(OpenResourcesChangedEvent{})
+	}
+	if resource != nil && resource == m.splitResource {
+		m.splitResource = m.selectedResource
+	}
+	if resource == nil {
+		m.splitResource = nil
+	}
+	if resource != m.selectedResource {
+		m.selection.Clear()
+	}
 	m.selectedResource = resource
 	m.eventBus.Notify(SelectedResourceChangedEvent{})
 }
 
+// CloseResource closes the tab of the specified resource.
+func (m *AppModel) CloseResource(resource *asset.Resource) {
+	index := slices.Index(m.openResources, resource)
+	if index < 0 {
+		return
+	}
+	m.openResources = slices.Delete(m.openResources, index, index+1)
+	m.eventBus.Notify(OpenResourcesChangedEvent{})
+
+	// NOTE: Edits are only reachable through the tab of the resource.
+	m.materials.Discard(resource)
+	m.transforms.Discard(resource)
+
+	if resource == m.splitResource {
+		m.SetSplitResource(nil)
+	}
+	if resource == m.selectedResource {
+		var next *asset.Resource
+		if len(m.openResources) > 0 {
+			next = m.openResources[min(index, len(m.openResources)-1)]
+		}
+		m.SetSelectedResource(next)
+	}
+}
+
+// SplitResource returns the resource that is shown next to the selected
+// one or nil if split view is not active.
+func (m *AppModel) SplitResource() *asset.Resource {
+	return m.splitResource
+}
+
+func (m *AppModel) SetSplitResource(resource *asset.Resource) {
+	if resource == m.selectedResource {
+		resource = nil
+	}
+	if resource != m.splitResource {
+		m.splitResource = resource
+		m.eventBus.Notify(SplitResourceChangedEvent{})
+	}
+}
+
+// IsResourceVisible returns whether the specified resource is currently
+// shown, either in the active tab or in split view.
+func (m *AppModel) IsResourceVisible(resource *asset.Resource) bool {
+	return resource == m.selectedResource || resource == m.splitResource
+}
+
+func (m *AppModel) LinkedCameras() bool {
+	return m.linkedCameras
+}
+
+func (m *AppModel) SetLinkedCameras(linked bool) {
+	if linked != m.linkedCameras {
+		m.linkedCameras = linked
+		m.eventBus.Notify(LinkedCamerasChangedEvent{})
+	}
+}
+
+// Camera returns the last known camera placement or nil if the camera
+// has not been positioned yet.
+func (m *AppModel) Camera() *CameraSettings {
+	return m.camera
+}
+
+// SetCamera records the camera placement. An event is fired only when
+// the cameras of a split view are linked, since the camera is owned by
+// the viewport and changes frequently.
+func (m *AppModel) SetCamera(camera CameraSettings) {
+	m.camera = &camera
+	if m.linkedCameras && m.splitResource != nil {
+		m.eventBus.Notify(CameraChangedEvent{
+			Camera: camera,
+		})
+	}
+}
+
+// SaveSettings persists the preview state to the project settings file.
+func (m *AppModel) SaveSettings() {
+	var selectedResourceID string
+	if m.selectedResource != nil {
+		selectedResourceID = m.selectedResource.ID()
+	}
+	openResourceIDs := make([]string, len(m.openResources))
+	for i, resource := range m.openResources {
+		openResourceIDs[i] = resource.ID()
+	}
+	settings := Settings{
+		SelectedResourceID: selectedResourceID,
+		OpenResourceIDs:    openResourceIDs,
+		LinkedCameras:      m.linkedCameras,
+
+		Theme: m.theme,
+
+		CameraSectionExpanded: m.cameraSectionExpanded,
+		AutoExposure:          m.autoExposure,
+
+		SceneSectionExpanded: m.sceneSectionExpanded,
+		ShowGrid:             m.showGrid,
+		ShowAmbientLight:     m.showAmbientLight,
+		ShowDirectionalLight: m.showDirectionalLight,
+		ShowSky:              m.showSky,
+		ShowBounds:           m.showBounds,
+		ShowNodeBounds:       m.showNodeBounds,
+		ShowSkeleton:         m.showSkeleton,
+		ShowNormals:          m.showNormals,
+		ShowTangents:         m.showTangents,
+
+		ComparisonSectionExpanded: m.comparisonSectionExpanded,
+		HierarchySectionExpanded:  m.hierarchySectionExpanded,
+		PropertiesSectionExpanded: m.propertiesSectionExpanded,
+		TransformSectionExpanded:  m.transformSectionExpanded,
+		MaterialsSectionExpanded:  m.materialsSectionExpanded,
+		PhysicsSectionExpanded:    m.physicsSectionExpanded,
+		MeasureSectionExpanded:    m.measureSectionExpanded,
+
+		DependenciesSectionExpanded: m.dependenciesSectionExpanded,
+		DependencyGraphFormat:       m.dependencies.Format(),
+
+		MeasureUnit: m.measure.Unit(),
+
+		ConsoleVisible: m.console.Visible(),
+		ConsoleDock:    m.console.Dock(),
+
+		Camera: m.camera,
+	}
+	if err := SaveSettings(m.projectDir, settings); err != nil {
+		log.Error("Error saving settings: %v", err)
+	}
+}
+
+func (m *AppModel) Theme() theme.Name {
+	return m.theme
+}
+
+func (m *AppModel) SetTheme(name theme.Name) {
+	if name != m.theme {
+		m.theme = name
+		m.eventBus.Notify(ThemeChangedEvent{})
+	}
+}
+
 func (m *AppModel) RefreshEnabled() bool {
 	return m.refreshEnabled
 }
@@ -64,78 +404,176 @@ func (m *AppModel) RefreshEnabled() bool {
 func (m *AppModel) Refresh() {
 	if m.refreshEnabled {
 		m.refreshEnabled = false
+		resource := m.selectedResource
+		if resource == nil {
+			m.progress.Reset(len(m.registry.Resources()))
+		} else {
+			m.progress.Reset(1)
+		}
+		m.eventBus.Notify(RefreshStartedEvent{})
+		logID := m.logBuffer.NextID()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		m.refreshCancel = cancel
 		var promise async.Promise[struct{}]
-		if m.selectedResource == nil {
-			promise = m.refreshRegistry()
+		if resource == nil {
+			promise = m.refreshRegistry(ctx)
 		} else {
-			promise = m.refreshResource(m.selectedResource)
+			promise = m.refreshResource(ctx, resource)
 		}
 		promise.OnSuccess(func(struct{}) {
 			m.window.Schedule(func() {
-				m.refreshEnabled = true
+				m.finishRefresh()
+				// The content was regenerated, so edits no longer apply
+				// to it.
+				m.materials.Discard(resource)
+				m.transforms.Discard(resource)
 				m.eventBus.Notify(RefreshEvent{})
 			})
 		})
 		promise.OnError(func(err error) {
 			m.window.Schedule(func() {
-				m.refreshEnabled = true
+				m.finishRefresh()
 				m.eventBus.Notify(RefreshErrorEvent{
-					Err: err,
+					Err:   err,
+					LogID: logID,
 				})
 			})
 		})
 	}
 }
 
-func (m *AppModel) refreshRegistry() async.Promise[struct{}] {
+// CancelRefresh stops the running refresh, if there is one. The refresh
+// then completes with an error that wraps context.Canceled.
+func (m *AppModel) CancelRefresh() {
+	if m.refreshCancel != nil {
+		m.refreshCancel()
+		m.progress.SetStep(RefreshStepCancelling)
+	}
+}
+
+func (m *AppModel) finishRefresh() {
+	m.refreshEnabled = true
+	if m.refreshCancel != nil {
+		m.refreshCancel()
+		m.refreshCancel = nil
+	}
+	// Even a failed refresh could have changed some of the resources.
+	m.dependencies.Invalidate()
+}
+
+func (m *AppModel) refreshRegistry(ctx context.Context) async.Promise[struct{}] {
+	flow := m.refreshFlow()
 	promise := async.NewPromise[struct{}]()
 	go func() {
-		if err := m.packAssets(""); err != nil {
-			promise.Fail(err)
-		}
-
-		reloadErr := make(chan error)
-		m.window.Schedule(func() {
-			reloadErr <- m.registry.Reload()
-		})
-		if err := <-reloadErr; err != nil {
+		if err := flow.Registry(ctx); err != nil {
 			promise.Fail(err)
+			return
 		}
-
 		promise.Deliver(struct{}{})
 	}()
 	return promise
 }
 
-func (m *AppModel) refreshResource(resource *asset.Resource) async.Promise[struct{}] {
+func (m *AppModel) refreshResource(ctx context.Context, resource *asset.Resource) async.Promise[struct{}] {
+	flow := m.refreshFlow()
+	compare := m.comparison.Enabled()
 	promise := async.NewPromise[struct{}]()
 	go func() {
-		if err := m.packAssets(resource.Name()); err != nil {
+		var beforeStats ModelStats
+		if compare {
+			var err error
+			if beforeStats, err = resourceStats(resource); err != nil {
+				log.Warn("Error computing stats before refresh: %v", err)
+				compare = false
+			}
+		}
+		if err := flow.Model(ctx, resource.Name()); err != nil {
 			promise.Fail(err)
+			return
+		}
+		if compare {
+			m.window.Schedule(func() {
+				m.progress.SetStep(RefreshStepComparing)
+			})
+			afterStats, err := resourceStats(resource)
+			if err != nil {
+				log.Warn("Error computing stats after refresh: %v", err)
+			} else {
+				statsDiff := DiffModelStats(beforeStats, afterStats)
+				m.window.Schedule(func() {
+					m.comparison.SetStatsDiff(statsDiff)
+				})
+			}
 		}
 		promise.Deliver(struct{}{})
 	}()
 	return promise
 }
 
-func (m *AppModel) packAssets(model string) error {
-	args := []string{"pack"}
-	if model != "" {
-		args = append(args, "--", model)
-	}
-	cmd := exec.Command("task", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return err
+// refreshFlow creates a refreshFlow that can be run outside of the UI
+// thread and that applies its results on the UI thread.
+func (m *AppModel) refreshFlow() *refreshFlow {
+	return &refreshFlow{
+		packer: m.packer,
+		reloader: ReloaderFunc(func() error {
+			reloadErr := make(chan error)
+			m.window.Schedule(func() {
+				reloadErr <- m.registry.Reload()
+			})
+			return <-reloadErr
+		}),
+		output: PackOutput{
+			Stdout:   io.MultiWriter(os.Stdout, m.logBuffer.Writer(sourcePack, logs.LevelInfo)),
+			Stderr:   io.MultiWriter(m.logBuffer.Terminal(), m.logBuffer.Writer(sourcePack, logs.LevelError)),
+			Progress: m.progress.Writer(),
+		},
+		onDiagnostics: func(diagnostics []pack.Diagnostic) {
+			m.window.Schedule(func() {
+				m.problems.SetDiagnostics(diagnostics)
+			})
+		},
+		onStep: func(step RefreshStep) {
+			m.window.Schedule(func() {
+				m.progress.SetStep(step)
+			})
+		},
 	}
-	return nil
 }
 
 func (m *AppModel) Resources() []*asset.Resource {
 	return m.registry.Resources()
 }
 
+func (m *AppModel) ResourceByID(id string) *asset.Resource {
+	return m.registry.ResourceByID(id)
+}
+
+func (m *AppModel) ViewMode() ViewMode {
+	return m.viewMode
+}
+
+func (m *AppModel) SetViewMode(mode ViewMode) {
+	m.viewMode = mode
+	m.eventBus.Notify(ViewModeChangedEvent{})
+}
+
+// SwitchViewMode cycles to the next view mode.
+func (m *AppModel) SwitchViewMode() {
+	m.SetViewMode((m.viewMode + 1) % viewModeCount)
+}
+
+// FrameModel requests that the camera be positioned to show the whole
+// model.
+func (m *AppModel) FrameModel() {
+	m.eventBus.Notify(FrameModelEvent{})
+}
+
+// ShowShortcuts requests that the keyboard shortcuts be displayed.
+func (m *AppModel) ShowShortcuts() {
+	m.eventBus.Notify(ShowShortcutsEvent{})
+}
+
 func (m *AppModel) CameraSectionExpanded() bool {
 	return m.cameraSectionExpanded
 }
@@ -153,8 +591,7 @@ func (m *AppModel) AutoExposure() bool {
 
 func (m *AppModel) SetAutoExposure(value bool) {
 	if value != m.autoExposure {
-		m.autoExposure = value
-		m.eventBus.Notify(AutoExposureChangedEvent{})
+		m.history.Do(ValueChange(m.eventBus, &m.autoExposure, value, AutoExposureChangedEvent{}))
 	}
 }
 
@@ -175,8 +612,7 @@ func (m *AppModel) ShowGrid() bool {
 
 func (m *AppModel) SetShowGrid(value bool) {
 	if value != m.showGrid {
-		m.showGrid = value
-		m.eventBus.Notify(ShowGridChangedEvent{})
+		m.history.Do(ValueChange(m.eventBus, &m.showGrid, value, ShowGridChangedEvent{}))
 	}
 }
 
@@ -186,8 +622,7 @@ func (m *AppModel) ShowAmbientLight() bool {
 
 func (m *AppModel) SetShowAmbientLight(value bool) {
 	if value != m.showAmbientLight {
-		m.showAmbientLight = value
-		m.eventBus.Notify(ShowAmbientLightChangedEvent{})
+		m.history.Do(ValueChange(m.eventBus, &m.showAmbientLight, value, ShowAmbientLightChangedEvent{}))
 	}
 }
 
@@ -197,8 +632,7 @@ func (m *AppModel) ShowDirectionalLight() bool {
 
 func (m *AppModel) SetShowDirectionalLight(value bool) {
 	if value != m.showDirectionalLight {
-		m.showDirectionalLight = value
-		m.eventBus.Notify(ShowDirectionalLightChangedEvent{})
+		m.history.Do(ValueChange(m.eventBus, &m.showDirectionalLight, value, ShowDirectionalLightChangedEvent{}))
 	}
 }
 
@@ -208,19 +642,180 @@ func (m *AppModel) ShowSky() bool {
 
 func (m *AppModel) SetShowSky(value bool) {
 	if value != m.showSky {
-		m.showSky = value
-		m.eventBus.Notify(ShowSkyChangedEvent{})
+		m.history.Do(ValueChange(m.eventBus, &m.showSky, value, ShowSkyChangedEvent{}))
+	}
+}
+
+func (m *AppModel) ShowBounds() bool {
+	return m.showBounds
+}
+
+func (m *AppModel) SetShowBounds(value bool) {
+	if value != m.showBounds {
+		m.history.Do(ValueChange(m.eventBus, &m.showBounds, value, ShowBoundsChangedEvent{}))
+	}
+}
+
+func (m *AppModel) ShowNodeBounds() bool {
+	return m.showNodeBounds
+}
+
+func (m *AppModel) SetShowNodeBounds(value bool) {
+	if value != m.showNodeBounds {
+		m.history.Do(ValueChange(m.eventBus, &m.showNodeBounds, value, ShowNodeBoundsChangedEvent{}))
+	}
+}
+
+func (m *AppModel) ShowSkeleton() bool {
+	return m.showSkeleton
+}
+
+func (m *AppModel) SetShowSkeleton(value bool) {
+	if value != m.showSkeleton {
+		m.history.Do(ValueChange(m.eventBus, &m.showSkeleton, value, ShowSkeletonChangedEvent{}))
+	}
+}
+
+func (m *AppModel) ShowNormals() bool {
+	return m.showNormals
+}
+
+func (m *AppModel) SetShowNormals(value bool) {
+	if value != m.showNormals {
+		m.history.Do(ValueChange(m.eventBus, &m.showNormals, value, ShowNormalsChangedEvent{}))
+	}
+}
+
+func (m *AppModel) ShowTangents() bool {
+	return m.showTangents
+}
+
+func (m *AppModel) SetShowTangents(value bool) {
+	if value != m.showTangents {
+		m.history.Do(ValueChange(m.eventBus, &m.showTangents, value, ShowTangentsChangedEvent{}))
+	}
+}
+
+func (m *AppModel) HierarchySectionExpanded() bool {
+	return m.hierarchySectionExpanded
+}
+
+func (m *AppModel) SetHierarchySectionExpanded(value bool) {
+	if value != m.hierarchySectionExpanded {
+		m.hierarchySectionExpanded = value
+		m.eventBus.Notify(HierarchySectionExpandedChangedEvent{})
+	}
+}
+
+func (m *AppModel) PropertiesSectionExpanded() bool {
+	return m.propertiesSectionExpanded
+}
+
+func (m *AppModel) SetPropertiesSectionExpanded(value bool) {
+	if value != m.propertiesSectionExpanded {
+		m.propertiesSectionExpanded = value
+		m.eventBus.Notify(PropertiesSectionExpandedChangedEvent{})
+	}
+}
+
+func (m *AppModel) TransformSectionExpanded() bool {
+	return m.transformSectionExpanded
+}
+
+func (m *AppModel) SetTransformSectionExpanded(value bool) {
+	if value != m.transformSectionExpanded {
+		m.transformSectionExpanded = value
+		m.eventBus.Notify(TransformSectionExpandedChangedEvent{})
+	}
+}
+
+func (m *AppModel) MaterialsSectionExpanded() bool {
+	return m.materialsSectionExpanded
+}
+
+func (m *AppModel) SetMaterialsSectionExpanded(value bool) {
+	if value != m.materialsSectionExpanded {
+		m.materialsSectionExpanded = value
+		m.eventBus.Notify(MaterialsSectionExpandedChangedEvent{})
+	}
+}
+
+func (m *AppModel) PhysicsSectionExpanded() bool {
+	return m.physicsSectionExpanded
+}
+
+func (m *AppModel) SetPhysicsSectionExpanded(value bool) {
+	if value != m.physicsSectionExpanded {
+		m.physicsSectionExpanded = value
+		m.eventBus.Notify(PhysicsSectionExpandedChangedEvent{})
+	}
+}
+
+func (m *AppModel) MeasureSectionExpanded() bool {
+	return m.measureSectionExpanded
+}
+
+func (m *AppModel) SetMeasureSectionExpanded(value bool) {
+	if value != m.measureSectionExpanded {
+		m.measureSectionExpanded = value
+		m.eventBus.Notify(MeasureSectionExpandedChangedEvent{})
+	}
+}
+
+func (m *AppModel) DependenciesSectionExpanded() bool {
+	return m.dependenciesSectionExpanded
+}
+
+func (m *AppModel) SetDependenciesSectionExpanded(value bool) {
+	if value != m.dependenciesSectionExpanded {
+		m.dependenciesSectionExpanded = value
+		m.eventBus.Notify(DependenciesSectionExpandedChangedEvent{})
+	}
+}
+
+type OpenResourcesChangedEvent struct{}
+
+func (m *AppModel) ComparisonSectionExpanded() bool {
+	return m.comparisonSectionExpanded
+}
+
+func (m *AppModel) SetComparisonSectionExpanded(value bool) {
+	if value != m.comparisonSectionExpanded {
+		m.comparisonSectionExpanded = value
+		m.eventBus.Notify(ComparisonSectionExpandedChangedEvent{})
 	}
 }
 
 type SelectedResourceChangedEvent struct{}
 
+type SplitResourceChangedEvent struct{}
+
+type LinkedCamerasChangedEvent struct{}
+
+type CameraChangedEvent struct {
+	Camera CameraSettings
+}
+
+type ThemeChangedEvent struct{}
+
+type RefreshStartedEvent struct{}
+
 type RefreshEvent struct{}
 
 type RefreshErrorEvent struct {
 	Err error
+
+	// LogID is the ID of the first log entry that was written during the
+	// refresh.
+	LogID uint64
 }
 
+type ViewModeChangedEvent struct{}
+
+type FrameModelEvent struct{}
+
+type ShowShortcutsEvent struct{}
+
 type CameraSectionExpandedChangedEvent struct{}
 
 type AutoExposureChangedEvent struct{}
@@ -234,3 +829,29 @@ type ShowAmbientLightChangedEvent struct{}
 type ShowDirectionalLightChangedEvent struct{}
 
 type ShowSkyChangedEvent struct{}
+
+type ShowBoundsChangedEvent struct{}
+
+type ShowNodeBoundsChangedEvent struct{}
+
+type ShowSkeletonChangedEvent struct{}
+
+type ShowNormalsChangedEvent struct{}
+
+type ShowTangentsChangedEvent struct{}
+
+type ComparisonSectionExpandedChangedEvent struct{}
+
+type HierarchySectionExpandedChangedEvent struct{}
+
+type PropertiesSectionExpandedChangedEvent struct{}
+
+type TransformSectionExpandedChangedEvent struct{}
+
+type MaterialsSectionExpandedChangedEvent struct{}
+
+type PhysicsSectionExpandedChangedEvent struct{}
+
+type MeasureSectionExpandedChangedEvent struct{}
+
+type DependenciesSectionExpandedChangedEvent struct{}
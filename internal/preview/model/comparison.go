@@ -0,0 +1,137 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/ui/mvc"
+)
+
+const (
+	CompareModeToggle CompareMode = iota
+	CompareModeWipe
+	CompareModeOverlay
+)
+
+// CompareMode determines how the previous and the current version of a
+// resource are presented.
+type CompareMode int
+
+// String returns a human-readable name of this compare mode.
+func (m CompareMode) String() string {
+	switch m {
+	case CompareModeToggle:
+		return "Toggle"
+	case CompareModeWipe:
+		return "Wipe"
+	case CompareModeOverlay:
+		return "Overlay"
+	default:
+		return "Unknown"
+	}
+}
+
+// CompareModes returns all available compare modes.
+func CompareModes() []CompareMode {
+	return []CompareMode{
+		CompareModeToggle,
+		CompareModeWipe,
+		CompareModeOverlay,
+	}
+}
+
+// NewComparisonModel creates a new ComparisonModel.
+func NewComparisonModel(eventBus *mvc.EventBus) *ComparisonModel {
+	return &ComparisonModel{
+		eventBus: eventBus,
+		mode:     CompareModeWipe,
+		wipe:     0.5,
+	}
+}
+
+// ComparisonModel controls whether the previous version of a resource is
+// kept around after a refresh and how it is compared to the new one.
+type ComparisonModel struct {
+	eventBus *mvc.EventBus
+
+	enabled    bool
+	mode       CompareMode
+	showBefore bool
+	wipe       float64
+	statsDiff  string
+}
+
+// Enabled returns whether the previous version of a resource should be
+// kept when it is refreshed.
+func (m *ComparisonModel) Enabled() bool {
+	return m.enabled
+}
+
+func (m *ComparisonModel) SetEnabled(enabled bool) {
+	if enabled != m.enabled {
+		m.enabled = enabled
+		if !enabled {
+			m.statsDiff = ""
+		}
+		m.eventBus.Notify(ComparisonChangedEvent{})
+	}
+}
+
+func (m *ComparisonModel) Mode() CompareMode {
+	return m.mode
+}
+
+func (m *ComparisonModel) SetMode(mode CompareMode) {
+	if mode != m.mode {
+		m.mode = mode
+		m.eventBus.Notify(ComparisonChangedEvent{})
+	}
+}
+
+// ShowBefore returns whether the previous version should be shown
+// instead of the current one when in toggle mode.
+func (m *ComparisonModel) ShowBefore() bool {
+	return m.showBefore
+}
+
+func (m *ComparisonModel) SetShowBefore(show bool) {
+	if show != m.showBefore {
+		m.showBefore = show
+		m.eventBus.Notify(ComparisonChangedEvent{})
+	}
+}
+
+// Wipe returns the fraction of the viewport width, starting from the left,
+// that shows the previous version when in wipe mode.
+func (m *ComparisonModel) Wipe() float64 {
+	return m.wipe
+}
+
+func (m *ComparisonModel) SetWipe(wipe float64) {
+	wipe = min(max(wipe, 0.0), 1.0)
+	if wipe != m.wipe {
+		m.wipe = wipe
+		m.eventBus.Notify(ComparisonChangedEvent{})
+	}
+}
+
+// StatsDiff returns a description of how the stats of the resource
+// changed during the last refresh.
+func (m *ComparisonModel) StatsDiff() string {
+	return m.statsDiff
+}
+
+func (m *ComparisonModel) SetStatsDiff(diff string) {
+	m.statsDiff = diff
+	m.eventBus.Notify(ComparisonChangedEvent{})
+}
+
+func resourceStats(resource *asset.Resource) (ModelStats, error) {
+	content, err := resource.OpenContent()
+	if err != nil {
+		return ModelStats{}, fmt.Errorf("error opening content: %w", err)
+	}
+	return ComputeModelStats(&content), nil
+}
+
+type ComparisonChangedEvent struct{}
@@ -0,0 +1,202 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking/ui"
+	"github.com/mokiat/lacking/ui/mvc"
+)
+
+const (
+	ConsoleDockBottom ConsoleDock = "bottom"
+	ConsoleDockRight  ConsoleDock = "right"
+)
+
+const (
+	ConsoleTabLogs     ConsoleTab = "logs"
+	ConsoleTabProblems ConsoleTab = "problems"
+)
+
+// ConsoleTab is the content that is displayed in the log console.
+type ConsoleTab string
+
+// ConsoleDock is the edge of the window that the log console is attached
+// to.
+type ConsoleDock string
+
+// NewConsoleModel creates a new ConsoleModel that displays the entries of
+// the specified buffer.
+func NewConsoleModel(window *ui.Window, eventBus *mvc.EventBus, buffer *logs.Buffer, visible bool, dock ConsoleDock) *ConsoleModel {
+	if dock != ConsoleDockRight {
+		dock = ConsoleDockBottom
+	}
+	result := &ConsoleModel{
+		window:   window,
+		eventBus: eventBus,
+		buffer:   buffer,
+
+		visible:  visible,
+		tab:      ConsoleTabLogs,
+		dock:     dock,
+		minLevel: logs.LevelInfo,
+	}
+	buffer.SetOnChange(result.handleBufferChange)
+	return result
+}
+
+// ConsoleModel holds the state of the log console.
+type ConsoleModel struct {
+	window   *ui.Window
+	eventBus *mvc.EventBus
+	buffer   *logs.Buffer
+
+	visible  bool
+	tab      ConsoleTab
+	dock     ConsoleDock
+	minLevel logs.Level
+	search   string
+	sinceID  uint64
+
+	// changeScheduled makes sure that a burst of entries results in a
+	// single notification.
+	changeScheduled atomic.Bool
+}
+
+func (m *ConsoleModel) Visible() bool {
+	return m.visible
+}
+
+func (m *ConsoleModel) SetVisible(visible bool) {
+	if visible != m.visible {
+		m.visible = visible
+		m.eventBus.Notify(ConsoleLayoutChangedEvent{})
+	}
+}
+
+func (m *ConsoleModel) Tab() ConsoleTab {
+	return m.tab
+}
+
+func (m *ConsoleModel) SetTab(tab ConsoleTab) {
+	if tab != m.tab {
+		m.tab = tab
+		m.eventBus.Notify(ConsoleChangedEvent{})
+	}
+}
+
+// ShowProblems opens the console with the diagnostics of the last refresh.
+func (m *ConsoleModel) ShowProblems() {
+	m.SetTab(ConsoleTabProblems)
+	m.SetVisible(true)
+}
+
+func (m *ConsoleModel) Dock() ConsoleDock {
+	return m.dock
+}
+
+func (m *ConsoleModel) SetDock(dock ConsoleDock) {
+	if dock != m.dock {
+		m.dock = dock
+		m.eventBus.Notify(ConsoleLayoutChangedEvent{})
+	}
+}
+
+// MinLevel returns the lowest severity of the entries that are displayed.
+func (m *ConsoleModel) MinLevel() logs.Level {
+	return m.minLevel
+}
+
+func (m *ConsoleModel) SetMinLevel(level logs.Level) {
+	if level != m.minLevel {
+		m.minLevel = level
+		m.eventBus.Notify(ConsoleChangedEvent{})
+	}
+}
+
+// Search returns the text that displayed entries need to contain.
+func (m *ConsoleModel) Search() string {
+	return m.search
+}
+
+func (m *ConsoleModel) SetSearch(search string) {
+	if search != m.search {
+		m.search = search
+		m.eventBus.Notify(ConsoleChangedEvent{})
+	}
+}
+
+// SinceID returns the ID of the oldest entry that is displayed or zero if
+// all entries are displayed.
+func (m *ConsoleModel) SinceID() uint64 {
+	return m.sinceID
+}
+
+// ShowSince opens the console with only the entries starting from the
+// specified ID.
+func (m *ConsoleModel) ShowSince(id uint64) {
+	m.sinceID = id
+	m.tab = ConsoleTabLogs
+	m.eventBus.Notify(ConsoleChangedEvent{})
+	m.SetVisible(true)
+}
+
+// ShowAll removes the restriction that was set with ShowSince.
+func (m *ConsoleModel) ShowAll() {
+	if m.sinceID != 0 {
+		m.sinceID = 0
+		m.eventBus.Notify(ConsoleChangedEvent{})
+	}
+}
+
+// Entries returns the entries that pass the filters of the console.
+func (m *ConsoleModel) Entries() []logs.Entry {
+	search := strings.ToLower(m.search)
+	return slices.DeleteFunc(m.buffer.Entries(), func(entry logs.Entry) bool {
+		if entry.ID < m.sinceID || entry.Level < m.minLevel {
+			return true
+		}
+		return search != "" && !strings.Contains(strings.ToLower(entry.String()), search)
+	})
+}
+
+// Text returns the entries that pass the filters of the console, one
+// per line.
+func (m *ConsoleModel) Text() string {
+	var builder strings.Builder
+	for _, entry := range m.Entries() {
+		builder.WriteString(entry.String())
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// Copy places the entries that pass the filters in the clipboard.
+func (m *ConsoleModel) Copy() {
+	m.window.RequestCopy(m.Text())
+}
+
+// Clear removes all entries.
+func (m *ConsoleModel) Clear() {
+	m.sinceID = 0
+	m.buffer.Clear()
+}
+
+func (m *ConsoleModel) handleBufferChange() {
+	if m.changeScheduled.CompareAndSwap(false, true) {
+		m.window.Schedule(func() {
+			m.changeScheduled.Store(false)
+			m.eventBus.Notify(ConsoleChangedEvent{})
+		})
+	}
+}
+
+// ConsoleChangedEvent indicates that the entries of the console or its
+// filters have changed.
+type ConsoleChangedEvent struct{}
+
+// ConsoleLayoutChangedEvent indicates that the console was shown, hidden
+// or docked to a different edge.
+type ConsoleLayoutChangedEvent struct{}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"github.com/mokiat/lacking-studio/internal/depgraph"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/ui"
+	"github.com/mokiat/lacking/ui/mvc"
+)
+
+// NewDependenciesModel creates a model for the dependency graphs of the
+// resources. The sizes of the resources are read from the specified
+// storage, unless it is nil.
+func NewDependenciesModel(window *ui.Window, eventBus *mvc.EventBus, storage asset.Storage, format depgraph.Format) *DependenciesModel {
+	return &DependenciesModel{
+		window:   window,
+		eventBus: eventBus,
+		storage:  storage,
+		format:   format,
+		graphs:   make(map[string]dependencyGraph),
+	}
+}
+
+// DependenciesModel holds the dependency graphs of the resources, together
+// with the format in which they are exported.
+type DependenciesModel struct {
+	window   *ui.Window
+	eventBus *mvc.EventBus
+	storage  asset.Storage
+	format   depgraph.Format
+
+	// generation is incremented whenever the graphs are invalidated, so that
+	// sizes that were loaded for a previous registry are ignored.
+	generation int
+	graphs     map[string]dependencyGraph
+}
+
+type dependencyGraph struct {
+	Graph       depgraph.Graph
+	SizesLoaded bool
+}
+
+// Format returns the format in which graphs are copied.
+func (m *DependenciesModel) Format() depgraph.Format {
+	return m.format
+}
+
+// SetFormat changes the format in which graphs are copied.
+func (m *DependenciesModel) SetFormat(format depgraph.Format) {
+	if format != m.format {
+		m.format = format
+		m.eventBus.Notify(DependenciesChangedEvent{})
+	}
+}
+
+// Graph returns the graph of the specified resource and everything that
+// it depends on. The sizes of the resources are loaded in the background
+// and the second result indicates whether they are available yet.
+func (m *DependenciesModel) Graph(resource *asset.Resource) (depgraph.Graph, bool) {
+	if graph, ok := m.graphs[resource.ID()]; ok {
+		return graph.Graph, graph.SizesLoaded
+	}
+
+	graph := depgraph.Reachable(resource)
+	if m.storage == nil {
+		m.graphs[resource.ID()] = dependencyGraph{
+			Graph:       graph,
+			SizesLoaded: true,
+		}
+		return graph, true
+	}
+	m.graphs[resource.ID()] = dependencyGraph{
+		Graph: graph,
+	}
+
+	// NOTE: The nodes are copied, since the graph that was returned keeps
+	// being used by the caller while the sizes are loaded.
+	sizedGraph := depgraph.Graph{
+		Nodes: append([]depgraph.Node(nil), graph.Nodes...),
+		Edges: graph.Edges,
+	}
+	generation := m.generation
+	go func() {
+		if err := sizedGraph.LoadSizes(m.storage); err != nil {
+			log.Warn("Error loading resource sizes: %v", err)
+		}
+		m.window.Schedule(func() {
+			if generation != m.generation {
+				return
+			}
+			m.graphs[resource.ID()] = dependencyGraph{
+				Graph:       sizedGraph,
+				SizesLoaded: true,
+			}
+			m.eventBus.Notify(DependenciesChangedEvent{})
+		})
+	}()
+	return graph, false
+}
+
+// Copy places the graph of the specified resource in the clipboard, in
+// the selected format.
+func (m *DependenciesModel) Copy(resource *asset.Resource) {
+	graph, _ := m.Graph(resource)
+	text, err := graph.Text(m.format)
+	if err != nil {
+		log.Error("Error formatting dependency graph: %v", err)
+		return
+	}
+	m.window.RequestCopy(text)
+}
+
+// Invalidate discards all graphs, for example after the registry has been
+// reloaded.
+func (m *DependenciesModel) Invalidate() {
+	m.generation++
+	clear(m.graphs)
+	m.eventBus.Notify(DependenciesChangedEvent{})
+}
+
+type DependenciesChangedEvent struct{}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OpenInEditor opens the specified file in the editor that is configured
+// through the VISUAL or EDITOR environment variables. If the line is
+// positive, the editor is asked to go to it, if it is known how.
+func OpenInEditor(path string, line int) error {
+	if path == "" {
+		return errors.New("no file to open")
+	}
+	command := strings.Fields(cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR")))
+	if len(command) == 0 {
+		return errors.New("neither VISUAL nor EDITOR is set")
+	}
+	args := append(command[1:], editorArgs(filepath.Base(command[0]), path, line)...)
+	cmd := exec.Command(command[0], args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting editor: %w", err)
+	}
+	// The editor is not waited on, though its exit status still needs to be
+	// collected.
+	go cmd.Wait()
+	return nil
+}
+
+func editorArgs(editor, path string, line int) []string {
+	if line <= 0 {
+		return []string{path}
+	}
+	location := path + ":" + strconv.Itoa(line)
+	switch strings.TrimSuffix(editor, ".exe") {
+	case "code", "code-insiders", "codium", "cursor":
+		return []string{"--goto", location}
+	case "subl", "zed", "hx":
+		return []string{location}
+	case "vi", "vim", "nvim", "nano", "emacs", "emacsclient", "micro", "kak":
+		return []string{"+" + strconv.Itoa(line), path}
+	case "idea", "goland":
+		return []string{"--line", strconv.Itoa(line), path}
+	default:
+		return []string{path}
+	}
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"slices"
+
+	"github.com/mokiat/lacking/ui/mvc"
+	"github.com/mokiat/lacking/ui/state"
+)
+
+const historyCapacity = 100
+
+// NewHistoryModel creates a new HistoryModel that tracks reversible
+// model changes.
+func NewHistoryModel(eventBus *mvc.EventBus) *HistoryModel {
+	return &HistoryModel{
+		eventBus: eventBus,
+	}
+}
+
+// HistoryModel is an undo/redo stack for model mutations. Any model that
+// wants its mutations to be reversible should express them as a
+// state.Change and pass them to Do.
+//
+// NOTE: This does not use state.History, since changes that no longer
+// apply need to be discarded selectively.
+type HistoryModel struct {
+	eventBus  *mvc.EventBus
+	undoStack []state.Change
+	redoStack []state.Change
+}
+
+// Do applies the specified change and records it for undo.
+func (m *HistoryModel) Do(change state.Change) {
+	if change == nil {
+		return
+	}
+	if !m.extendLast(change) {
+		if len(m.undoStack) == historyCapacity {
+			m.undoStack = slices.Delete(m.undoStack, 0, 1)
+		}
+		m.undoStack = append(m.undoStack, change)
+	}
+	clear(m.redoStack)
+	m.redoStack = m.redoStack[:0]
+	change.Apply()
+	m.eventBus.Notify(HistoryChangedEvent{})
+}
+
+func (m *HistoryModel) CanUndo() bool {
+	return len(m.undoStack) > 0
+}
+
+func (m *HistoryModel) Undo() {
+	if m.CanUndo() {
+		change := m.undoStack[len(m.undoStack)-1]
+		m.undoStack = m.undoStack[:len(m.undoStack)-1]
+		m.redoStack = append(m.redoStack, change)
+		change.Revert()
+		m.eventBus.Notify(HistoryChangedEvent{})
+	}
+}
+
+func (m *HistoryModel) CanRedo() bool {
+	return len(m.redoStack) > 0
+}
+
+func (m *HistoryModel) Redo() {
+	if m.CanRedo() {
+		change := m.redoStack[len(m.redoStack)-1]
+		m.redoStack = m.redoStack[:len(m.redoStack)-1]
+		m.undoStack = append(m.undoStack, change)
+		change.Apply()
+		m.eventBus.Notify(HistoryChangedEvent{})
+	}
+}
+
+// Clear discards all tracked changes.
+func (m *HistoryModel) Clear() {
+	m.undoStack = nil
+	m.redoStack = nil
+	m.eventBus.Notify(HistoryChangedEvent{})
+}
+
+// Discard removes the tracked changes for which the specified function
+// returns true, without reverting them.
+func (m *HistoryModel) Discard(fn func(change state.Change) bool) {
+	undoCount, redoCount := len(m.undoStack), len(m.redoStack)
+	m.undoStack = slices.DeleteFunc(m.undoStack, fn)
+	m.redoStack = slices.DeleteFunc(m.redoStack, fn)
+	if len(m.undoStack) != undoCount || len(m.redoStack) != redoCount {
+		m.eventBus.Notify(HistoryChangedEvent{})
+	}
+}
+
+func (m *HistoryModel) extendLast(change state.Change) bool {
+	if len(m.undoStack) == 0 {
+		return false
+	}
+	extendable, ok := m.undoStack[len(m.undoStack)-1].(state.ExtendableChange)
+	return ok && extendable.Extend(change)
+}
+
+// ValueChange returns a change that assigns the specified value to the
+// target and notifies the specified event, both when applied and reverted.
+func ValueChange[T any](eventBus *mvc.EventBus, target *T, value T, event mvc.Event) state.Change {
+	oldValue := *target
+	return state.ActionChange(0,
+		[]state.Action{
+			func() {
+				*target = value
+				eventBus.Notify(event)
+			},
+		},
+		[]state.Action{
+			func() {
+				*target = oldValue
+				eventBus.Notify(event)
+			},
+		},
+	)
+}
+
+type HistoryChangedEvent struct{}
@@ -0,0 +1,311 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mokiat/gblob"
+	"github.com/mokiat/lacking-studio/internal/dsledit"
+	"github.com/mokiat/lacking-studio/internal/storage"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/ui"
+	"github.com/mokiat/lacking/ui/mvc"
+	"github.com/mokiat/lacking/ui/state"
+)
+
+// MaterialShaderTypes returns the names of the shader types that are used
+// by the passes of the specified material.
+func MaterialShaderTypes(material asset.Material) []string {
+	var result []string
+	if len(material.GeometryPasses) > 0 {
+		result = append(result, "Geometry")
+	}
+	if len(material.ShadowPasses) > 0 {
+		result = append(result, "Shadow")
+	}
+	if len(material.ForwardPasses) > 0 {
+		result = append(result, "Forward")
+	}
+	if len(material.SkyPasses) > 0 {
+		result = append(result, "Sky")
+	}
+	if len(material.PostprocessingPasses) > 0 {
+		result = append(result, "Postprocess")
+	}
+	return result
+}
+
+// DecodeMaterialProperty returns the float components of the data of
+// a material property.
+func DecodeMaterialProperty(data []byte) []float32 {
+	block := gblob.LittleEndianBlock(data)
+	result := make([]float32, len(data)/4)
+	for i := range result {
+		result[i] = block.Float32(i * 4)
+	}
+	return result
+}
+
+// EncodeMaterialProperty returns the data of a material property that
+// holds the specified float components.
+func EncodeMaterialProperty(values []float32) []byte {
+	block := make(gblob.LittleEndianBlock, len(values)*4)
+	for i, value := range values {
+		block.SetFloat32(i*4, value)
+	}
+	return block
+}
+
+// NewMaterialsModel creates a new MaterialsModel that previews edits by
+// overriding the content of resources in the specified storage and writes
+// them back to the sources of the project in the specified directory.
+func NewMaterialsModel(window *ui.Window, eventBus *mvc.EventBus, history *HistoryModel, overrides *storage.OverrideStorage, projectDir string) *MaterialsModel {
+	return &MaterialsModel{
+		window:          window,
+		eventBus:        eventBus,
+		history:         history,
+		overrides:       overrides,
+		projectDir:      projectDir,
+		editsByResource: make(map[string]map[int]materialEdit),
+	}
+}
+
+// MaterialsModel applies edits to the material properties of resources.
+// The edits are previewed until the next refresh and can be written back
+// to the source of the resource or copied as DSL operations.
+//
+// NOTE: Ideally, edits would be applied to the loaded materials through
+// graphics.Material.SetProperty. However, game.ModelDefinition does not
+// expose its materials, so instead the edited content overrides the packed
+// one in memory and the model is loaded again. This avoids a repack but is
+// not as fast.
+type MaterialsModel struct {
+	window     *ui.Window
+	eventBus   *mvc.EventBus
+	history    *HistoryModel
+	overrides  *storage.OverrideStorage
+	projectDir string
+
+	// editsByResource holds the edited properties, keyed by resource ID
+	// and then by material index.
+	editsByResource map[string]map[int]materialEdit
+}
+
+type materialEdit struct {
+	name       string
+	properties map[string][]float32
+}
+
+// materialChange is a property change in the history, which needs to be
+// discarded once the content of its resource is replaced.
+type materialChange struct {
+	state.Change
+	resource *asset.Resource
+}
+
+// HasChanges returns whether the materials of the specified resource have
+// edits that have not been reverted.
+func (m *MaterialsModel) HasChanges(resource *asset.Resource) bool {
+	return len(m.editsByResource[resource.ID()]) > 0
+}
+
+// ChangeProperty records that the property of the specified material of
+// the resource changed from oldValues to newValues. The change is tracked
+// in the history, so that it can be undone.
+func (m *MaterialsModel) ChangeProperty(resource *asset.Resource, materialIndex int, name string, oldValues, newValues []float32) {
+	m.history.Do(&materialChange{
+		Change: state.ActionChange(0,
+			[]state.Action{
+				func() {
+					m.applyProperty(resource, materialIndex, name, newValues)
+				},
+			},
+			[]state.Action{
+				func() {
+					m.applyProperty(resource, materialIndex, name, oldValues)
+				},
+			},
+		),
+		resource: resource,
+	})
+}
+
+// Revert restores the packed content of the resource and forgets its
+// edits.
+func (m *MaterialsModel) Revert(resource *asset.Resource) {
+	if m.HasChanges(resource) {
+		m.Discard(resource)
+		m.eventBus.Notify(MaterialsChangedEvent{
+			Resource: resource,
+		})
+	}
+}
+
+// DSL returns the edited material properties of the resource as DSL
+// operations, one dsl.EditMaterial call per material, ordered by material
+// index.
+func (m *MaterialsModel) DSL(resource *asset.Resource) string {
+	edits := m.editsByResource[resource.ID()]
+	var builder strings.Builder
+	for _, index := range slices.Sorted(maps.Keys(edits)) {
+		edit := edits[index]
+		fmt.Fprintf(&builder, "dsl.EditMaterial(%q,\n", edit.name)
+		for _, name := range slices.Sorted(maps.Keys(edit.properties)) {
+			operation, ok := propertyOperation(name, edit.properties[name])
+			if !ok {
+				fmt.Fprintf(&builder, "\t// Property %q has %d components and needs to be set by hand.\n", name, len(edit.properties[name]))
+				continue
+			}
+			fmt.Fprintf(&builder, "\t%s,\n", operation.Code)
+		}
+		builder.WriteString("),\n")
+	}
+	return builder.String()
+}
+
+// Copy places the edited material properties of the resource in the
+// clipboard, as DSL operations.
+func (m *MaterialsModel) Copy(resource *asset.Resource) {
+	if m.HasChanges(resource) {
+		m.window.RequestCopy(m.DSL(resource))
+	}
+}
+
+// WriteBack sets the edited material properties of the resource on the
+// dsl.EditMaterial calls of the materials in the sources of the project.
+// The edits remain previewed until the resource is refreshed.
+func (m *MaterialsModel) WriteBack(resource *asset.Resource) error {
+	edits := m.editsByResource[resource.ID()]
+	var errs []error
+	for _, index := range slices.Sorted(maps.Keys(edits)) {
+		edit := edits[index]
+		if edit.name == "" {
+			errs = append(errs, fmt.Errorf("material %d has no name to find it by", index))
+			continue
+		}
+		var operations []dsledit.Operation
+		for _, name := range slices.Sorted(maps.Keys(edit.properties)) {
+			operation, ok := propertyOperation(name, edit.properties[name])
+			if !ok {
+				errs = append(errs, fmt.Errorf("property %q of material %q has %d components", name, edit.name, len(edit.properties[name])))
+				continue
+			}
+			operations = append(operations, operation)
+		}
+		target := dsledit.Call{
+			Function: "EditMaterial",
+			Name:     edit.name,
+		}
+		path, err := dsledit.Apply(m.projectDir, target, operations)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error writing material %q: %w", edit.name, err))
+			continue
+		}
+		log.Info("Wrote properties of material %q to %q", edit.name, path)
+	}
+	return errors.Join(errs...)
+}
+
+// Discard forgets the edits of the specified resource, or of all resources
+// if nil, including the ones in the history, and restores the packed
+// content. This should be used when the content of the resource is
+// replaced, at which point the edits no longer apply to it.
+func (m *MaterialsModel) Discard(resource *asset.Resource) {
+	if resource == nil {
+		for id := range m.editsByResource {
+			m.overrides.ResetContent(id)
+		}
+		clear(m.editsByResource)
+	} else {
+		m.overrides.ResetContent(resource.ID())
+		delete(m.editsByResource, resource.ID())
+	}
+	m.history.Discard(func(change state.Change) bool {
+		materialChange, ok := change.(*materialChange)
+		return ok && (resource == nil || materialChange.resource == resource)
+	})
+}
+
+func (m *MaterialsModel) applyProperty(resource *asset.Resource, materialIndex int, name string, values []float32) {
+	if err := m.setProperty(resource, materialIndex, name, values); err != nil {
+		log.Error("Error changing material property: %v", err)
+	}
+}
+
+func (m *MaterialsModel) setProperty(resource *asset.Resource, materialIndex int, name string, values []float32) error {
+	content, err := resource.OpenContent()
+	if err != nil {
+		return fmt.Errorf("error opening content: %w", err)
+	}
+	if materialIndex < 0 || materialIndex >= len(content.Materials) {
+		return fmt.Errorf("material %d not found", materialIndex)
+	}
+	material := &content.Materials[materialIndex]
+	index := slices.IndexFunc(material.Properties, func(binding asset.PropertyBinding) bool {
+		return binding.BindingName == name
+	})
+	if index < 0 {
+		return fmt.Errorf("property %q not found", name)
+	}
+	material.Properties[index].Data = EncodeMaterialProperty(values)
+
+	if err := m.overrides.OverrideContent(resource.ID(), content); err != nil {
+		return fmt.Errorf("error overriding content: %w", err)
+	}
+
+	edits, ok := m.editsByResource[resource.ID()]
+	if !ok {
+		edits = make(map[int]materialEdit)
+		m.editsByResource[resource.ID()] = edits
+	}
+	edit, ok := edits[materialIndex]
+	if !ok {
+		edit = materialEdit{
+			name:       material.Name,
+			properties: make(map[string][]float32),
+		}
+		edits[materialIndex] = edit
+	}
+	edit.properties[name] = slices.Clone(values)
+
+	m.eventBus.Notify(MaterialsChangedEvent{
+		Resource: resource,
+	})
+	return nil
+}
+
+// propertyOperation returns the DSL operation that sets the property to the
+// specified values, if the DSL supports properties with that many
+// components.
+func propertyOperation(name string, values []float32) (dsledit.Operation, bool) {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = strconv.FormatFloat(float64(value), 'g', -1, 32)
+	}
+	var value string
+	switch len(values) {
+	case 1:
+		value = fmt.Sprintf("float32(%s)", formatted[0])
+	case 2, 3, 4:
+		value = fmt.Sprintf("sprec.NewVec%d(%s)", len(values), strings.Join(formatted, ", "))
+	default:
+		return dsledit.Operation{}, false
+	}
+	return dsledit.Operation{
+		Call: dsledit.Call{
+			Function: "BindProperty",
+			Name:     name,
+		},
+		Code:    fmt.Sprintf("dsl.BindProperty(%q, dsl.Const(%s))", name, value),
+		Imports: []string{"github.com/mokiat/gomath/sprec"},
+	}, true
+}
+
+type MaterialsChangedEvent struct {
+	Resource *asset.Resource
+}
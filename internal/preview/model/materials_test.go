@@ -0,0 +1,126 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+var testMaterialContent = asset.Model{
+	Materials: []asset.Material{
+		{
+			Name: "Paint",
+			Properties: []asset.PropertyBinding{
+				{
+					BindingName: "color",
+					Data:        EncodeMaterialProperty([]float32{1.0, 0.0, 0.0, 1.0}),
+				},
+				{
+					BindingName: "roughness",
+					Data:        EncodeMaterialProperty([]float32{0.5}),
+				},
+			},
+		},
+	},
+}
+
+func colorProperty(t *testing.T, resource *asset.Resource) []float32 {
+	t.Helper()
+	content, err := resource.OpenContent()
+	if err != nil {
+		t.Fatalf("error opening content: %v", err)
+	}
+	return DecodeMaterialProperty(content.Materials[0].Properties[0].Data)
+}
+
+func TestMaterialsRevertRestoresPackedContent(t *testing.T) {
+	resource, overrides, eventBus, history := newTestResource(t, testMaterialContent)
+	materials := NewMaterialsModel(nil, eventBus, history, overrides, t.TempDir())
+
+	materials.ChangeProperty(resource, 0, "color", []float32{1.0, 0.0, 0.0, 1.0}, []float32{0.0, 0.0, 1.0, 1.0})
+	if values := colorProperty(t, resource); !slices.Equal(values, []float32{0.0, 0.0, 1.0, 1.0}) {
+		t.Fatalf("expected edited color, got %v", values)
+	}
+
+	materials.Revert(resource)
+	if values := colorProperty(t, resource); !slices.Equal(values, []float32{1.0, 0.0, 0.0, 1.0}) {
+		t.Fatalf("expected packed color, got %v", values)
+	}
+	if materials.HasChanges(resource) || history.CanUndo() {
+		t.Fatalf("expected no changes after revert")
+	}
+}
+
+func TestMaterialsDiscardDropsHistory(t *testing.T) {
+	resource, overrides, eventBus, history := newTestResource(t, testMaterialContent)
+	materials := NewMaterialsModel(nil, eventBus, history, overrides, t.TempDir())
+
+	materials.ChangeProperty(resource, 0, "color", []float32{1.0, 0.0, 0.0, 1.0}, []float32{0.0, 1.0, 0.0, 1.0})
+	if !history.CanUndo() {
+		t.Fatalf("expected change to be tracked")
+	}
+
+	materials.Discard(nil)
+	if history.CanUndo() {
+		t.Fatalf("expected change to be discarded")
+	}
+	if materials.HasChanges(resource) {
+		t.Fatalf("expected no changes after discard")
+	}
+	if values := colorProperty(t, resource); !slices.Equal(values, []float32{1.0, 0.0, 0.0, 1.0}) {
+		t.Fatalf("expected packed color, got %v", values)
+	}
+}
+
+func TestMaterialsWriteBack(t *testing.T) {
+	resource, overrides, eventBus, history := newTestResource(t, testMaterialContent)
+	projectDir := t.TempDir()
+	materials := NewMaterialsModel(nil, eventBus, history, overrides, projectDir)
+
+	materials.ChangeProperty(resource, 0, "roughness", []float32{0.5}, []float32{0.25})
+	materials.ChangeProperty(resource, 0, "color", []float32{1.0, 0.0, 0.0, 1.0}, []float32{0.0, 0.5, 0.0, 1.0})
+
+	expected := `dsl.EditMaterial("Paint",
+	dsl.BindProperty("color", dsl.Const(sprec.NewVec4(0, 0.5, 0, 1))),
+	dsl.BindProperty("roughness", dsl.Const(float32(0.25))),
+),
+`
+	if text := materials.DSL(resource); text != expected {
+		t.Fatalf("unexpected DSL:\n%s", text)
+	}
+
+	path := filepath.Join(projectDir, "car.go")
+	source := `package main
+
+import "github.com/mokiat/lacking/game/asset/dsl"
+
+var car = dsl.OpenGLTFModel("car.glb",
+	dsl.EditMaterial("Paint",
+		dsl.BindProperty("color", dsl.Const(sprec.NewVec4(1, 0, 0, 1))),
+	),
+)
+`
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("error writing source: %v", err)
+	}
+	if err := materials.WriteBack(resource); err != nil {
+		t.Fatalf("error writing back: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading source: %v", err)
+	}
+	for _, operation := range []string{
+		`dsl.BindProperty("color", dsl.Const(sprec.NewVec4(0, 0.5, 0, 1))),`,
+		`dsl.BindProperty("roughness", dsl.Const(float32(0.25))),`,
+		`"github.com/mokiat/gomath/sprec"`,
+	} {
+		if !strings.Contains(string(data), operation) {
+			t.Fatalf("expected source to contain %s:\n%s", operation, data)
+		}
+	}
+}
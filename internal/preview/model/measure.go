@@ -0,0 +1,169 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/ui/mvc"
+)
+
+const (
+	LengthUnitMeters      LengthUnit = "m"
+	LengthUnitCentimeters LengthUnit = "cm"
+	LengthUnitMillimeters LengthUnit = "mm"
+	LengthUnitFeet        LengthUnit = "ft"
+	LengthUnitInches      LengthUnit = "in"
+)
+
+// LengthUnit is the unit in which measured distances are displayed.
+// Distances in the scene are always in meters.
+type LengthUnit string
+
+// String returns a human-readable name of this unit.
+func (u LengthUnit) String() string {
+	switch u {
+	case LengthUnitMeters:
+		return "Meters"
+	case LengthUnitCentimeters:
+		return "Centimeters"
+	case LengthUnitMillimeters:
+		return "Millimeters"
+	case LengthUnitFeet:
+		return "Feet"
+	case LengthUnitInches:
+		return "Inches"
+	default:
+		return "Unknown"
+	}
+}
+
+// Format returns the specified distance in meters as text in this unit.
+func (u LengthUnit) Format(meters float64) string {
+	switch u {
+	case LengthUnitCentimeters:
+		return fmt.Sprintf("%.1f cm", meters*100.0)
+	case LengthUnitMillimeters:
+		return fmt.Sprintf("%.0f mm", meters*1000.0)
+	case LengthUnitFeet:
+		return fmt.Sprintf("%.2f ft", meters/0.3048)
+	case LengthUnitInches:
+		return fmt.Sprintf("%.1f in", meters/0.0254)
+	default:
+		return fmt.Sprintf("%.3f m", meters)
+	}
+}
+
+// LengthUnits returns all available length units.
+func LengthUnits() []LengthUnit {
+	return []LengthUnit{
+		LengthUnitMeters,
+		LengthUnitCentimeters,
+		LengthUnitMillimeters,
+		LengthUnitFeet,
+		LengthUnitInches,
+	}
+}
+
+// NewMeasureModel creates a new MeasureModel.
+func NewMeasureModel(eventBus *mvc.EventBus, unit LengthUnit) *MeasureModel {
+	if !slices.Contains(LengthUnits(), unit) {
+		unit = LengthUnitMeters
+	}
+	return &MeasureModel{
+		eventBus: eventBus,
+		unit:     unit,
+	}
+}
+
+// MeasureModel holds the state of the ruler tool, which measures the
+// distance between two points on the surface of a model.
+type MeasureModel struct {
+	eventBus *mvc.EventBus
+
+	active         bool
+	showDimensions bool
+	unit           LengthUnit
+
+	resource *asset.Resource
+	points   []dprec.Vec3
+}
+
+// Active returns whether clicking in the viewport places ruler points
+// instead of selecting nodes.
+func (m *MeasureModel) Active() bool {
+	return m.active
+}
+
+func (m *MeasureModel) SetActive(active bool) {
+	if active != m.active {
+		m.active = active
+		m.eventBus.Notify(MeasureChangedEvent{})
+	}
+}
+
+// ShowDimensions returns whether the size of the model along each axis
+// should be displayed.
+func (m *MeasureModel) ShowDimensions() bool {
+	return m.showDimensions
+}
+
+func (m *MeasureModel) SetShowDimensions(show bool) {
+	if show != m.showDimensions {
+		m.showDimensions = show
+		m.eventBus.Notify(MeasureChangedEvent{})
+	}
+}
+
+func (m *MeasureModel) Unit() LengthUnit {
+	return m.unit
+}
+
+func (m *MeasureModel) SetUnit(unit LengthUnit) {
+	if unit != m.unit {
+		m.unit = unit
+		m.eventBus.Notify(MeasureChangedEvent{})
+	}
+}
+
+// AddPoint places a ruler point on the specified resource. A measurement
+// consists of two points, so a third point starts a new measurement.
+func (m *MeasureModel) AddPoint(resource *asset.Resource, point dprec.Vec3) {
+	if resource != m.resource || len(m.points) >= 2 {
+		m.resource = resource
+		m.points = m.points[:0]
+	}
+	m.points = append(m.points, point)
+	m.eventBus.Notify(MeasureChangedEvent{})
+}
+
+// Points returns the ruler points that were placed on the specified
+// resource.
+func (m *MeasureModel) Points(resource *asset.Resource) []dprec.Vec3 {
+	if resource != m.resource {
+		return nil
+	}
+	return m.points
+}
+
+// Distance returns the distance between the two ruler points of the
+// specified resource, if both have been placed.
+func (m *MeasureModel) Distance(resource *asset.Resource) (float64, bool) {
+	points := m.Points(resource)
+	if len(points) < 2 {
+		return 0.0, false
+	}
+	return dprec.Vec3Diff(points[1], points[0]).Length(), true
+}
+
+// Clear removes all ruler points.
+func (m *MeasureModel) Clear() {
+	if len(m.points) > 0 {
+		m.resource = nil
+		m.points = m.points[:0]
+		m.eventBus.Notify(MeasureChangedEvent{})
+	}
+}
+
+type MeasureChangedEvent struct{}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/mokiat/lacking-studio/internal/storage"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/ui/mvc"
+)
+
+// newTestResource creates a resource with the specified content in an
+// in-memory registry, whose content can be overridden through the returned
+// storage.
+func newTestResource(t *testing.T, content asset.Model) (*asset.Resource, *storage.OverrideStorage, *mvc.EventBus, *HistoryModel) {
+	t.Helper()
+	formatter := asset.NewBlobFormatter()
+	overrides := storage.NewOverrideStorage(storage.NewMemoryStorage(), formatter)
+	registry, err := asset.NewRegistry(overrides, formatter)
+	if err != nil {
+		t.Fatalf("error creating registry: %v", err)
+	}
+	resource, err := registry.CreateResource("Model", content)
+	if err != nil {
+		t.Fatalf("error creating resource: %v", err)
+	}
+	eventBus := mvc.NewEventBus()
+	return resource, overrides, eventBus, NewHistoryModel(eventBus)
+}
@@ -0,0 +1,256 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mokiat/lacking-studio/internal/config"
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking-studio/internal/pack"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// PackOutput holds the writers that receive the output of a pack.
+type PackOutput struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Progress receives the lines that report the progress of the pack,
+	// such as the status lines that the DSL logs for each model. It can be
+	// nil.
+	Progress io.Writer
+}
+
+func (o PackOutput) stderrWithProgress() io.Writer {
+	if o.Progress == nil {
+		return o.Stderr
+	}
+	return io.MultiWriter(o.Stderr, o.Progress)
+}
+
+// Packer packs the assets of a project.
+type Packer interface {
+
+	// Pack packs the model with the specified name or all models if the
+	// name is empty. The returned diagnostics are valid even if packing
+	// fails.
+	Pack(ctx context.Context, modelName string, output PackOutput) ([]pack.Diagnostic, error)
+}
+
+// NewPacker creates the Packer that is selected by the project
+// configuration. The newRegistry function is used by the in-process DSL
+// packer, which needs a registry of its own.
+func NewPacker(cfg config.Pack, projectDir string, newRegistry func() (*asset.Registry, error), logBuffer *logs.Buffer) (Packer, error) {
+	switch cfg.Kind {
+	case config.PackKindCommand:
+		return NewCommandPacker(cfg.Command, cfg.ModelArgs, projectDir), nil
+	case config.PackKindDSL:
+		return NewDSLPacker(projectDir, newRegistry, logBuffer), nil
+	case config.PackKindRemote:
+		return NewRemotePacker(cfg.URL), nil
+	default:
+		return nil, fmt.Errorf("unknown pack kind %q", cfg.Kind)
+	}
+}
+
+// NewCommandPacker creates a Packer that runs an external command in the
+// project directory.
+func NewCommandPacker(command, modelArgs []string, projectDir string) *CommandPacker {
+	return &CommandPacker{
+		command:    command,
+		modelArgs:  modelArgs,
+		projectDir: projectDir,
+	}
+}
+
+// CommandPacker is a Packer that runs an external command, such as the
+// pack task of the project.
+type CommandPacker struct {
+	command    []string
+	modelArgs  []string
+	projectDir string
+}
+
+func (p *CommandPacker) Pack(ctx context.Context, modelName string, output PackOutput) ([]pack.Diagnostic, error) {
+	if len(p.command) == 0 {
+		return nil, errors.New("pack command is not specified")
+	}
+	args := slices.Clone(p.command[1:])
+	if modelName != "" {
+		for _, arg := range p.modelArgs {
+			args = append(args, strings.ReplaceAll(arg, config.ModelPlaceholder, modelName))
+		}
+	}
+
+	diagnosticsFile, err := os.CreateTemp("", "studio-diagnostics-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("error creating diagnostics file: %w", err)
+	}
+	diagnosticsFile.Close()
+	defer os.Remove(diagnosticsFile.Name())
+
+	cmd := exec.CommandContext(ctx, p.command[0], args...)
+	cmd.Dir = p.projectDir
+	killProcessGroup(cmd)
+	// The output is copied through pipes, which processes that survive
+	// the cancellation could otherwise keep open indefinitely.
+	cmd.WaitDelay = 5 * time.Second
+	cmd.Env = append(os.Environ(), pack.EnvDiagnosticsFile+"="+diagnosticsFile.Name())
+	cmd.Stdout = output.Stdout
+	cmd.Stderr = output.stderrWithProgress()
+	runErr := cmd.Run()
+
+	// NOTE: Projects that use an older version of the studio do not write
+	// any diagnostics, in which case the file remains empty.
+	var diagnostics []pack.Diagnostic
+	if info, err := os.Stat(diagnosticsFile.Name()); err == nil && info.Size() > 0 {
+		if diagnostics, err = pack.ReadFile(diagnosticsFile.Name()); err != nil {
+			log.Warn("Error reading pack diagnostics: %v", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return diagnostics, fmt.Errorf("pack command was cancelled: %w", ctx.Err())
+	}
+	if runErr != nil {
+		return diagnostics, fmt.Errorf("error running pack command: %w", runErr)
+	}
+	return diagnostics, nil
+}
+
+// NewDSLPacker creates a Packer that runs the DSL that is registered in
+// the studio process.
+func NewDSLPacker(projectDir string, newRegistry func() (*asset.Registry, error), logBuffer *logs.Buffer) *DSLPacker {
+	return &DSLPacker{
+		projectDir:  projectDir,
+		newRegistry: newRegistry,
+		logBuffer:   logBuffer,
+	}
+}
+
+// DSLPacker is a Packer that runs the DSL in-process. It uses a registry
+// of its own, since the registry of the studio is owned by the UI thread.
+//
+// NOTE: The DSL cannot be interrupted, so a cancelled pack returns right
+// away but keeps running in the background. Later packs wait for it.
+type DSLPacker struct {
+	mu          sync.Mutex
+	projectDir  string
+	newRegistry func() (*asset.Registry, error)
+	logBuffer   *logs.Buffer
+}
+
+func (p *DSLPacker) Pack(ctx context.Context, modelName string, output PackOutput) ([]pack.Diagnostic, error) {
+	// NOTE: The DSL logs through the engine logger, which ends up in the
+	// log buffer already, so only the progress is forwarded.
+	if p.logBuffer != nil && output.Progress != nil {
+		unsubscribe := p.logBuffer.Subscribe(func(entry logs.Entry) {
+			io.WriteString(output.Progress, entry.Message+"\n")
+		})
+		defer unsubscribe()
+	}
+
+	type result struct {
+		diagnostics []pack.Diagnostic
+		err         error
+	}
+	resultChan := make(chan result, 1)
+	go func() {
+		diagnostics, err := p.pack(modelName)
+		resultChan <- result{
+			diagnostics: diagnostics,
+			err:         err,
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("pack was cancelled: %w", ctx.Err())
+	case result := <-resultChan:
+		return result.diagnostics, result.err
+	}
+}
+
+func (p *DSLPacker) pack(modelName string) ([]pack.Diagnostic, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	registry, err := p.newRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("error creating registry: %w", err)
+	}
+	var modelNames []string
+	if modelName != "" {
+		modelNames = append(modelNames, modelName)
+	}
+	diagnostics := pack.Run(registry, p.projectDir, modelNames, p.logBuffer)
+	return diagnostics, pack.Error(diagnostics)
+}
+
+// NewRemotePacker creates a Packer that sends pack requests to the pack
+// server at the specified URL.
+func NewRemotePacker(url string) *RemotePacker {
+	return &RemotePacker{
+		url: strings.TrimSuffix(url, "/") + pack.RemotePath,
+	}
+}
+
+// RemotePacker is a Packer that delegates to a pack server. The server
+// needs to have access to the assets directory of the project.
+type RemotePacker struct {
+	url string
+}
+
+func (p *RemotePacker) Pack(ctx context.Context, modelName string, output PackOutput) ([]pack.Diagnostic, error) {
+	body, err := json.Marshal(pack.RemoteRequest{
+		Model: modelName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error contacting pack server: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pack server responded with status %d", response.StatusCode)
+	}
+	// The server streams the output of the pack before the result.
+	decoder := json.NewDecoder(response.Body)
+	stderr := output.stderrWithProgress()
+	var result *pack.RemoteResponse
+	for result == nil {
+		var message pack.RemoteMessage
+		if err := decoder.Decode(&message); err != nil {
+			return nil, fmt.Errorf("error decoding response: %w", err)
+		}
+		if message.Output != "" {
+			io.WriteString(stderr, message.Output+"\n")
+		}
+		result = message.Result
+	}
+	if result.Error != "" {
+		return result.Diagnostics, fmt.Errorf("pack server error: %s", result.Error)
+	}
+	return result.Diagnostics, nil
+}
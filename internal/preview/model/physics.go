@@ -0,0 +1,100 @@
+package model
+
+import "github.com/mokiat/lacking/ui/mvc"
+
+// NewPhysicsModel creates a new PhysicsModel.
+func NewPhysicsModel(eventBus *mvc.EventBus) *PhysicsModel {
+	return &PhysicsModel{
+		eventBus: eventBus,
+	}
+}
+
+// PhysicsModel holds the settings for visualizing and simulating the
+// physics bodies of models.
+type PhysicsModel struct {
+	eventBus *mvc.EventBus
+
+	showSpheres bool
+	showBoxes   bool
+	showMeshes  bool
+
+	simulation bool
+	playing    bool
+}
+
+func (m *PhysicsModel) ShowSpheres() bool {
+	return m.showSpheres
+}
+
+func (m *PhysicsModel) SetShowSpheres(show bool) {
+	if show != m.showSpheres {
+		m.showSpheres = show
+		m.eventBus.Notify(PhysicsSettingsChangedEvent{})
+	}
+}
+
+func (m *PhysicsModel) ShowBoxes() bool {
+	return m.showBoxes
+}
+
+func (m *PhysicsModel) SetShowBoxes(show bool) {
+	if show != m.showBoxes {
+		m.showBoxes = show
+		m.eventBus.Notify(PhysicsSettingsChangedEvent{})
+	}
+}
+
+func (m *PhysicsModel) ShowMeshes() bool {
+	return m.showMeshes
+}
+
+func (m *PhysicsModel) SetShowMeshes(show bool) {
+	if show != m.showMeshes {
+		m.showMeshes = show
+		m.eventBus.Notify(PhysicsSettingsChangedEvent{})
+	}
+}
+
+// Simulation returns whether models should be loaded with bodies that can
+// be simulated.
+func (m *PhysicsModel) Simulation() bool {
+	return m.simulation
+}
+
+func (m *PhysicsModel) SetSimulation(simulation bool) {
+	if simulation != m.simulation {
+		m.simulation = simulation
+		m.playing = false
+		m.eventBus.Notify(PhysicsSettingsChangedEvent{})
+		if !simulation {
+			m.eventBus.Notify(PhysicsResetEvent{})
+		}
+	}
+}
+
+// Playing returns whether the simulation is advancing.
+func (m *PhysicsModel) Playing() bool {
+	return m.playing
+}
+
+func (m *PhysicsModel) SetPlaying(playing bool) {
+	if playing && !m.simulation {
+		return
+	}
+	if playing != m.playing {
+		m.playing = playing
+		m.eventBus.Notify(PhysicsSettingsChangedEvent{})
+	}
+}
+
+// ResetSimulation requests that the bodies are returned to where they
+// were authored.
+func (m *PhysicsModel) ResetSimulation() {
+	m.playing = false
+	m.eventBus.Notify(PhysicsSettingsChangedEvent{})
+	m.eventBus.Notify(PhysicsResetEvent{})
+}
+
+type PhysicsSettingsChangedEvent struct{}
+
+type PhysicsResetEvent struct{}
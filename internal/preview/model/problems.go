@@ -0,0 +1,48 @@
+package model
+
+import (
+	"github.com/mokiat/lacking-studio/internal/pack"
+	"github.com/mokiat/lacking/ui/mvc"
+)
+
+// NewProblemsModel creates a new ProblemsModel.
+func NewProblemsModel(eventBus *mvc.EventBus) *ProblemsModel {
+	return &ProblemsModel{
+		eventBus: eventBus,
+	}
+}
+
+// ProblemsModel holds the diagnostics of the last pack of the assets.
+type ProblemsModel struct {
+	eventBus *mvc.EventBus
+
+	diagnostics []pack.Diagnostic
+}
+
+func (m *ProblemsModel) Diagnostics() []pack.Diagnostic {
+	return m.diagnostics
+}
+
+func (m *ProblemsModel) SetDiagnostics(diagnostics []pack.Diagnostic) {
+	m.diagnostics = diagnostics
+	m.eventBus.Notify(ProblemsChangedEvent{})
+}
+
+// ErrorCount returns the number of diagnostics that are errors.
+func (m *ProblemsModel) ErrorCount() int {
+	var count int
+	for _, diagnostic := range m.diagnostics {
+		if diagnostic.Severity == pack.SeverityError {
+			count++
+		}
+	}
+	return count
+}
+
+// Open opens the source file of the specified diagnostic in the editor
+// of the user.
+func (m *ProblemsModel) Open(diagnostic pack.Diagnostic) error {
+	return OpenInEditor(diagnostic.File, diagnostic.Line)
+}
+
+type ProblemsChangedEvent struct{}
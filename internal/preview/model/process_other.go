@@ -0,0 +1,9 @@
+//go:build !unix
+
+package model
+
+import "os/exec"
+
+// killProcessGroup does nothing on this platform, so only the command
+// itself is killed when its context is cancelled.
+func killProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package model
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup makes the command run in its own process group, which
+// is killed as a whole when the context of the command is cancelled. The
+// pack task starts further processes, which would otherwise keep running.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
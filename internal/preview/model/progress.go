@@ -0,0 +1,140 @@
+package model
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/mokiat/lacking/ui"
+	"github.com/mokiat/lacking/ui/mvc"
+)
+
+const (
+	RefreshStepPacking    RefreshStep = "Packing assets..."
+	RefreshStepReloading  RefreshStep = "Reloading registry..."
+	RefreshStepComparing  RefreshStep = "Comparing statistics..."
+	RefreshStepCancelling RefreshStep = "Cancelling..."
+)
+
+// RefreshStep is the stage that a refresh is in.
+type RefreshStep string
+
+// modelStatusPattern matches the status lines that the DSL logs for each
+// model that it packs.
+var modelStatusPattern = regexp.MustCompile(`Model "([^"]+)" - (processing|building|updating|up to date|done)`)
+
+// NewProgressModel creates a new ProgressModel.
+func NewProgressModel(window *ui.Window, eventBus *mvc.EventBus) *ProgressModel {
+	return &ProgressModel{
+		window:   window,
+		eventBus: eventBus,
+	}
+}
+
+// ProgressModel tracks the progress of a refresh.
+type ProgressModel struct {
+	window   *ui.Window
+	eventBus *mvc.EventBus
+
+	step       RefreshStep
+	model      string
+	total      int
+	started    map[string]struct{}
+	doneModels map[string]struct{}
+}
+
+func (m *ProgressModel) Step() RefreshStep {
+	return m.step
+}
+
+// Model returns the name of the model that was last started.
+func (m *ProgressModel) Model() string {
+	return m.model
+}
+
+// Done returns the number of models that have been packed.
+func (m *ProgressModel) Done() int {
+	return len(m.doneModels)
+}
+
+// Total returns the number of models that are expected to be packed.
+func (m *ProgressModel) Total() int {
+	return max(m.total, len(m.started))
+}
+
+// Percent returns the completed portion of the packing in the range
+// [0.0, 1.0].
+func (m *ProgressModel) Percent() float64 {
+	total := m.Total()
+	if total == 0 {
+		return 0.0
+	}
+	return float64(m.Done()) / float64(total)
+}
+
+// Reset starts tracking a new refresh that is expected to pack the
+// specified number of models.
+func (m *ProgressModel) Reset(total int) {
+	m.step = RefreshStepPacking
+	m.model = ""
+	m.total = total
+	m.started = make(map[string]struct{})
+	m.doneModels = make(map[string]struct{})
+	m.eventBus.Notify(RefreshProgressEvent{})
+}
+
+func (m *ProgressModel) SetStep(step RefreshStep) {
+	if step != m.step {
+		m.step = step
+		m.eventBus.Notify(RefreshProgressEvent{})
+	}
+}
+
+// Writer returns a writer that follows the output of the pack command. It
+// can be written to from any goroutine.
+func (m *ProgressModel) Writer() *ProgressWriter {
+	return &ProgressWriter{
+		model: m,
+	}
+}
+
+func (m *ProgressModel) trackModel(name, status string) {
+	if m.step == RefreshStepCancelling {
+		return
+	}
+	switch status {
+	case "processing":
+		m.started[name] = struct{}{}
+	case "building":
+		m.model = name
+	case "done":
+		m.doneModels[name] = struct{}{}
+	default:
+		return
+	}
+	m.eventBus.Notify(RefreshProgressEvent{})
+}
+
+// ProgressWriter feeds the lines of the pack output to a ProgressModel.
+type ProgressWriter struct {
+	model   *ProgressModel
+	pending []byte
+}
+
+func (w *ProgressWriter) Write(data []byte) (int, error) {
+	w.pending = append(w.pending, data...)
+	for {
+		index := bytes.IndexByte(w.pending, '\n')
+		if index < 0 {
+			return len(data), nil
+		}
+		if match := modelStatusPattern.FindSubmatch(w.pending[:index]); match != nil {
+			name, status := string(match[1]), string(match[2])
+			w.model.window.Schedule(func() {
+				w.model.trackModel(name, status)
+			})
+		}
+		w.pending = w.pending[index+1:]
+	}
+}
+
+type RefreshProgressEvent struct{}
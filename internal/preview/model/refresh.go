@@ -0,0 +1,92 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mokiat/lacking-studio/internal/pack"
+)
+
+const (
+	RefreshStagePack   RefreshStage = "pack"
+	RefreshStageReload RefreshStage = "reload"
+)
+
+// RefreshStage is a part of a refresh that can fail.
+type RefreshStage string
+
+// RefreshError is returned when a stage of a refresh fails. No further
+// stages are run after it.
+type RefreshError struct {
+	Stage RefreshStage
+	Err   error
+}
+
+func (e *RefreshError) Error() string {
+	return fmt.Sprintf("error in %s stage: %v", e.Stage, e.Err)
+}
+
+func (e *RefreshError) Unwrap() error {
+	return e.Err
+}
+
+// Reloader reloads the registry after its assets have been packed.
+type Reloader interface {
+	Reload() error
+}
+
+// ReloaderFunc is a function that implements Reloader.
+type ReloaderFunc func() error
+
+func (f ReloaderFunc) Reload() error {
+	return f()
+}
+
+// refreshFlow runs the stages of a refresh. It does not depend on the
+// window, so that it can be exercised with fake packers and reloaders.
+type refreshFlow struct {
+	packer   Packer
+	reloader Reloader
+	output   PackOutput
+
+	// onDiagnostics receives the diagnostics of the pack stage, whether
+	// it succeeds or not.
+	onDiagnostics func(diagnostics []pack.Diagnostic)
+
+	// onStep is called when a new stage begins.
+	onStep func(step RefreshStep)
+}
+
+// Registry packs all models and then reloads the registry.
+func (f *refreshFlow) Registry(ctx context.Context) error {
+	if err := f.pack(ctx, ""); err != nil {
+		return err
+	}
+	f.onStep(RefreshStepReloading)
+	if err := f.reloader.Reload(); err != nil {
+		return &RefreshError{
+			Stage: RefreshStageReload,
+			Err:   err,
+		}
+	}
+	return nil
+}
+
+// Model packs the model with the specified name. The registry does not
+// need to be reloaded, since the resource of the model already exists.
+func (f *refreshFlow) Model(ctx context.Context, modelName string) error {
+	return f.pack(ctx, modelName)
+}
+
+func (f *refreshFlow) pack(ctx context.Context, modelName string) error {
+	f.onStep(RefreshStepPacking)
+	diagnostics, err := f.packer.Pack(ctx, modelName, f.output)
+	f.onDiagnostics(diagnostics)
+	if err != nil {
+		return &RefreshError{
+			Stage: RefreshStagePack,
+			Err:   err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mokiat/lacking-studio/internal/pack"
+)
+
+type fakePacker struct {
+	diagnostics []pack.Diagnostic
+	err         error
+	modelNames  []string
+}
+
+func (p *fakePacker) Pack(ctx context.Context, modelName string, output PackOutput) ([]pack.Diagnostic, error) {
+	p.modelNames = append(p.modelNames, modelName)
+	return p.diagnostics, p.err
+}
+
+func newTestRefreshFlow(packer Packer, reloader Reloader) (*refreshFlow, *[]RefreshStep, *[]pack.Diagnostic) {
+	var steps []RefreshStep
+	var diagnostics []pack.Diagnostic
+	flow := &refreshFlow{
+		packer:   packer,
+		reloader: reloader,
+		onDiagnostics: func(result []pack.Diagnostic) {
+			diagnostics = result
+		},
+		onStep: func(step RefreshStep) {
+			steps = append(steps, step)
+		},
+	}
+	return flow, &steps, &diagnostics
+}
+
+func TestRefreshFlowSuccess(t *testing.T) {
+	packer := &fakePacker{}
+	reloads := 0
+	flow, steps, _ := newTestRefreshFlow(packer, ReloaderFunc(func() error {
+		reloads++
+		return nil
+	}))
+
+	if err := flow.Registry(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(packer.modelNames) != 1 || packer.modelNames[0] != "" {
+		t.Errorf("expected a single pack of all models, got %q", packer.modelNames)
+	}
+	if reloads != 1 {
+		t.Errorf("expected a single reload, got %d", reloads)
+	}
+	if len(*steps) != 2 || (*steps)[0] != RefreshStepPacking || (*steps)[1] != RefreshStepReloading {
+		t.Errorf("unexpected steps %v", *steps)
+	}
+}
+
+func TestRefreshFlowPackFailure(t *testing.T) {
+	packErr := errors.New("pack failed")
+	packer := &fakePacker{
+		diagnostics: []pack.Diagnostic{
+			{Severity: pack.SeverityError, Message: "broken"},
+		},
+		err: packErr,
+	}
+	reloads := 0
+	flow, steps, diagnostics := newTestRefreshFlow(packer, ReloaderFunc(func() error {
+		reloads++
+		return nil
+	}))
+
+	err := flow.Registry(context.Background())
+	var refreshErr *RefreshError
+	if !errors.As(err, &refreshErr) {
+		t.Fatalf("expected a refresh error, got %v", err)
+	}
+	if refreshErr.Stage != RefreshStagePack {
+		t.Errorf("expected stage %q, got %q", RefreshStagePack, refreshErr.Stage)
+	}
+	if !errors.Is(err, packErr) {
+		t.Errorf("expected the error to wrap the pack error")
+	}
+	if reloads != 0 {
+		t.Errorf("expected no reload, got %d", reloads)
+	}
+	if len(*diagnostics) != 1 {
+		t.Errorf("expected the diagnostics of the failed pack, got %v", *diagnostics)
+	}
+	if len(*steps) != 1 || (*steps)[0] != RefreshStepPacking {
+		t.Errorf("unexpected steps %v", *steps)
+	}
+}
+
+func TestRefreshFlowReloadFailure(t *testing.T) {
+	reloadErr := errors.New("reload failed")
+	flow, _, _ := newTestRefreshFlow(&fakePacker{}, ReloaderFunc(func() error {
+		return reloadErr
+	}))
+
+	err := flow.Registry(context.Background())
+	var refreshErr *RefreshError
+	if !errors.As(err, &refreshErr) {
+		t.Fatalf("expected a refresh error, got %v", err)
+	}
+	if refreshErr.Stage != RefreshStageReload {
+		t.Errorf("expected stage %q, got %q", RefreshStageReload, refreshErr.Stage)
+	}
+	if !errors.Is(err, reloadErr) {
+		t.Errorf("expected the error to wrap the reload error")
+	}
+}
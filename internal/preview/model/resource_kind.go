@@ -0,0 +1,23 @@
+package model
+
+import "github.com/mokiat/lacking/game/asset"
+
+const (
+	ResourceKindModel ResourceKind = iota
+	ResourceKindTexture
+)
+
+// ResourceKind determines how a resource is previewed.
+type ResourceKind int
+
+// ResourceKindOf returns the kind of a resource with the specified content.
+//
+// NOTE: The registry does not track the kind of resources, since all of
+// them are models. Resources that only hold textures are treated as
+// textures.
+func ResourceKindOf(content asset.Model) ResourceKind {
+	if len(content.Nodes) == 0 && len(content.Meshes) == 0 && len(content.Textures) > 0 {
+		return ResourceKindTexture
+	}
+	return ResourceKindModel
+}
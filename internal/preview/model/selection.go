@@ -0,0 +1,51 @@
+package model
+
+import "github.com/mokiat/lacking/ui/mvc"
+
+// NewSelectionModel creates a new SelectionModel.
+func NewSelectionModel(eventBus *mvc.EventBus) *SelectionModel {
+	return &SelectionModel{
+		eventBus: eventBus,
+	}
+}
+
+// SelectionModel tracks the node of the selected resource that the user
+// is currently working with.
+//
+// NOTE: Nodes are identified by their index in the model hierarchy, since
+// names can be empty or repeated.
+type SelectionModel struct {
+	eventBus  *mvc.EventBus
+	nodeIndex int
+	hasNode   bool
+}
+
+// NodeIndex returns the index of the selected node and whether a node is
+// selected at all.
+func (m *SelectionModel) NodeIndex() (int, bool) {
+	return m.nodeIndex, m.hasNode
+}
+
+// HasNode returns whether a node is currently selected.
+func (m *SelectionModel) HasNode() bool {
+	return m.hasNode
+}
+
+func (m *SelectionModel) SetNodeIndex(index int) {
+	if !m.hasNode || index != m.nodeIndex {
+		m.nodeIndex = index
+		m.hasNode = true
+		m.eventBus.Notify(SelectionChangedEvent{})
+	}
+}
+
+// Clear deselects any selected node.
+func (m *SelectionModel) Clear() {
+	if m.hasNode {
+		m.nodeIndex = 0
+		m.hasNode = false
+		m.eventBus.Notify(SelectionChangedEvent{})
+	}
+}
+
+type SelectionChangedEvent struct{}
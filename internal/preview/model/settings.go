@@ -0,0 +1,151 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking-studio/internal/config"
+	"github.com/mokiat/lacking-studio/internal/depgraph"
+	"github.com/mokiat/lacking-studio/internal/theme"
+)
+
+const (
+	settingsDir  = ".studio"
+	settingsFile = "preview.json"
+)
+
+// Settings holds the preview state that is persisted between studio
+// sessions of the same project.
+type Settings struct {
+	SelectedResourceID string   `json:"selected_resource_id,omitempty"`
+	OpenResourceIDs    []string `json:"open_resource_ids,omitempty"`
+	LinkedCameras      bool     `json:"linked_cameras"`
+
+	Theme theme.Name `json:"theme"`
+
+	CameraSectionExpanded bool `json:"camera_section_expanded"`
+	AutoExposure          bool `json:"auto_exposure"`
+
+	SceneSectionExpanded bool `json:"scene_section_expanded"`
+	ShowGrid             bool `json:"show_grid"`
+	ShowAmbientLight     bool `json:"show_ambient_light"`
+	ShowDirectionalLight bool `json:"show_directional_light"`
+	ShowSky              bool `json:"show_sky"`
+	ShowBounds           bool `json:"show_bounds"`
+	ShowNodeBounds       bool `json:"show_node_bounds"`
+	ShowSkeleton         bool `json:"show_skeleton"`
+	ShowNormals          bool `json:"show_normals"`
+	ShowTangents         bool `json:"show_tangents"`
+
+	ComparisonSectionExpanded bool `json:"comparison_section_expanded"`
+	HierarchySectionExpanded  bool `json:"hierarchy_section_expanded"`
+	PropertiesSectionExpanded bool `json:"properties_section_expanded"`
+	TransformSectionExpanded  bool `json:"transform_section_expanded"`
+	MaterialsSectionExpanded  bool `json:"materials_section_expanded"`
+	PhysicsSectionExpanded    bool `json:"physics_section_expanded"`
+	MeasureSectionExpanded    bool `json:"measure_section_expanded"`
+
+	MeasureUnit LengthUnit `json:"measure_unit,omitempty"`
+
+	DependenciesSectionExpanded bool            `json:"dependencies_section_expanded"`
+	DependencyGraphFormat       depgraph.Format `json:"dependency_graph_format,omitempty"`
+
+	ConsoleVisible bool        `json:"console_visible"`
+	ConsoleDock    ConsoleDock `json:"console_dock,omitempty"`
+
+	Camera *CameraSettings `json:"camera,omitempty"`
+}
+
+// CameraSettings holds the orbit position of the preview camera.
+type CameraSettings struct {
+	Position dprec.Vec3  `json:"position"`
+	Yaw      dprec.Angle `json:"yaw"`
+	Pitch    dprec.Angle `json:"pitch"`
+	Zoom     float64     `json:"zoom"`
+}
+
+// DefaultSettings returns the settings that are used when a project
+// is opened for the first time.
+func DefaultSettings() Settings {
+	return Settings{
+		Theme:         theme.NameLight,
+		LinkedCameras: true,
+
+		CameraSectionExpanded: true,
+		AutoExposure:          false,
+
+		SceneSectionExpanded: true,
+		ShowGrid:             true,
+		ShowAmbientLight:     true,
+		ShowDirectionalLight: true,
+		ShowSky:              true,
+
+		HierarchySectionExpanded:  true,
+		PropertiesSectionExpanded: true,
+		TransformSectionExpanded:  true,
+		MaterialsSectionExpanded:  true,
+		PhysicsSectionExpanded:    true,
+		MeasureSectionExpanded:    true,
+
+		MeasureUnit: LengthUnitMeters,
+
+		DependencyGraphFormat: depgraph.FormatDOT,
+
+		ConsoleDock: ConsoleDockBottom,
+	}
+}
+
+// ProjectSettings returns the default settings with the viewport defaults
+// of the project configuration applied.
+func ProjectSettings(viewport config.Viewport) Settings {
+	settings := DefaultSettings()
+	settings.ShowGrid = viewport.ShowGrid
+	settings.ShowSky = viewport.ShowSky
+	settings.ShowAmbientLight = viewport.ShowAmbientLight
+	settings.ShowDirectionalLight = viewport.ShowDirectionalLight
+	settings.AutoExposure = viewport.AutoExposure
+	settings.MeasureUnit = LengthUnit(viewport.MeasureUnit)
+	return settings
+}
+
+// LoadSettings reads the settings of the specified project. If the project
+// has no settings file, the specified defaults are returned.
+func LoadSettings(projectDir string, defaults Settings) (Settings, error) {
+	settings := defaults
+
+	data, err := os.ReadFile(settingsPath(projectDir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return settings, nil
+		}
+		return settings, fmt.Errorf("error reading settings file: %w", err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return defaults, fmt.Errorf("error decoding settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SaveSettings writes the settings of the specified project.
+func SaveSettings(projectDir string, settings Settings) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(projectDir, settingsDir), 0775); err != nil {
+		return fmt.Errorf("error creating settings dir: %w", err)
+	}
+	if err := os.WriteFile(settingsPath(projectDir), data, 0664); err != nil {
+		return fmt.Errorf("error writing settings file: %w", err)
+	}
+	return nil
+}
+
+func settingsPath(projectDir string) string {
+	return filepath.Join(projectDir, settingsDir, settingsFile)
+}
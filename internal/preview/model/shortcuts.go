@@ -0,0 +1,138 @@
+package model
+
+import (
+	"github.com/mokiat/lacking-studio/internal/keymap"
+	"github.com/mokiat/lacking/app"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/ui"
+)
+
+const (
+	ActionRefresh        keymap.Action = "refresh"
+	ActionBack           keymap.Action = "back"
+	ActionQuit           keymap.Action = "quit"
+	ActionUndo           keymap.Action = "undo"
+	ActionRedo           keymap.Action = "redo"
+	ActionToggleGrid     keymap.Action = "toggle_grid"
+	ActionFrameModel     keymap.Action = "frame_model"
+	ActionSwitchViewMode keymap.Action = "switch_view_mode"
+	ActionShowShortcuts  keymap.Action = "show_shortcuts"
+	ActionToolSelect     keymap.Action = "tool_select"
+	ActionToolTranslate  keymap.Action = "tool_translate"
+	ActionToolRotate     keymap.Action = "tool_rotate"
+	ActionToolScale      keymap.Action = "tool_scale"
+)
+
+// NewKeymap creates the keymap of the preview application, applying any
+// overrides from the user keymap file.
+func NewKeymap(os app.OS) *keymap.Keymap {
+	primary := ui.KeyModifierControl
+	if os == app.OSDarwin {
+		primary = ui.KeyModifierSuper
+	}
+
+	result := keymap.New(
+		keymap.Entry{
+			Action:      ActionRefresh,
+			Description: "Refresh",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeF5),
+				keymap.NewBinding(ui.KeyCodeR, primary),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionBack,
+			Description: "Back to registry",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeEscape),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionQuit,
+			Description: "Quit",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeQ, primary),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionUndo,
+			Description: "Undo",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeZ, primary),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionRedo,
+			Description: "Redo",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeZ, primary, ui.KeyModifierShift),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionToggleGrid,
+			Description: "Toggle grid",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeG),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionFrameModel,
+			Description: "Frame model",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeF),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionSwitchViewMode,
+			Description: "Switch view mode",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeV),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionShowShortcuts,
+			Description: "Keyboard shortcuts",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeF1),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionToolSelect,
+			Description: "Select tool",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeQ),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionToolTranslate,
+			Description: "Translate tool",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeW),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionToolRotate,
+			Description: "Rotate tool",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeE),
+			},
+		},
+		keymap.Entry{
+			Action:      ActionToolScale,
+			Description: "Scale tool",
+			Bindings: []keymap.Binding{
+				keymap.NewBinding(ui.KeyCodeR),
+			},
+		},
+	)
+
+	path, err := keymap.DefaultPath()
+	if err != nil {
+		log.Warn("Error locating keymap file: %v", err)
+		return result
+	}
+	if err := result.Load(path); err != nil {
+		log.Warn("Error loading keymap file: %v", err)
+	}
+	return result
+}
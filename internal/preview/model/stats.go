@@ -0,0 +1,99 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// ModelStats summarizes the content of a model resource.
+type ModelStats struct {
+	Nodes          int
+	Meshes         int
+	Geometries     int
+	Vertices       int
+	Triangles      int
+	Textures       int
+	Animations     int
+	Materials      []string
+	BoundingRadius float64
+}
+
+// ComputeModelStats calculates the stats of the specified model content.
+func ComputeModelStats(model *asset.Model) ModelStats {
+	stats := ModelStats{
+		Nodes:      len(model.Nodes),
+		Meshes:     len(model.Meshes),
+		Geometries: len(model.Geometries),
+		Textures:   len(model.Textures),
+		Animations: len(model.Animations),
+		Materials:  make([]string, len(model.Materials)),
+	}
+	for i, material := range model.Materials {
+		stats.Materials[i] = material.Name
+	}
+	slices.Sort(stats.Materials)
+	for _, geometry := range model.Geometries {
+		stats.Vertices += geometryVertexCount(geometry)
+		for _, fragment := range geometry.Fragments {
+			switch fragment.Topology {
+			case asset.TopologyTriangleList:
+				stats.Triangles += int(fragment.IndexCount) / 3
+			case asset.TopologyTriangleStrip:
+				stats.Triangles += max(int(fragment.IndexCount)-2, 0)
+			}
+		}
+		stats.BoundingRadius = dprec.Max(stats.BoundingRadius, geometry.BoundingSphereRadius)
+	}
+	return stats
+}
+
+// DiffModelStats returns a human-readable description of the differences
+// between two model stats, one line per changed value.
+func DiffModelStats(before, after ModelStats) string {
+	var lines []string
+	diffCount := func(label string, oldValue, newValue int) {
+		if oldValue != newValue {
+			lines = append(lines, fmt.Sprintf("%s: %d -> %d (%+d)", label, oldValue, newValue, newValue-oldValue))
+		}
+	}
+	diffCount("Nodes", before.Nodes, after.Nodes)
+	diffCount("Meshes", before.Meshes, after.Meshes)
+	diffCount("Geometries", before.Geometries, after.Geometries)
+	diffCount("Vertices", before.Vertices, after.Vertices)
+	diffCount("Triangles", before.Triangles, after.Triangles)
+	diffCount("Textures", before.Textures, after.Textures)
+	diffCount("Animations", before.Animations, after.Animations)
+	for _, name := range after.Materials {
+		if !slices.Contains(before.Materials, name) {
+			lines = append(lines, fmt.Sprintf("Material added: %s", name))
+		}
+	}
+	for _, name := range before.Materials {
+		if !slices.Contains(after.Materials, name) {
+			lines = append(lines, fmt.Sprintf("Material removed: %s", name))
+		}
+	}
+	if !dprec.EqEps(before.BoundingRadius, after.BoundingRadius, 0.0001) {
+		lines = append(lines, fmt.Sprintf("Bounds radius: %.3f -> %.3f", before.BoundingRadius, after.BoundingRadius))
+	}
+	if len(lines) == 0 {
+		return "No changes in stats."
+	}
+	return strings.Join(lines, "\n")
+}
+
+func geometryVertexCount(geometry asset.Geometry) int {
+	bufferIndex := geometry.VertexLayout.Coord.BufferIndex
+	if bufferIndex == asset.UnspecifiedBufferIndex || int(bufferIndex) >= len(geometry.VertexBuffers) {
+		return 0
+	}
+	buffer := geometry.VertexBuffers[bufferIndex]
+	if buffer.Stride == 0 {
+		return 0
+	}
+	return len(buffer.Data) / int(buffer.Stride)
+}
@@ -0,0 +1,352 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking-studio/internal/dsledit"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/ui"
+	"github.com/mokiat/lacking/ui/mvc"
+	"github.com/mokiat/lacking/ui/state"
+)
+
+const (
+	TransformToolSelect TransformTool = iota
+	TransformToolTranslate
+	TransformToolRotate
+	TransformToolScale
+)
+
+// TransformTool determines what happens when the user drags in the
+// viewport.
+type TransformTool int
+
+// String returns a human-readable name of this tool.
+func (t TransformTool) String() string {
+	switch t {
+	case TransformToolSelect:
+		return "Select"
+	case TransformToolTranslate:
+		return "Translate"
+	case TransformToolRotate:
+		return "Rotate"
+	case TransformToolScale:
+		return "Scale"
+	default:
+		return "Unknown"
+	}
+}
+
+// TransformTools returns all available transform tools.
+func TransformTools() []TransformTool {
+	return []TransformTool{
+		TransformToolSelect,
+		TransformToolTranslate,
+		TransformToolRotate,
+		TransformToolScale,
+	}
+}
+
+// IsEditing returns whether the tool modifies nodes.
+func (t TransformTool) IsEditing() bool {
+	return t != TransformToolSelect
+}
+
+// NodeTransform is the local transformation of a node.
+type NodeTransform struct {
+	Translation dprec.Vec3
+	Rotation    dprec.Quat
+	Scale       dprec.Vec3
+}
+
+// NewTransformsModel creates a new TransformsModel that writes edits back
+// to the sources of the project in the specified directory.
+func NewTransformsModel(window *ui.Window, eventBus *mvc.EventBus, history *HistoryModel, projectDir string) *TransformsModel {
+	return &TransformsModel{
+		window:           window,
+		eventBus:         eventBus,
+		history:          history,
+		projectDir:       projectDir,
+		localSpace:       false,
+		snapping:         false,
+		translationSnap:  0.25,
+		rotationSnap:     dprec.Degrees(15),
+		scaleSnap:        0.1,
+		transformsByNode: make(map[string]map[int]nodeEdit),
+	}
+}
+
+// TransformsModel tracks the tool that is used to edit nodes and the
+// node transformations that have been edited. The edits are previewed
+// until the next refresh and can be written back to the source of the
+// resource or copied as DSL operations.
+type TransformsModel struct {
+	window     *ui.Window
+	eventBus   *mvc.EventBus
+	history    *HistoryModel
+	projectDir string
+
+	tool            TransformTool
+	localSpace      bool
+	snapping        bool
+	translationSnap float64
+	rotationSnap    dprec.Angle
+	scaleSnap       float64
+
+	// transformsByNode holds the edited transforms, keyed by resource ID
+	// and then by content node index, since node names can be empty or
+	// repeated.
+	transformsByNode map[string]map[int]nodeEdit
+}
+
+type nodeEdit struct {
+	name      string
+	path      string
+	transform NodeTransform
+}
+
+// transformChange is a node change in the history, which needs to be
+// discarded once the content of its resource is replaced.
+type transformChange struct {
+	state.Change
+	resource *asset.Resource
+}
+
+func (m *TransformsModel) Tool() TransformTool {
+	return m.tool
+}
+
+func (m *TransformsModel) SetTool(tool TransformTool) {
+	if tool != m.tool {
+		m.tool = tool
+		m.eventBus.Notify(TransformSettingsChangedEvent{})
+	}
+}
+
+// LocalSpace returns whether the handles are aligned with the axes of
+// the selected node instead of the world axes.
+func (m *TransformsModel) LocalSpace() bool {
+	return m.localSpace
+}
+
+func (m *TransformsModel) SetLocalSpace(local bool) {
+	if local != m.localSpace {
+		m.localSpace = local
+		m.eventBus.Notify(TransformSettingsChangedEvent{})
+	}
+}
+
+func (m *TransformsModel) Snapping() bool {
+	return m.snapping
+}
+
+func (m *TransformsModel) SetSnapping(snapping bool) {
+	if snapping != m.snapping {
+		m.snapping = snapping
+		m.eventBus.Notify(TransformSettingsChangedEvent{})
+	}
+}
+
+func (m *TransformsModel) TranslationSnap() float64 {
+	return m.translationSnap
+}
+
+func (m *TransformsModel) RotationSnap() dprec.Angle {
+	return m.rotationSnap
+}
+
+func (m *TransformsModel) ScaleSnap() float64 {
+	return m.scaleSnap
+}
+
+// Transforms returns the edited node transforms of the specified resource,
+// keyed by node index.
+func (m *TransformsModel) Transforms(resource *asset.Resource) map[int]NodeTransform {
+	edits := m.transformsByNode[resource.ID()]
+	result := make(map[int]NodeTransform, len(edits))
+	for index, edit := range edits {
+		result[index] = edit.transform
+	}
+	return result
+}
+
+// HasChanges returns whether the specified resource has edited node
+// transforms.
+func (m *TransformsModel) HasChanges(resource *asset.Resource) bool {
+	return len(m.transformsByNode[resource.ID()]) > 0
+}
+
+// ChangeTransform records that the node of the resource with the specified
+// index in the content changed its transform from oldTransform to
+// newTransform. The change is tracked in the history, so that it can be
+// undone.
+func (m *TransformsModel) ChangeTransform(resource *asset.Resource, content *asset.Model, nodeIndex int, oldTransform, newTransform NodeTransform) {
+	if nodeIndex < 0 || nodeIndex >= len(content.Nodes) {
+		return
+	}
+	edit := nodeEdit{
+		name: content.Nodes[nodeIndex].Name,
+		path: NodePath(content, nodeIndex),
+	}
+	event := NodeTransformChangedEvent{
+		Resource: resource,
+	}
+	m.history.Do(&transformChange{
+		Change: state.ActionChange(0,
+			[]state.Action{
+				func() {
+					edit.transform = newTransform
+					m.setTransform(resource, nodeIndex, edit)
+					m.eventBus.Notify(event)
+				},
+			},
+			[]state.Action{
+				func() {
+					edit.transform = oldTransform
+					m.setTransform(resource, nodeIndex, edit)
+					m.eventBus.Notify(event)
+				},
+			},
+		),
+		resource: resource,
+	})
+}
+
+// DSL returns the edited node transforms of the resource as DSL operations,
+// one group per node, ordered by node index.
+func (m *TransformsModel) DSL(resource *asset.Resource) string {
+	edits := m.transformsByNode[resource.ID()]
+	var builder strings.Builder
+	for _, index := range slices.Sorted(maps.Keys(edits)) {
+		edit := edits[index]
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		fmt.Fprintf(&builder, "// Node %q\n", edit.path)
+		for _, operation := range transformOperations(edit.transform) {
+			fmt.Fprintf(&builder, "%s,\n", operation.Code)
+		}
+	}
+	return builder.String()
+}
+
+// Copy places the edited node transforms of the resource in the
+// clipboard, as DSL operations.
+func (m *TransformsModel) Copy(resource *asset.Resource) {
+	if m.HasChanges(resource) {
+		m.window.RequestCopy(m.DSL(resource))
+	}
+}
+
+// WriteBack sets the edited node transforms of the resource on the
+// dsl.CreateNode calls of the nodes in the sources of the project. The
+// edits remain previewed until the resource is refreshed.
+func (m *TransformsModel) WriteBack(resource *asset.Resource) error {
+	edits := m.transformsByNode[resource.ID()]
+	var errs []error
+	for _, index := range slices.Sorted(maps.Keys(edits)) {
+		edit := edits[index]
+		if edit.name == "" {
+			errs = append(errs, fmt.Errorf("node %q has no name to find it by", edit.path))
+			continue
+		}
+		target := dsledit.Call{
+			Function: "CreateNode",
+			Name:     edit.name,
+		}
+		path, err := dsledit.Apply(m.projectDir, target, transformOperations(edit.transform))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error writing node %q: %w", edit.path, err))
+			continue
+		}
+		log.Info("Wrote transform of node %q to %q", edit.path, path)
+	}
+	return errors.Join(errs...)
+}
+
+// Discard forgets the edits of the specified resource, or of all resources
+// if nil, including the ones in the history. This should be used when the
+// content of the resource is replaced, at which point the node indices may
+// no longer match.
+func (m *TransformsModel) Discard(resource *asset.Resource) {
+	if resource == nil {
+		clear(m.transformsByNode)
+	} else {
+		delete(m.transformsByNode, resource.ID())
+	}
+	m.history.Discard(func(change state.Change) bool {
+		transformChange, ok := change.(*transformChange)
+		return ok && (resource == nil || transformChange.resource == resource)
+	})
+}
+
+func (m *TransformsModel) setTransform(resource *asset.Resource, nodeIndex int, edit nodeEdit) {
+	edits, ok := m.transformsByNode[resource.ID()]
+	if !ok {
+		edits = make(map[int]nodeEdit)
+		m.transformsByNode[resource.ID()] = edits
+	}
+	edits[nodeIndex] = edit
+}
+
+// NodePath returns the names of the node of the content with the specified
+// index and of its ancestors, separated by slashes.
+func NodePath(content *asset.Model, nodeIndex int) string {
+	var names []string
+	for index := nodeIndex; index >= 0 && index < len(content.Nodes); index = int(content.Nodes[index].ParentIndex) {
+		name := content.Nodes[index].Name
+		if name == "" {
+			name = "<unnamed>"
+		}
+		names = append(names, name)
+		if len(names) > len(content.Nodes) {
+			break // cycle in malformed content
+		}
+	}
+	slices.Reverse(names)
+	return strings.Join(names, "/")
+}
+
+func transformOperations(transform NodeTransform) []dsledit.Operation {
+	translation := transform.Translation
+	rotation := transform.Rotation
+	scale := transform.Scale
+	imports := []string{"github.com/mokiat/gomath/dprec"}
+	return []dsledit.Operation{
+		{
+			Call: dsledit.Call{Function: "SetTranslation"},
+			Code: fmt.Sprintf("dsl.SetTranslation(dsl.Const(dprec.NewVec3(%s, %s, %s)))",
+				formatFloat(translation.X), formatFloat(translation.Y), formatFloat(translation.Z)),
+			Imports: imports,
+		},
+		{
+			Call: dsledit.Call{Function: "SetRotation"},
+			Code: fmt.Sprintf("dsl.SetRotation(dsl.Const(dprec.NewQuat(%s, %s, %s, %s)))",
+				formatFloat(rotation.W), formatFloat(rotation.X), formatFloat(rotation.Y), formatFloat(rotation.Z)),
+			Imports: imports,
+		},
+		{
+			Call: dsledit.Call{Function: "SetScale"},
+			Code: fmt.Sprintf("dsl.SetScale(dsl.Const(dprec.NewVec3(%s, %s, %s)))",
+				formatFloat(scale.X), formatFloat(scale.Y), formatFloat(scale.Z)),
+			Imports: imports,
+		},
+	}
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+type TransformSettingsChangedEvent struct{}
+
+type NodeTransformChangedEvent struct {
+	Resource *asset.Resource
+}
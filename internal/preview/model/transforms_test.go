@@ -0,0 +1,88 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+func TestTransformsKeepsDuplicateNames(t *testing.T) {
+	content := asset.Model{
+		Nodes: []asset.Node{
+			{Name: "Car", ParentIndex: asset.UnspecifiedNodeIndex},
+			{Name: "Wheel", ParentIndex: 0},
+			{Name: "", ParentIndex: 0},
+			{Name: "Wheel", ParentIndex: asset.UnspecifiedNodeIndex},
+		},
+	}
+	resource, _, eventBus, history := newTestResource(t, content)
+	transforms := NewTransformsModel(nil, eventBus, history, t.TempDir())
+
+	first := NodeTransform{
+		Translation: dprec.NewVec3(1.0, 0.0, 0.0),
+		Rotation:    dprec.IdentityQuat(),
+		Scale:       dprec.NewVec3(1.0, 1.0, 1.0),
+	}
+	second := NodeTransform{
+		Translation: dprec.NewVec3(-1.0, 0.0, 0.0),
+		Rotation:    dprec.IdentityQuat(),
+		Scale:       dprec.NewVec3(2.0, 2.0, 2.0),
+	}
+	transforms.ChangeTransform(resource, &content, 3, NodeTransform{}, second)
+	transforms.ChangeTransform(resource, &content, 1, NodeTransform{}, first)
+	transforms.ChangeTransform(resource, &content, 2, NodeTransform{}, first)
+
+	result := transforms.Transforms(resource)
+	if len(result) != 3 {
+		t.Fatalf("expected 3 edited nodes, got %d", len(result))
+	}
+	if result[1] != first || result[3] != second {
+		t.Fatalf("expected nodes with the same name to keep separate edits")
+	}
+
+	expected := `// Node "Car/Wheel"
+dsl.SetTranslation(dsl.Const(dprec.NewVec3(1, 0, 0))),
+dsl.SetRotation(dsl.Const(dprec.NewQuat(1, 0, 0, 0))),
+dsl.SetScale(dsl.Const(dprec.NewVec3(1, 1, 1))),
+
+// Node "Car/<unnamed>"
+dsl.SetTranslation(dsl.Const(dprec.NewVec3(1, 0, 0))),
+dsl.SetRotation(dsl.Const(dprec.NewQuat(1, 0, 0, 0))),
+dsl.SetScale(dsl.Const(dprec.NewVec3(1, 1, 1))),
+
+// Node "Wheel"
+dsl.SetTranslation(dsl.Const(dprec.NewVec3(-1, 0, 0))),
+dsl.SetRotation(dsl.Const(dprec.NewQuat(1, 0, 0, 0))),
+dsl.SetScale(dsl.Const(dprec.NewVec3(2, 2, 2))),
+`
+	if text := transforms.DSL(resource); text != expected {
+		t.Fatalf("unexpected DSL:\n%s", text)
+	}
+}
+
+func TestTransformsDiscardDropsHistory(t *testing.T) {
+	content := asset.Model{
+		Nodes: []asset.Node{
+			{Name: "Root", ParentIndex: asset.UnspecifiedNodeIndex},
+		},
+	}
+	resource, _, eventBus, history := newTestResource(t, content)
+	transforms := NewTransformsModel(nil, eventBus, history, t.TempDir())
+
+	transforms.ChangeTransform(resource, &content, 0, NodeTransform{}, NodeTransform{
+		Translation: dprec.NewVec3(1.0, 2.0, 3.0),
+	})
+	history.Undo()
+	if !history.CanRedo() {
+		t.Fatalf("expected change to be redoable")
+	}
+
+	transforms.Discard(nil)
+	if history.CanUndo() || history.CanRedo() {
+		t.Fatalf("expected change to be discarded")
+	}
+	if transforms.HasChanges(resource) {
+		t.Fatalf("expected no changes after discard")
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+const (
+	ViewModePerspective ViewMode = iota
+	ViewModeFront
+	ViewModeSide
+	ViewModeTop
+
+	viewModeCount
+)
+
+// ViewMode determines the orientation from which the model is viewed.
+type ViewMode int
+
+// String returns a human-readable name of this view mode.
+func (m ViewMode) String() string {
+	switch m {
+	case ViewModePerspective:
+		return "Perspective"
+	case ViewModeFront:
+		return "Front"
+	case ViewModeSide:
+		return "Side"
+	case ViewModeTop:
+		return "Top"
+	default:
+		return "Unknown"
+	}
+}
+
+// ViewModes returns all available view modes.
+func ViewModes() []ViewMode {
+	return []ViewMode{
+		ViewModePerspective,
+		ViewModeFront,
+		ViewModeSide,
+		ViewModeTop,
+	}
+}
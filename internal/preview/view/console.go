@@ -0,0 +1,370 @@
+package view
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking-studio/internal/pack"
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/ui"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/layout"
+	"github.com/mokiat/lacking/ui/mvc"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+const (
+	ConsoleHeight = 250
+	ConsoleWidth  = 500
+
+	// consoleMaxRows limits the number of entries that are displayed,
+	// since every one of them is a separate element.
+	consoleMaxRows = 500
+)
+
+// Console displays the log entries that were captured by the studio.
+var Console = mvc.EventListener(co.Define(&consoleComponent{}))
+
+type ConsoleData struct {
+	AppModel *model.AppModel
+}
+
+type consoleComponent struct {
+	co.BaseComponent
+
+	appModel *model.AppModel
+}
+
+func (c *consoleComponent) OnUpsert() {
+	data := co.GetData[ConsoleData](c.Properties())
+	c.appModel = data.AppModel
+}
+
+func (c *consoleComponent) Render() co.Instance {
+	console := c.appModel.Console()
+
+	borderSize := ui.Spacing{
+		Top: 1,
+	}
+	if console.Dock() == model.ConsoleDockRight {
+		borderSize = ui.Spacing{
+			Left: 1,
+		}
+	}
+
+	return co.New(std.Container, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ContainerData{
+			BackgroundColor: opt.V(theme.Surface()),
+			BorderColor:     opt.V(theme.Outline()),
+			BorderSize:      borderSize,
+			Padding:         ui.UniformSpacing(5),
+			Layout: layout.Frame(layout.FrameSettings{
+				ContentSpacing: ui.SymmetricSpacing(0, 5),
+			}),
+		})
+
+		if console.Tab() == model.ConsoleTabProblems {
+			c.renderProblems()
+		} else {
+			c.renderLogs()
+		}
+	})
+}
+
+func (c *consoleComponent) renderLogs() {
+	console := c.appModel.Console()
+	entries := console.Entries()
+	hiddenCount := max(len(entries)-consoleMaxRows, 0)
+	entries = entries[hiddenCount:]
+
+	co.WithChild("header", c.renderHeader(func() {
+		co.WithChild("level", co.New(std.Dropdown, func() {
+			co.WithLayoutData(layout.Data{
+				Width: opt.V(120),
+			})
+			co.WithData(std.DropdownData{
+				Items:       c.levelItems(),
+				SelectedKey: console.MinLevel(),
+			})
+			co.WithCallbackData(std.DropdownCallbackData{
+				OnItemSelected: c.handleLevelSelected,
+			})
+		}))
+
+		co.WithChild("search", co.New(std.EditBox, func() {
+			co.WithLayoutData(layout.Data{
+				Width: opt.V(160),
+			})
+			co.WithData(std.EditBoxData{
+				Text: console.Search(),
+			})
+			co.WithCallbackData(std.EditBoxCallbackData{
+				OnChange: console.SetSearch,
+			})
+		}))
+
+		if console.SinceID() != 0 {
+			co.WithChild("show-all", co.New(std.Button, func() {
+				co.WithData(std.ButtonData{
+					Text: "Show Earlier",
+				})
+				co.WithCallbackData(std.ButtonCallbackData{
+					OnClick: console.ShowAll,
+				})
+			}))
+		}
+
+		co.WithChild("copy", co.New(std.Button, func() {
+			co.WithData(std.ButtonData{
+				Text:    "Copy",
+				Enabled: opt.V(len(entries) > 0),
+			})
+			co.WithCallbackData(std.ButtonCallbackData{
+				OnClick: console.Copy,
+			})
+		}))
+
+		co.WithChild("clear", co.New(std.Button, func() {
+			co.WithData(std.ButtonData{
+				Text: "Clear",
+			})
+			co.WithCallbackData(std.ButtonCallbackData{
+				OnClick: console.Clear,
+			})
+		}))
+	}))
+
+	co.WithChild("entries", co.New(std.ScrollPane, func() {
+		co.WithLayoutData(layout.Data{
+			HorizontalAlignment: layout.HorizontalAlignmentCenter,
+			VerticalAlignment:   layout.VerticalAlignmentCenter,
+		})
+
+		co.WithChild("list", co.New(std.Element, func() {
+			co.WithData(std.ElementData{
+				Layout: layout.Vertical(layout.VerticalSettings{
+					ContentAlignment: layout.HorizontalAlignmentLeft,
+				}),
+			})
+
+			if hiddenCount > 0 {
+				co.WithChild("hidden", c.renderNote(strconv.Itoa(hiddenCount)+" earlier entries are not displayed."))
+			}
+
+			for _, entry := range entries {
+				co.WithChild(strconv.FormatUint(entry.ID, 10), co.New(std.Label, func() {
+					co.WithData(std.LabelData{
+						Font:      co.OpenFont(c.Scope(), "ui:///roboto-mono-regular.ttf"),
+						FontSize:  opt.V(float32(14)),
+						FontColor: opt.V(c.entryColor(entry)),
+						Text:      entry.String(),
+					})
+				}))
+			}
+		}))
+	}))
+}
+
+func (c *consoleComponent) renderProblems() {
+	diagnostics := c.appModel.Problems().Diagnostics()
+
+	co.WithChild("header", c.renderHeader(func() {}))
+
+	co.WithChild("problems", co.New(std.ScrollPane, func() {
+		co.WithLayoutData(layout.Data{
+			HorizontalAlignment: layout.HorizontalAlignmentCenter,
+			VerticalAlignment:   layout.VerticalAlignmentCenter,
+		})
+		co.WithData(std.ScrollPaneData{
+			DisableHorizontal: true,
+		})
+
+		co.WithChild("list", co.New(std.List, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+
+			if len(diagnostics) == 0 {
+				co.WithChild("empty", c.renderNote("No problems were reported by the last refresh."))
+			}
+
+			for i, diagnostic := range diagnostics {
+				co.WithChild(fmt.Sprintf("problem-%d", i), co.New(std.ListItem, func() {
+					co.WithLayoutData(layout.Data{
+						GrowHorizontally: true,
+					})
+					co.WithCallbackData(std.ListItemCallbackData{
+						OnSelected: func() {
+							c.handleProblemSelected(diagnostic)
+						},
+					})
+
+					co.WithChild("text", co.New(std.Label, func() {
+						co.WithData(std.LabelData{
+							Font:      co.OpenFont(c.Scope(), "ui:///roboto-mono-regular.ttf"),
+							FontSize:  opt.V(float32(14)),
+							FontColor: opt.V(c.problemColor(diagnostic)),
+							Text:      diagnostic.String(),
+						})
+					}))
+				}))
+			}
+		}))
+	}))
+}
+
+// renderHeader renders the toolbar of the console, with the controls of
+// the current tab in between the common ones.
+func (c *consoleComponent) renderHeader(tabControls func()) co.Instance {
+	return co.New(std.Element, func() {
+		co.WithLayoutData(layout.Data{
+			VerticalAlignment: layout.VerticalAlignmentTop,
+		})
+		co.WithData(std.ElementData{
+			Layout: layout.Horizontal(layout.HorizontalSettings{
+				ContentAlignment: layout.VerticalAlignmentCenter,
+				ContentSpacing:   5,
+			}),
+		})
+
+		co.WithChild("tab", co.New(std.Dropdown, func() {
+			co.WithLayoutData(layout.Data{
+				Width: opt.V(140),
+			})
+			co.WithData(std.DropdownData{
+				Items:       c.tabItems(),
+				SelectedKey: c.appModel.Console().Tab(),
+			})
+			co.WithCallbackData(std.DropdownCallbackData{
+				OnItemSelected: c.handleTabSelected,
+			})
+		}))
+
+		tabControls()
+
+		co.WithChild("dock", co.New(std.Button, func() {
+			co.WithData(std.ButtonData{
+				Text: c.dockText(),
+			})
+			co.WithCallbackData(std.ButtonCallbackData{
+				OnClick: c.handleDockToggle,
+			})
+		}))
+
+		co.WithChild("close", co.New(std.Button, func() {
+			co.WithData(std.ButtonData{
+				Text: "Close",
+			})
+			co.WithCallbackData(std.ButtonCallbackData{
+				OnClick: c.handleClose,
+			})
+		}))
+	})
+}
+
+func (c *consoleComponent) renderNote(text string) co.Instance {
+	return co.New(std.Label, func() {
+		co.WithData(std.LabelData{
+			Font:      co.OpenFont(c.Scope(), "ui:///roboto-italic.ttf"),
+			FontSize:  opt.V(float32(14)),
+			FontColor: opt.V(theme.OnSurface()),
+			Text:      text,
+		})
+	})
+}
+
+func (c *consoleComponent) OnEvent(event mvc.Event) {
+	switch event.(type) {
+	case model.ConsoleChangedEvent:
+		c.Invalidate()
+	case model.ConsoleLayoutChangedEvent:
+		c.Invalidate()
+	case model.ProblemsChangedEvent:
+		c.Invalidate()
+	}
+}
+
+func (c *consoleComponent) tabItems() []std.DropdownItem {
+	problemsLabel := "Problems"
+	if count := len(c.appModel.Problems().Diagnostics()); count > 0 {
+		problemsLabel = fmt.Sprintf("Problems (%d)", count)
+	}
+	return []std.DropdownItem{
+		{
+			Key:   model.ConsoleTabLogs,
+			Label: "Logs",
+		},
+		{
+			Key:   model.ConsoleTabProblems,
+			Label: problemsLabel,
+		},
+	}
+}
+
+func (c *consoleComponent) handleTabSelected(key any) {
+	c.appModel.Console().SetTab(key.(model.ConsoleTab))
+}
+
+func (c *consoleComponent) levelItems() []std.DropdownItem {
+	levels := logs.Levels()
+	result := make([]std.DropdownItem, len(levels))
+	for i, level := range levels {
+		result[i] = std.DropdownItem{
+			Key:   level,
+			Label: level.String(),
+		}
+	}
+	return result
+}
+
+func (c *consoleComponent) handleLevelSelected(key any) {
+	c.appModel.Console().SetMinLevel(key.(logs.Level))
+}
+
+func (c *consoleComponent) entryColor(entry logs.Entry) ui.Color {
+	if entry.Level >= logs.LevelError {
+		return theme.Error()
+	}
+	return theme.OnSurface()
+}
+
+func (c *consoleComponent) problemColor(diagnostic pack.Diagnostic) ui.Color {
+	if diagnostic.Severity == pack.SeverityError {
+		return theme.Error()
+	}
+	return theme.OnSurface()
+}
+
+func (c *consoleComponent) handleProblemSelected(diagnostic pack.Diagnostic) {
+	if diagnostic.File == "" {
+		return
+	}
+	if err := c.appModel.Problems().Open(diagnostic); err != nil {
+		log.Warn("Error opening %q: %v", diagnostic.File, err)
+	}
+}
+
+func (c *consoleComponent) dockText() string {
+	if c.appModel.Console().Dock() == model.ConsoleDockRight {
+		return "Dock Bottom"
+	}
+	return "Dock Right"
+}
+
+func (c *consoleComponent) handleDockToggle() {
+	console := c.appModel.Console()
+	if console.Dock() == model.ConsoleDockRight {
+		console.SetDock(model.ConsoleDockBottom)
+	} else {
+		console.SetDock(model.ConsoleDockRight)
+	}
+}
+
+func (c *consoleComponent) handleClose() {
+	c.appModel.Console().SetVisible(false)
+}
@@ -0,0 +1,177 @@
+package view
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/depgraph"
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/game/asset"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/layout"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+var Dependencies = co.Define(&dependenciesComponent{})
+
+type DependenciesData struct {
+	AppModel *model.AppModel
+	Resource *asset.Resource
+}
+
+type dependenciesComponent struct {
+	co.BaseComponent
+
+	appModel *model.AppModel
+	resource *asset.Resource
+}
+
+func (c *dependenciesComponent) OnUpsert() {
+	data := co.GetData[DependenciesData](c.Properties())
+	c.appModel = data.AppModel
+	c.resource = data.Resource
+}
+
+func (c *dependenciesComponent) Render() co.Instance {
+	dependencies := c.appModel.Dependencies()
+	graph, sizesLoaded := dependencies.Graph(c.resource)
+
+	return co.New(std.Element, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ElementData{
+			Layout: layout.Vertical(layout.VerticalSettings{
+				ContentAlignment: layout.HorizontalAlignmentLeft,
+				ContentSpacing:   5,
+			}),
+		})
+
+		co.WithChild("summary", co.New(std.Label, func() {
+			co.WithData(std.LabelData{
+				Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
+				FontSize:  opt.V(float32(16)),
+				FontColor: opt.V(theme.OnSurface()),
+				Text:      c.summary(graph, sizesLoaded),
+			})
+		}))
+
+		co.WithChild("list", co.New(std.List, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+
+			for _, node := range c.sortedNodes(graph) {
+				co.WithChild(node.ID, co.New(std.ListItem, func() {
+					co.WithLayoutData(layout.Data{
+						GrowHorizontally: true,
+					})
+					co.WithData(std.ListItemData{
+						Selected: node.ID == c.resource.ID(),
+					})
+					co.WithCallbackData(std.ListItemCallbackData{
+						OnSelected: func() {
+							c.handleNodeSelected(node)
+						},
+					})
+
+					co.WithChild("text", co.New(std.Label, func() {
+						co.WithData(std.LabelData{
+							Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
+							FontSize:  opt.V(float32(14)),
+							FontColor: opt.V(theme.OnSurface()),
+							Text:      c.nodeText(node, sizesLoaded),
+						})
+					}))
+				}))
+			}
+		}))
+
+		co.WithChild("export", co.New(std.Element, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+			co.WithData(std.ElementData{
+				Layout: layout.Horizontal(layout.HorizontalSettings{
+					ContentAlignment: layout.VerticalAlignmentCenter,
+					ContentSpacing:   5,
+				}),
+			})
+
+			co.WithChild("format", co.New(std.Dropdown, func() {
+				co.WithLayoutData(layout.Data{
+					Width: opt.V(140),
+				})
+				co.WithData(std.DropdownData{
+					Items:       c.formatItems(),
+					SelectedKey: dependencies.Format(),
+				})
+				co.WithCallbackData(std.DropdownCallbackData{
+					OnItemSelected: c.handleFormatSelected,
+				})
+			}))
+
+			co.WithChild("copy", co.New(std.Button, func() {
+				co.WithData(std.ButtonData{
+					Text: "Copy",
+				})
+				co.WithCallbackData(std.ButtonCallbackData{
+					OnClick: c.handleCopy,
+				})
+			}))
+		}))
+	})
+}
+
+func (c *dependenciesComponent) summary(graph depgraph.Graph, sizesLoaded bool) string {
+	count := len(graph.Nodes) - 1
+	if !sizesLoaded {
+		return fmt.Sprintf("%d dependencies, measuring sizes...", count)
+	}
+	return fmt.Sprintf("%d dependencies, %s in total", count, depgraph.FormatSize(graph.TotalSize()))
+}
+
+// sortedNodes returns the nodes of the graph with the largest ones first,
+// so that the resources that contribute the most stand out.
+func (c *dependenciesComponent) sortedNodes(graph depgraph.Graph) []depgraph.Node {
+	nodes := slices.Clone(graph.Nodes)
+	slices.SortStableFunc(nodes, func(a, b depgraph.Node) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+	return nodes
+}
+
+func (c *dependenciesComponent) nodeText(node depgraph.Node, sizesLoaded bool) string {
+	if !sizesLoaded {
+		return node.Name
+	}
+	return fmt.Sprintf("%s (%s)", node.Name, depgraph.FormatSize(node.Size))
+}
+
+func (c *dependenciesComponent) formatItems() []std.DropdownItem {
+	formats := depgraph.Formats()
+	result := make([]std.DropdownItem, len(formats))
+	for i, format := range formats {
+		result[i] = std.DropdownItem{
+			Key:   format,
+			Label: strings.ToUpper(string(format[:1])) + string(format[1:]),
+		}
+	}
+	return result
+}
+
+func (c *dependenciesComponent) handleFormatSelected(key any) {
+	c.appModel.Dependencies().SetFormat(key.(depgraph.Format))
+}
+
+func (c *dependenciesComponent) handleCopy() {
+	c.appModel.Dependencies().Copy(c.resource)
+}
+
+func (c *dependenciesComponent) handleNodeSelected(node depgraph.Node) {
+	if resource := c.appModel.ResourceByID(node.ID); resource != nil {
+		c.appModel.SetSelectedResource(resource)
+	}
+}
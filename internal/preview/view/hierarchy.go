@@ -0,0 +1,132 @@
+package view
+
+import (
+	"fmt"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/game/hierarchy"
+	"github.com/mokiat/lacking/ui"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/layout"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+const (
+	hierarchyHeight      = 200
+	hierarchyIndentation = 12
+)
+
+var Hierarchy = co.Define(&hierarchyComponent{})
+
+type HierarchyData struct {
+	Root         *hierarchy.Node
+	SelectedNode *hierarchy.Node
+}
+
+type HierarchyCallbackData struct {
+	OnSelected func(node *hierarchy.Node)
+}
+
+type hierarchyComponent struct {
+	co.BaseComponent
+
+	root         *hierarchy.Node
+	selectedNode *hierarchy.Node
+
+	onSelected func(node *hierarchy.Node)
+}
+
+func (c *hierarchyComponent) OnUpsert() {
+	data := co.GetData[HierarchyData](c.Properties())
+	c.root = data.Root
+	c.selectedNode = data.SelectedNode
+
+	callbackData := co.GetCallbackData[HierarchyCallbackData](c.Properties())
+	c.onSelected = callbackData.OnSelected
+}
+
+func (c *hierarchyComponent) Render() co.Instance {
+	return co.New(std.ScrollPane, func() {
+		co.WithLayoutData(layout.Data{
+			GrowHorizontally: true,
+			Height:           opt.V(hierarchyHeight),
+		})
+		co.WithData(std.ScrollPaneData{
+			DisableHorizontal: true,
+		})
+
+		co.WithChild("list", co.New(std.List, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+
+			if c.root == nil {
+				return
+			}
+			var index int
+			c.visit(c.root.FirstChild(), 0, func(node *hierarchy.Node, depth int) {
+				co.WithChild(fmt.Sprintf("node-%d", index), c.renderItem(node, depth))
+				index++
+			})
+		}))
+	})
+}
+
+func (c *hierarchyComponent) renderItem(node *hierarchy.Node, depth int) co.Instance {
+	return co.New(std.ListItem, func() {
+		co.WithLayoutData(layout.Data{
+			GrowHorizontally: true,
+		})
+		co.WithData(std.ListItemData{
+			Selected: node == c.selectedNode,
+		})
+		co.WithCallbackData(std.ListItemCallbackData{
+			OnSelected: func() {
+				c.onSelected(node)
+			},
+		})
+
+		co.WithChild("item", co.New(std.Element, func() {
+			co.WithData(std.ElementData{
+				Padding: ui.Spacing{
+					Left: depth * hierarchyIndentation,
+				},
+				Layout: layout.Frame(),
+			})
+
+			co.WithChild("name", co.New(std.Label, func() {
+				co.WithLayoutData(layout.Data{
+					HorizontalAlignment: layout.HorizontalAlignmentLeft,
+				})
+				co.WithData(std.LabelData{
+					Font:      co.OpenFont(c.Scope(), c.fontFile(node)),
+					FontSize:  opt.V(float32(16)),
+					FontColor: opt.V(theme.OnSurface()),
+					Text:      nodeDisplayName(node),
+				})
+			}))
+		}))
+	})
+}
+
+func (c *hierarchyComponent) visit(node *hierarchy.Node, depth int, callback func(node *hierarchy.Node, depth int)) {
+	for ; node != nil; node = node.RightSibling() {
+		callback(node, depth)
+		c.visit(node.FirstChild(), depth+1, callback)
+	}
+}
+
+func (c *hierarchyComponent) fontFile(node *hierarchy.Node) string {
+	if node == c.selectedNode {
+		return "ui:///roboto-bold.ttf"
+	}
+	return "ui:///roboto-regular.ttf"
+}
+
+func nodeDisplayName(node *hierarchy.Node) string {
+	if name := node.Name(); name != "" {
+		return name
+	}
+	return "<unnamed>"
+}
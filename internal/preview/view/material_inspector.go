@@ -0,0 +1,228 @@
+package view
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/game/asset"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/layout"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+const materialFieldWidth = 55
+
+var MaterialInspector = co.Define(&materialInspectorComponent{})
+
+type MaterialInspectorData struct {
+	AppModel *model.AppModel
+	Resource *asset.Resource
+	Content  *asset.Model
+}
+
+type materialInspectorComponent struct {
+	co.BaseComponent
+
+	appModel *model.AppModel
+	resource *asset.Resource
+	content  *asset.Model
+}
+
+func (c *materialInspectorComponent) OnUpsert() {
+	data := co.GetData[MaterialInspectorData](c.Properties())
+	c.appModel = data.AppModel
+	c.resource = data.Resource
+	c.content = data.Content
+}
+
+func (c *materialInspectorComponent) Render() co.Instance {
+	materials := c.appModel.Materials()
+	return co.New(std.Element, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ElementData{
+			Layout: layout.Vertical(layout.VerticalSettings{
+				ContentAlignment: layout.HorizontalAlignmentLeft,
+				ContentSpacing:   10,
+			}),
+		})
+
+		if c.content == nil || len(c.content.Materials) == 0 {
+			co.WithChild("empty", c.renderText("No materials.", false))
+			return
+		}
+
+		for i, material := range c.content.Materials {
+			co.WithChild(fmt.Sprintf("material-%d", i), c.renderMaterial(i, material))
+		}
+
+		co.WithChild("write", co.New(std.Button, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+			co.WithData(std.ButtonData{
+				Text:    "Write to Source",
+				Enabled: opt.V(materials.HasChanges(c.resource)),
+			})
+			co.WithCallbackData(std.ButtonCallbackData{
+				OnClick: c.handleWriteBack,
+			})
+		}))
+
+		co.WithChild("copy", co.New(std.Button, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+			co.WithData(std.ButtonData{
+				Text:    "Copy as DSL",
+				Enabled: opt.V(materials.HasChanges(c.resource)),
+			})
+			co.WithCallbackData(std.ButtonCallbackData{
+				OnClick: c.handleCopy,
+			})
+		}))
+
+		co.WithChild("revert", co.New(std.Button, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+			co.WithData(std.ButtonData{
+				Text:    "Revert Materials",
+				Enabled: opt.V(materials.HasChanges(c.resource)),
+			})
+			co.WithCallbackData(std.ButtonCallbackData{
+				OnClick: c.handleRevert,
+			})
+		}))
+	})
+}
+
+func (c *materialInspectorComponent) renderMaterial(index int, material asset.Material) co.Instance {
+	return co.New(std.Element, func() {
+		co.WithLayoutData(layout.Data{
+			GrowHorizontally: true,
+		})
+		co.WithData(std.ElementData{
+			Layout: layout.Vertical(layout.VerticalSettings{
+				ContentAlignment: layout.HorizontalAlignmentLeft,
+				ContentSpacing:   5,
+			}),
+		})
+
+		name := material.Name
+		if name == "" {
+			name = "<unnamed>"
+		}
+		co.WithChild("name", c.renderText(name, true))
+
+		shaderTypes := model.MaterialShaderTypes(material)
+		if len(shaderTypes) == 0 {
+			shaderTypes = []string{"None"}
+		}
+		co.WithChild("shader", c.renderText("Shader: "+strings.Join(shaderTypes, ", "), false))
+
+		for i, binding := range material.Textures {
+			co.WithChild(fmt.Sprintf("texture-%d", i), c.renderText(
+				fmt.Sprintf("Texture %s: %s", binding.BindingName, c.textureDescription(binding.TextureIndex)),
+				false,
+			))
+		}
+
+		for i, binding := range material.Properties {
+			co.WithChild(fmt.Sprintf("property-%d", i), c.renderProperty(index, binding))
+		}
+	})
+}
+
+func (c *materialInspectorComponent) renderProperty(materialIndex int, binding asset.PropertyBinding) co.Instance {
+	values := model.DecodeMaterialProperty(binding.Data)
+	return co.New(std.Element, func() {
+		co.WithLayoutData(layout.Data{
+			GrowHorizontally: true,
+		})
+		co.WithData(std.ElementData{
+			Layout: layout.Vertical(layout.VerticalSettings{
+				ContentAlignment: layout.HorizontalAlignmentLeft,
+				ContentSpacing:   2,
+			}),
+		})
+
+		co.WithChild("label", c.renderText(binding.BindingName, false))
+
+		co.WithChild("fields", co.New(std.Element, func() {
+			co.WithData(std.ElementData{
+				Layout: layout.Horizontal(layout.HorizontalSettings{
+					ContentAlignment: layout.VerticalAlignmentCenter,
+					ContentSpacing:   5,
+				}),
+			})
+
+			for i, value := range values {
+				co.WithChild(fmt.Sprintf("field-%d", i), co.New(std.EditBox, func() {
+					co.WithLayoutData(layout.Data{
+						Width: opt.V(materialFieldWidth),
+					})
+					co.WithData(std.EditBoxData{
+						Text: strconv.FormatFloat(float64(value), 'f', 3, 32),
+					})
+					co.WithCallbackData(std.EditBoxCallbackData{
+						OnSubmit: func(text string) {
+							newValue, err := strconv.ParseFloat(strings.TrimSpace(text), 32)
+							if err != nil {
+								c.Invalidate() // restore the previous value
+								return
+							}
+							newValues := make([]float32, len(values))
+							copy(newValues, values)
+							newValues[i] = float32(newValue)
+							c.appModel.Materials().ChangeProperty(c.resource, materialIndex, binding.BindingName, values, newValues)
+						},
+					})
+				}))
+			}
+		}))
+	})
+}
+
+func (c *materialInspectorComponent) renderText(text string, bold bool) co.Instance {
+	font := "ui:///roboto-regular.ttf"
+	if bold {
+		font = "ui:///roboto-bold.ttf"
+	}
+	return co.New(std.Label, func() {
+		co.WithData(std.LabelData{
+			Font:      co.OpenFont(c.Scope(), font),
+			FontSize:  opt.V(float32(16)),
+			FontColor: opt.V(theme.OnSurface()),
+			Text:      text,
+		})
+	})
+}
+
+func (c *materialInspectorComponent) textureDescription(index uint32) string {
+	if int(index) >= len(c.content.Textures) {
+		return fmt.Sprintf("#%d (missing)", index)
+	}
+	texture := c.content.Textures[index]
+	if len(texture.MipmapLayers) == 0 {
+		return fmt.Sprintf("#%d", index)
+	}
+	layer := texture.MipmapLayers[0]
+	return fmt.Sprintf("#%d (%dx%d)", index, layer.Width, layer.Height)
+}
+
+func (c *materialInspectorComponent) handleWriteBack() {
+	err := c.appModel.Materials().WriteBack(c.resource)
+	openWriteBackResult(c.Scope(), err, c.handleCopy)
+}
+
+func (c *materialInspectorComponent) handleCopy() {
+	c.appModel.Materials().Copy(c.resource)
+}
+
+func (c *materialInspectorComponent) handleRevert() {
+	c.appModel.Materials().Revert(c.resource)
+}
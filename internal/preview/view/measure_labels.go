@@ -0,0 +1,98 @@
+package view
+
+import (
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/ui"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+const (
+	measureLabelFontSize = float32(16)
+	measureLabelPadding  = float32(4)
+	measureLabelOffset   = float32(8)
+)
+
+// MeasureLabels draws text labels for the ruler tool on top of the
+// viewport canvas.
+var MeasureLabels = co.Define(&measureLabelsComponent{})
+
+// MeasureLabel is a text that is displayed above a pixel of the canvas.
+type MeasureLabel struct {
+	X    float64
+	Y    float64
+	Text string
+}
+
+type MeasureLabelsCallbackData struct {
+	// OnLabels returns the labels for a canvas with the specified size. It
+	// is called on every frame, since the labels follow the camera.
+	OnLabels func(size ui.Size) []MeasureLabel
+
+	// OnMouseEvent receives all mouse events, since the labels are drawn
+	// over the whole canvas.
+	OnMouseEvent func(element *ui.Element, event ui.MouseEvent) bool
+}
+
+type measureLabelsComponent struct {
+	co.BaseComponent
+
+	font *ui.Font
+
+	onLabels     func(size ui.Size) []MeasureLabel
+	onMouseEvent func(element *ui.Element, event ui.MouseEvent) bool
+}
+
+func (c *measureLabelsComponent) OnCreate() {
+	c.font = co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf")
+}
+
+func (c *measureLabelsComponent) OnUpsert() {
+	callbackData := co.GetOptionalCallbackData(c.Properties(), MeasureLabelsCallbackData{})
+	c.onLabels = callbackData.OnLabels
+	if c.onLabels == nil {
+		c.onLabels = func(ui.Size) []MeasureLabel { return nil }
+	}
+	c.onMouseEvent = callbackData.OnMouseEvent
+	if c.onMouseEvent == nil {
+		c.onMouseEvent = func(*ui.Element, ui.MouseEvent) bool { return false }
+	}
+}
+
+func (c *measureLabelsComponent) Render() co.Instance {
+	return co.New(std.Element, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ElementData{
+			Essence: c,
+		})
+	})
+}
+
+func (c *measureLabelsComponent) OnMouseEvent(element *ui.Element, event ui.MouseEvent) bool {
+	return c.onMouseEvent(element, event)
+}
+
+func (c *measureLabelsComponent) OnRender(element *ui.Element, canvas *ui.Canvas) {
+	for _, label := range c.onLabels(element.Bounds().Size) {
+		text := []rune(label.Text)
+		textSize := c.font.TextSize(label.Text, measureLabelFontSize)
+		boxSize := sprec.Vec2Sum(textSize, sprec.NewVec2(2*measureLabelPadding, 2*measureLabelPadding))
+		boxPosition := sprec.NewVec2(
+			float32(label.X)-boxSize.X/2.0,
+			float32(label.Y)-boxSize.Y-measureLabelOffset,
+		)
+
+		canvas.Reset()
+		canvas.Rectangle(boxPosition, boxSize)
+		canvas.Fill(ui.Fill{
+			Color: theme.Surface(),
+		})
+
+		canvas.FillTextLine(text, sprec.Vec2Sum(boxPosition, sprec.NewVec2(measureLabelPadding, measureLabelPadding)), ui.Typography{
+			Font:  c.font,
+			Size:  measureLabelFontSize,
+			Color: theme.OnSurface(),
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package view
+
+import "github.com/mokiat/lacking/ui"
+
+// panesLayout positions the visible children of an element side by side,
+// each taking an equal share of the width. Hidden children are collapsed
+// so that they don't take up any space.
+func panesLayout() ui.Layout {
+	return &panesLayoutImpl{}
+}
+
+type panesLayoutImpl struct{}
+
+func (l *panesLayoutImpl) Apply(element *ui.Element) {
+	contentBounds := element.ContentBounds()
+
+	var visibleCount int
+	for childElement := element.FirstChild(); childElement != nil; childElement = childElement.RightSibling() {
+		if childElement.Visible() {
+			visibleCount++
+		}
+	}
+
+	var offset int
+	for childElement := element.FirstChild(); childElement != nil; childElement = childElement.RightSibling() {
+		if !childElement.Visible() {
+			childElement.SetBounds(ui.Bounds{
+				Position: contentBounds.Position,
+			})
+			continue
+		}
+		width := contentBounds.Width / visibleCount
+		childElement.SetBounds(ui.Bounds{
+			Position: ui.NewPosition(contentBounds.X+offset, contentBounds.Y),
+			Size:     ui.NewSize(width, contentBounds.Height),
+		})
+		offset += width
+	}
+
+	element.SetIdealSize(element.Padding().Size())
+}
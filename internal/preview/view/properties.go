@@ -0,0 +1,114 @@
+package view
+
+import (
+	"fmt"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/game/hierarchy"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/layout"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+var Properties = co.Define(&propertiesComponent{})
+
+type PropertiesData struct {
+	Node *hierarchy.Node
+}
+
+type propertiesComponent struct {
+	co.BaseComponent
+
+	node *hierarchy.Node
+}
+
+type property struct {
+	Name  string
+	Value string
+}
+
+func (c *propertiesComponent) OnUpsert() {
+	data := co.GetData[PropertiesData](c.Properties())
+	c.node = data.Node
+}
+
+func (c *propertiesComponent) Render() co.Instance {
+	return co.New(std.Element, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ElementData{
+			Layout: layout.Vertical(layout.VerticalSettings{
+				ContentAlignment: layout.HorizontalAlignmentLeft,
+				ContentSpacing:   5,
+			}),
+		})
+
+		if c.node == nil {
+			co.WithChild("empty", co.New(std.Label, func() {
+				co.WithData(std.LabelData{
+					Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
+					FontSize:  opt.V(float32(16)),
+					FontColor: opt.V(theme.OnSurface()),
+					Text:      "No node selected.",
+				})
+			}))
+			return
+		}
+
+		for i, prop := range c.properties() {
+			co.WithChild(fmt.Sprintf("property-%d", i), co.New(std.Element, func() {
+				co.WithLayoutData(layout.Data{
+					GrowHorizontally: true,
+				})
+				co.WithData(std.ElementData{
+					Layout: layout.Frame(),
+				})
+
+				co.WithChild("name", co.New(std.Label, func() {
+					co.WithLayoutData(layout.Data{
+						HorizontalAlignment: layout.HorizontalAlignmentLeft,
+					})
+					co.WithData(std.LabelData{
+						Font:      co.OpenFont(c.Scope(), "ui:///roboto-bold.ttf"),
+						FontSize:  opt.V(float32(16)),
+						FontColor: opt.V(theme.OnSurface()),
+						Text:      prop.Name,
+					})
+				}))
+
+				co.WithChild("value", co.New(std.Label, func() {
+					co.WithLayoutData(layout.Data{
+						HorizontalAlignment: layout.HorizontalAlignmentRight,
+					})
+					co.WithData(std.LabelData{
+						Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
+						FontSize:  opt.V(float32(16)),
+						FontColor: opt.V(theme.OnSurface()),
+						Text:      prop.Value,
+					})
+				}))
+			}))
+		}
+	})
+}
+
+func (c *propertiesComponent) properties() []property {
+	rotationX, rotationY, rotationZ := c.node.Rotation().EulerAngles(dprec.RotationOrderGlobalXYZ)
+	var childCount int
+	for child := c.node.FirstChild(); child != nil; child = child.RightSibling() {
+		childCount++
+	}
+	return []property{
+		{Name: "Name", Value: nodeDisplayName(c.node)},
+		{Name: "Position", Value: formatVec3(c.node.Position())},
+		{Name: "Rotation", Value: fmt.Sprintf("%.1f°, %.1f°, %.1f°", rotationX.Degrees(), rotationY.Degrees(), rotationZ.Degrees())},
+		{Name: "Scale", Value: formatVec3(c.node.Scale())},
+		{Name: "World Position", Value: formatVec3(c.node.AbsoluteMatrix().Translation())},
+		{Name: "Children", Value: fmt.Sprintf("%d", childCount)},
+	}
+}
+
+func formatVec3(vector dprec.Vec3) string {
+	return fmt.Sprintf("%.3f, %.3f, %.3f", vector.X, vector.Y, vector.Z)
+}
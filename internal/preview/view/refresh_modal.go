@@ -0,0 +1,62 @@
+package view
+
+import (
+	"fmt"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/widget"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/mvc"
+)
+
+// RefreshModal displays the progress of a refresh and allows it to be
+// cancelled.
+var RefreshModal = mvc.EventListener(co.Define(&refreshModalComponent{}))
+
+type RefreshModalData struct {
+	AppModel *model.AppModel
+}
+
+type refreshModalComponent struct {
+	co.BaseComponent
+
+	appModel *model.AppModel
+}
+
+func (c *refreshModalComponent) OnUpsert() {
+	data := co.GetData[RefreshModalData](c.Properties())
+	c.appModel = data.AppModel
+}
+
+func (c *refreshModalComponent) Render() co.Instance {
+	progress := c.appModel.Progress()
+
+	var detail string
+	if name := progress.Model(); name != "" && progress.Step() == model.RefreshStepPacking {
+		detail = fmt.Sprintf("%s (%d of %d)", name, progress.Done(), progress.Total())
+	}
+
+	var cancel func()
+	if progress.Step() != model.RefreshStepCancelling {
+		cancel = c.appModel.CancelRefresh
+	}
+
+	return co.New(widget.LoadingModal, func() {
+		co.WithData(widget.LoadingModalData{
+			Text:     string(progress.Step()),
+			Detail:   detail,
+			Progress: opt.V(progress.Percent()),
+		})
+		co.WithCallbackData(widget.LoadingModalCallbackData{
+			OnCancel: cancel,
+		})
+	})
+}
+
+func (c *refreshModalComponent) OnEvent(event mvc.Event) {
+	switch event.(type) {
+	case model.RefreshProgressEvent:
+		c.Invalidate()
+	}
+}
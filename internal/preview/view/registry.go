@@ -5,6 +5,7 @@ import (
 
 	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/theme"
 	"github.com/mokiat/lacking/game/asset"
 	"github.com/mokiat/lacking/ui"
 	co "github.com/mokiat/lacking/ui/component"
@@ -36,7 +37,7 @@ func (c *registryComponent) Render() co.Instance {
 	return co.New(std.Container, func() {
 		co.WithLayoutData(c.Properties().LayoutData())
 		co.WithData(std.ContainerData{
-			BackgroundColor: opt.V(std.SurfaceColor),
+			BackgroundColor: opt.V(theme.Surface()),
 			Layout:          layout.Anchor(),
 			Padding:         ui.SymmetricSpacing(100, 20),
 		})
@@ -87,7 +88,7 @@ func (c *registryComponent) Render() co.Instance {
 						Height: opt.V(1),
 					})
 					co.WithData(std.ContainerData{
-						BackgroundColor: opt.V(std.OutlineColor),
+						BackgroundColor: opt.V(theme.Outline()),
 					})
 				}))
 			}))
@@ -2,6 +2,7 @@ package view
 
 import (
 	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/theme"
 	"github.com/mokiat/lacking/game/asset"
 	"github.com/mokiat/lacking/ui"
 	co "github.com/mokiat/lacking/ui/component"
@@ -66,7 +67,7 @@ func (c *itemComponent) Render() co.Instance {
 					co.WithData(std.LabelData{
 						Font:      co.OpenFont(c.Scope(), "ui:///roboto-bold.ttf"),
 						FontSize:  opt.V(float32(16)),
-						FontColor: opt.V(ui.Black()),
+						FontColor: opt.V(theme.OnSurface()),
 						Text:      c.resource.Name(),
 					})
 				}))
@@ -75,7 +76,7 @@ func (c *itemComponent) Render() co.Instance {
 					co.WithData(std.LabelData{
 						Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
 						FontSize:  opt.V(float32(16)),
-						FontColor: opt.V(ui.Black()),
+						FontColor: opt.V(theme.OnSurface()),
 						Text:      c.resource.ID(),
 					})
 				}))
@@ -0,0 +1,93 @@
+package view
+
+import (
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/game/asset"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/layout"
+	"github.com/mokiat/lacking/ui/mvc"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+// ResourceView picks the preview that is suitable for the kind of the
+// resource.
+var ResourceView = mvc.EventListener(co.Define(&resourceViewComponent{}))
+
+type ResourceViewData struct {
+	AppModel *model.AppModel
+	Resource *asset.Resource
+}
+
+type resourceViewComponent struct {
+	co.BaseComponent
+
+	appModel *model.AppModel
+	resource *asset.Resource
+
+	kind      model.ResourceKind
+	kindKnown bool
+}
+
+func (c *resourceViewComponent) OnCreate() {
+	data := co.GetData[ResourceViewData](c.Properties())
+	c.appModel = data.AppModel
+	c.resource = data.Resource
+	c.detectKind()
+}
+
+func (c *resourceViewComponent) Render() co.Instance {
+	return co.New(std.Element, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ElementData{
+			Layout: layout.Fill(),
+		})
+
+		if !c.kindKnown {
+			return
+		}
+
+		switch c.kind {
+		case model.ResourceKindTexture:
+			co.WithChild("texture-viewer", co.New(TextureViewer, func() {
+				co.WithData(TextureViewerData{
+					AppModel: c.appModel,
+					Resource: c.resource,
+				})
+			}))
+		default:
+			co.WithChild("viewport", co.New(Viewport, func() {
+				co.WithData(ViewportData{
+					AppModel: c.appModel,
+					Resource: c.resource,
+				})
+			}))
+		}
+	})
+}
+
+func (c *resourceViewComponent) OnEvent(event mvc.Event) {
+	switch event.(type) {
+	case model.RefreshEvent:
+		c.detectKind()
+	}
+}
+
+func (c *resourceViewComponent) detectKind() {
+	resource := c.resource
+	go func() {
+		kind := model.ResourceKindModel
+		if content, err := resource.OpenContent(); err == nil {
+			kind = model.ResourceKindOf(content)
+		} else {
+			log.Warn("Error opening content of %q: %v", resource.Name(), err)
+		}
+		co.Schedule(c.Scope(), func() {
+			if !c.kindKnown || kind != c.kind {
+				c.kind = kind
+				c.kindKnown = true
+				c.Invalidate()
+			}
+		})
+	}()
+}
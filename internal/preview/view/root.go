@@ -2,21 +2,27 @@ package view
 
 import (
 	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/config"
 	"github.com/mokiat/lacking-studio/internal/global"
+	"github.com/mokiat/lacking-studio/internal/keymap"
 	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/theme"
 	"github.com/mokiat/lacking-studio/internal/viewport"
+	"github.com/mokiat/lacking-studio/internal/widget"
 	"github.com/mokiat/lacking/debug/log"
 	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/ui"
 	co "github.com/mokiat/lacking/ui/component"
 	"github.com/mokiat/lacking/ui/layout"
 	"github.com/mokiat/lacking/ui/mvc"
 	"github.com/mokiat/lacking/ui/std"
 )
 
-// TODO: Get dark theme working
-
 var Root = mvc.EventListener(co.Define(&rootComponent{}))
 
+var _ ui.ElementKeyboardHandler = (*rootComponent)(nil)
+var _ ui.ElementHistoryHandler = (*rootComponent)(nil)
+
 type rootComponent struct {
 	co.BaseComponent
 
@@ -24,6 +30,8 @@ type rootComponent struct {
 	registry   *asset.Registry
 
 	appModel *model.AppModel
+
+	shortcutsOverlay co.Overlay
 }
 
 func (c *rootComponent) OnCreate() {
@@ -31,8 +39,20 @@ func (c *rootComponent) OnCreate() {
 	c.commonData = ctx.CommonData
 	c.registry = ctx.Registry
 
+	window := co.Window(c.Scope())
 	eventBus := co.TypedValue[*mvc.EventBus](c.Scope())
-	c.appModel = model.NewAppModel(co.Window(c.Scope()), eventBus, c.registry)
+	packer, err := model.NewPacker(ctx.Project.Pack, ctx.ProjectDir, func() (*asset.Registry, error) {
+		return ctx.Project.NewRegistry(ctx.ProjectDir)
+	}, ctx.Logs)
+	if err != nil {
+		log.Error("Error creating packer: %v", err)
+		defaultPack := config.DefaultProject().Pack
+		packer = model.NewCommandPacker(defaultPack.Command, defaultPack.ModelArgs, ctx.ProjectDir)
+	}
+	c.appModel = model.NewAppModel(window, eventBus, c.registry, ctx.Overrides, ctx.Logs, packer, ctx.Project, ctx.ProjectDir)
+	c.refreshTheme()
+
+	window.SetCloseInterceptor(c.handleCloseRequested)
 }
 
 func (c *rootComponent) OnDelete() {
@@ -40,50 +60,279 @@ func (c *rootComponent) OnDelete() {
 }
 
 func (c *rootComponent) Render() co.Instance {
-	return co.New(std.Container, func() {
-		co.WithData(std.ContainerData{
-			BackgroundColor: opt.V(std.SurfaceColor),
-			Layout:          layout.Frame(),
+	return co.New(std.Element, func() {
+		co.WithData(std.ElementData{
+			Essence:   c,
+			Focusable: opt.V(true),
+			Layout:    layout.Fill(),
 		})
 
-		co.WithChild("toolbar", co.New(Toolbar, func() {
-			co.WithLayoutData(layout.Data{
-				VerticalAlignment: layout.VerticalAlignmentTop,
-			})
-			co.WithData(ToolbarData{
-				AppModel: c.appModel,
+		co.WithChild("content", co.New(std.Container, func() {
+			co.WithData(std.ContainerData{
+				BackgroundColor: opt.V(theme.Surface()),
+				Layout:          layout.Frame(),
 			})
-		}))
 
-		if c.appModel.SelectedResource() == nil {
-			co.WithChild("registry", co.New(Registry, func() {
+			co.WithChild("header", co.New(std.Element, func() {
 				co.WithLayoutData(layout.Data{
-					HorizontalAlignment: layout.HorizontalAlignmentCenter,
-					VerticalAlignment:   layout.VerticalAlignmentCenter,
+					VerticalAlignment: layout.VerticalAlignmentTop,
 				})
-				co.WithData(RegistryData{
-					AppModel: c.appModel,
+				co.WithData(std.ElementData{
+					Layout: layout.Vertical(layout.VerticalSettings{
+						ContentAlignment: layout.HorizontalAlignmentLeft,
+					}),
 				})
+
+				co.WithChild("toolbar", co.New(Toolbar, func() {
+					co.WithLayoutData(layout.Data{
+						GrowHorizontally: true,
+					})
+					co.WithData(ToolbarData{
+						AppModel: c.appModel,
+					})
+				}))
+
+				if len(c.appModel.OpenResources()) > 0 {
+					co.WithChild("tabs", co.New(Tabs, func() {
+						co.WithLayoutData(layout.Data{
+							GrowHorizontally: true,
+						})
+						co.WithData(TabsData{
+							AppModel: c.appModel,
+						})
+					}))
+				}
 			}))
-		} else {
-			co.WithChild("viewport", co.New(Viewport, func() {
+
+			if console := c.appModel.Console(); console.Visible() {
+				co.WithChild("console", co.New(Console, func() {
+					if console.Dock() == model.ConsoleDockRight {
+						co.WithLayoutData(layout.Data{
+							HorizontalAlignment: layout.HorizontalAlignmentRight,
+							VerticalAlignment:   layout.VerticalAlignmentCenter,
+							Width:               opt.V(ConsoleWidth),
+						})
+					} else {
+						co.WithLayoutData(layout.Data{
+							VerticalAlignment: layout.VerticalAlignmentBottom,
+							Height:            opt.V(ConsoleHeight),
+						})
+					}
+					co.WithData(ConsoleData{
+						AppModel: c.appModel,
+					})
+				}))
+			}
+
+			co.WithChild("workspace", co.New(std.Element, func() {
 				co.WithLayoutData(layout.Data{
 					HorizontalAlignment: layout.HorizontalAlignmentCenter,
 					VerticalAlignment:   layout.VerticalAlignmentCenter,
 				})
-				co.WithData(ViewportData{
-					AppModel: c.appModel,
-					Resource: c.appModel.SelectedResource(),
+				co.WithData(std.ElementData{
+					Layout: panesLayout(),
 				})
+
+				co.WithChild("registry", co.New(std.Element, func() {
+					co.WithData(std.ElementData{
+						Visible: opt.V(c.appModel.SelectedResource() == nil),
+						Layout:  layout.Fill(),
+					})
+
+					co.WithChild("registry", co.New(Registry, func() {
+						co.WithData(RegistryData{
+							AppModel: c.appModel,
+						})
+					}))
+				}))
+
+				// NOTE: All open resources are kept alive, so that switching
+				// between tabs preserves the scene and camera of each one.
+				// The visible ones are listed first to keep their order.
+				for _, resource := range c.workspaceResources() {
+					co.WithChild(resource.ID(), co.New(std.Element, func() {
+						co.WithData(std.ElementData{
+							Visible: opt.V(c.appModel.IsResourceVisible(resource)),
+							Layout:  layout.Fill(),
+						})
+
+						co.WithChild("view", co.New(ResourceView, func() {
+							co.WithData(ResourceViewData{
+								AppModel: c.appModel,
+								Resource: resource,
+							})
+						}))
+					}))
+				}
 			}))
-		}
+		}))
 	})
 }
 
+func (c *rootComponent) workspaceResources() []*asset.Resource {
+	selectedResource := c.appModel.SelectedResource()
+	splitResource := c.appModel.SplitResource()
+
+	var result []*asset.Resource
+	if selectedResource != nil {
+		result = append(result, selectedResource)
+	}
+	if splitResource != nil {
+		result = append(result, splitResource)
+	}
+	for _, resource := range c.appModel.OpenResources() {
+		if resource != selectedResource && resource != splitResource {
+			result = append(result, resource)
+		}
+	}
+	return result
+}
+
+func (c *rootComponent) OnKeyboardEvent(element *ui.Element, event ui.KeyboardEvent) bool {
+	if event.Action != ui.KeyboardActionDown && event.Action != ui.KeyboardActionRepeat {
+		return false
+	}
+	action, ok := c.appModel.Keymap().Match(event)
+	if !ok {
+		return false
+	}
+	// NOTE: Holding a key down would otherwise flip toggles on every repeat
+	// and flood the history with changes.
+	if event.Action == ui.KeyboardActionRepeat && !isRepeatableAction(action) {
+		return true
+	}
+	return c.handleAction(element, action)
+}
+
+func isRepeatableAction(action keymap.Action) bool {
+	switch action {
+	case model.ActionUndo, model.ActionRedo:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *rootComponent) selectTool(tool model.TransformTool) bool {
+	if c.appModel.SelectedResource() == nil {
+		return false
+	}
+	c.appModel.Transforms().SetTool(tool)
+	return true
+}
+
+func (c *rootComponent) handleAction(element *ui.Element, action keymap.Action) bool {
+	hasResource := c.appModel.SelectedResource() != nil
+	switch action {
+	case model.ActionRefresh:
+		c.appModel.Refresh()
+		return true
+	case model.ActionBack:
+		if !hasResource {
+			return false
+		}
+		c.appModel.SetSelectedResource(nil)
+		return true
+	case model.ActionQuit:
+		c.appModel.SaveSettings()
+		element.Window().Close()
+		return true
+	case model.ActionUndo:
+		return element.Window().Undo()
+	case model.ActionRedo:
+		return element.Window().Redo()
+	case model.ActionToggleGrid:
+		if !hasResource {
+			return false
+		}
+		c.appModel.SetShowGrid(!c.appModel.ShowGrid())
+		return true
+	case model.ActionFrameModel:
+		if !hasResource {
+			return false
+		}
+		c.appModel.FrameModel()
+		return true
+	case model.ActionSwitchViewMode:
+		if !hasResource {
+			return false
+		}
+		c.appModel.SwitchViewMode()
+		return true
+	case model.ActionToolSelect:
+		return c.selectTool(model.TransformToolSelect)
+	case model.ActionToolTranslate:
+		return c.selectTool(model.TransformToolTranslate)
+	case model.ActionToolRotate:
+		return c.selectTool(model.TransformToolRotate)
+	case model.ActionToolScale:
+		return c.selectTool(model.TransformToolScale)
+	case model.ActionShowShortcuts:
+		c.appModel.ShowShortcuts()
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *rootComponent) OnUndo(element *ui.Element) bool {
+	history := c.appModel.History()
+	if !history.CanUndo() {
+		return false
+	}
+	history.Undo()
+	return true
+}
+
+func (c *rootComponent) OnRedo(element *ui.Element) bool {
+	history := c.appModel.History()
+	if !history.CanRedo() {
+		return false
+	}
+	history.Redo()
+	return true
+}
+
+func (c *rootComponent) showShortcuts() {
+	if c.shortcutsOverlay != nil {
+		return
+	}
+	c.shortcutsOverlay = co.OpenOverlay(c.Scope(), co.New(widget.ShortcutsModal, func() {
+		co.WithData(widget.ShortcutsModalData{
+			Keymap: c.appModel.Keymap(),
+		})
+		co.WithCallbackData(widget.ShortcutsModalCallbackData{
+			OnClose: func() {
+				c.shortcutsOverlay = nil
+			},
+		})
+	}))
+}
+
+func (c *rootComponent) refreshTheme() {
+	theme.Apply(theme.PaletteByName(c.appModel.Theme()))
+}
+
+func (c *rootComponent) handleCloseRequested() bool {
+	c.appModel.SaveSettings()
+	return true
+}
+
 func (c *rootComponent) OnEvent(event mvc.Event) {
 	switch event := event.(type) {
+	case model.OpenResourcesChangedEvent:
+		c.Invalidate()
 	case model.SelectedResourceChangedEvent:
 		c.Invalidate()
+	case model.SplitResourceChangedEvent:
+		c.Invalidate()
+	case model.ThemeChangedEvent:
+		c.refreshTheme()
+		c.Invalidate()
+	case model.ConsoleLayoutChangedEvent:
+		c.Invalidate()
+	case model.ShowShortcutsEvent:
+		c.showShortcuts()
 	case model.RefreshErrorEvent:
 		// TODO: Open error dialog
 		log.Error("Refresh error: %v", event.Err)
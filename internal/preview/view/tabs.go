@@ -0,0 +1,68 @@
+package view
+
+import (
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking/game/asset"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/mvc"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+var Tabs = mvc.EventListener(co.Define(&tabsComponent{}))
+
+type TabsData struct {
+	AppModel *model.AppModel
+}
+
+type tabsComponent struct {
+	co.BaseComponent
+
+	appModel *model.AppModel
+}
+
+func (c *tabsComponent) OnUpsert() {
+	data := co.GetData[TabsData](c.Properties())
+	c.appModel = data.AppModel
+}
+
+func (c *tabsComponent) Render() co.Instance {
+	return co.New(std.Tabbar, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+
+		for _, resource := range c.appModel.OpenResources() {
+			co.WithChild(resource.ID(), co.New(std.TabbarTab, func() {
+				co.WithData(std.TabbarTabData{
+					Text:     resource.Name(),
+					Selected: c.appModel.IsResourceVisible(resource),
+				})
+				co.WithCallbackData(std.TabbarTabCallbackData{
+					OnClick: func() {
+						c.handleTabSelected(resource)
+					},
+					OnClose: func() {
+						c.handleTabClosed(resource)
+					},
+				})
+			}))
+		}
+	})
+}
+
+func (c *tabsComponent) OnEvent(event mvc.Event) {
+	switch event.(type) {
+	case model.OpenResourcesChangedEvent:
+		c.Invalidate()
+	case model.SelectedResourceChangedEvent:
+		c.Invalidate()
+	case model.SplitResourceChangedEvent:
+		c.Invalidate()
+	}
+}
+
+func (c *tabsComponent) handleTabSelected(resource *asset.Resource) {
+	c.appModel.SetSelectedResource(resource)
+}
+
+func (c *tabsComponent) handleTabClosed(resource *asset.Resource) {
+	c.appModel.CloseResource(resource)
+}
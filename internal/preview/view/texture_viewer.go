@@ -0,0 +1,431 @@
+package view
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking-studio/internal/viewport"
+	"github.com/mokiat/lacking-studio/internal/widget"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/ui"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/layout"
+	"github.com/mokiat/lacking/ui/mvc"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+const (
+	textureMinZoom      = 1.0 / 16.0
+	textureMaxZoom      = 64.0
+	textureZoomStep     = 1.2
+	textureExposureSpan = 16.0
+)
+
+var cubeFaceNames = []string{"Front", "Back", "Left", "Right", "Top", "Bottom"}
+
+var TextureViewer = mvc.EventListener(co.Define(&textureViewerComponent{}))
+
+type TextureViewerData struct {
+	AppModel *model.AppModel
+	Resource *asset.Resource
+}
+
+type textureViewerComponent struct {
+	co.BaseComponent
+
+	appModel *model.AppModel
+	resource *asset.Resource
+
+	textures []asset.Texture
+
+	textureIndex int
+	mipLevel     int
+	face         int
+	channel      viewport.TextureChannel
+	exposure     float64
+
+	image *ui.Image
+
+	zoom     float32
+	pan      sprec.Vec2
+	dragging bool
+	dragX    int
+	dragY    int
+}
+
+func (c *textureViewerComponent) OnCreate() {
+	data := co.GetData[TextureViewerData](c.Properties())
+	c.appModel = data.AppModel
+	c.resource = data.Resource
+	c.zoom = 1.0
+	c.loadTextures()
+}
+
+func (c *textureViewerComponent) OnDelete() {
+	c.releaseImage()
+}
+
+func (c *textureViewerComponent) Render() co.Instance {
+	return co.New(std.Element, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ElementData{
+			Layout: layout.Frame(),
+		})
+
+		co.WithChild("canvas", co.New(std.Element, func() {
+			co.WithLayoutData(layout.Data{
+				HorizontalAlignment: layout.HorizontalAlignmentCenter,
+				VerticalAlignment:   layout.VerticalAlignmentCenter,
+			})
+			co.WithData(std.ElementData{
+				Essence: c,
+			})
+		}))
+
+		co.WithChild("sidebar", co.New(std.Container, func() {
+			co.WithLayoutData(layout.Data{
+				HorizontalAlignment: layout.HorizontalAlignmentRight,
+				VerticalAlignment:   layout.VerticalAlignmentCenter,
+				Width:               opt.V(300),
+			})
+			co.WithData(std.ContainerData{
+				Padding:     ui.UniformSpacing(5),
+				BorderColor: opt.V(theme.Outline()),
+				BorderSize: ui.Spacing{
+					Left: 1,
+				},
+				Layout: layout.Vertical(layout.VerticalSettings{
+					ContentAlignment: layout.HorizontalAlignmentLeft,
+					ContentSpacing:   10,
+				}),
+			})
+
+			if len(c.textures) == 0 {
+				co.WithChild("empty", c.renderText("No textures."))
+				return
+			}
+			texture := c.textures[c.textureIndex]
+
+			if len(c.textures) > 1 {
+				co.WithChild("texture", co.New(std.Dropdown, func() {
+					co.WithLayoutData(layout.Data{
+						GrowHorizontally: true,
+					})
+					co.WithData(std.DropdownData{
+						Items:       c.textureItems(),
+						SelectedKey: c.textureIndex,
+					})
+					co.WithCallbackData(std.DropdownCallbackData{
+						OnItemSelected: c.handleTextureSelected,
+					})
+				}))
+			}
+
+			co.WithChild("format", c.renderText("Format: "+viewport.TextureFormatName(texture.Format)))
+			co.WithChild("type", c.renderText("Type: "+c.textureType(texture)))
+			if len(texture.MipmapLayers) > 0 {
+				layer := texture.MipmapLayers[0]
+				co.WithChild("size", c.renderText(fmt.Sprintf("Size: %dx%d", layer.Width, layer.Height)))
+			}
+			co.WithChild("mip-count", c.renderText(fmt.Sprintf("Mip Levels: %d", len(texture.MipmapLayers))))
+
+			co.WithChild("mip-level", co.New(std.Dropdown, func() {
+				co.WithLayoutData(layout.Data{
+					GrowHorizontally: true,
+				})
+				co.WithData(std.DropdownData{
+					Items:       c.mipLevelItems(texture),
+					SelectedKey: c.mipLevel,
+				})
+				co.WithCallbackData(std.DropdownCallbackData{
+					OnItemSelected: c.handleMipLevelSelected,
+				})
+			}))
+
+			if viewport.TextureFaceCount(texture) > 1 {
+				co.WithChild("face", co.New(std.Dropdown, func() {
+					co.WithLayoutData(layout.Data{
+						GrowHorizontally: true,
+					})
+					co.WithData(std.DropdownData{
+						Items:       c.faceItems(texture),
+						SelectedKey: c.face,
+					})
+					co.WithCallbackData(std.DropdownCallbackData{
+						OnItemSelected: c.handleFaceSelected,
+					})
+				}))
+			}
+
+			co.WithChild("channel", co.New(std.Dropdown, func() {
+				co.WithLayoutData(layout.Data{
+					GrowHorizontally: true,
+				})
+				co.WithData(std.DropdownData{
+					Items:       c.channelItems(),
+					SelectedKey: c.channel,
+				})
+				co.WithCallbackData(std.DropdownCallbackData{
+					OnItemSelected: c.handleChannelSelected,
+				})
+			}))
+
+			if viewport.IsHDRTextureFormat(texture.Format) {
+				co.WithChild("exposure-label", c.renderText(fmt.Sprintf("Exposure: %+.1f", c.exposure)))
+				co.WithChild("exposure", co.New(widget.Slider, func() {
+					co.WithLayoutData(layout.Data{
+						GrowHorizontally: true,
+					})
+					co.WithData(widget.SliderData{
+						Value: c.exposure/textureExposureSpan + 0.5,
+					})
+					co.WithCallbackData(widget.SliderCallbackData{
+						OnChange: c.handleExposureChange,
+					})
+				}))
+			}
+
+			co.WithChild("zoom", c.renderText(fmt.Sprintf("Zoom: %.0f%%", c.zoom*100.0)))
+
+			co.WithChild("reset", co.New(std.Button, func() {
+				co.WithLayoutData(layout.Data{
+					GrowHorizontally: true,
+				})
+				co.WithData(std.ButtonData{
+					Text: "Reset View",
+				})
+				co.WithCallbackData(std.ButtonCallbackData{
+					OnClick: c.resetView,
+				})
+			}))
+		}))
+	})
+}
+
+func (c *textureViewerComponent) OnEvent(event mvc.Event) {
+	switch event.(type) {
+	case model.RefreshEvent:
+		c.loadTextures()
+	}
+}
+
+func (c *textureViewerComponent) OnMouseEvent(element *ui.Element, event ui.MouseEvent) bool {
+	switch event.Action {
+	case ui.MouseActionScroll:
+		zoom := c.zoom * float32(math.Pow(textureZoomStep, float64(event.ScrollY)))
+		c.zoom = min(max(zoom, textureMinZoom), textureMaxZoom)
+		c.Invalidate()
+		return true
+	case ui.MouseActionDown:
+		if event.Button != ui.MouseButtonLeft && event.Button != ui.MouseButtonMiddle {
+			return false
+		}
+		c.dragging = true
+		c.dragX, c.dragY = event.X, event.Y
+		return true
+	case ui.MouseActionMove:
+		if !c.dragging {
+			return false
+		}
+		c.pan = sprec.Vec2Sum(c.pan, sprec.NewVec2(float32(event.X-c.dragX), float32(event.Y-c.dragY)))
+		c.dragX, c.dragY = event.X, event.Y
+		element.Invalidate()
+		return true
+	case ui.MouseActionUp, ui.MouseActionLeave:
+		c.dragging = false
+		return false
+	default:
+		return false
+	}
+}
+
+func (c *textureViewerComponent) OnRender(element *ui.Element, canvas *ui.Canvas) {
+	drawBounds := canvas.DrawBounds(element, false)
+
+	canvas.Reset()
+	canvas.Rectangle(sprec.ZeroVec2(), drawBounds.Size)
+	canvas.Fill(ui.Fill{
+		Color: theme.Surface(),
+	})
+
+	if c.image == nil {
+		return
+	}
+	imageSize := c.image.Size()
+	size := sprec.NewVec2(float32(imageSize.Width)*c.zoom, float32(imageSize.Height)*c.zoom)
+	position := sprec.Vec2Sum(
+		sprec.Vec2Prod(sprec.Vec2Diff(drawBounds.Size, size), 0.5),
+		c.pan,
+	)
+
+	canvas.Reset()
+	canvas.Rectangle(position, size)
+	canvas.Fill(ui.Fill{
+		Color:       ui.White(),
+		Image:       c.image,
+		ImageOffset: position,
+		ImageSize:   size,
+	})
+}
+
+func (c *textureViewerComponent) loadTextures() {
+	resource := c.resource
+	go func() {
+		content, err := resource.OpenContent()
+		if err != nil {
+			log.Error("Error opening content of %q: %v", resource.Name(), err)
+			return
+		}
+		co.Schedule(c.Scope(), func() {
+			c.textures = content.Textures
+			c.textureIndex = min(c.textureIndex, max(len(c.textures)-1, 0))
+			c.clampLevels()
+			c.refreshImage()
+		})
+	}()
+}
+
+func (c *textureViewerComponent) clampLevels() {
+	if len(c.textures) == 0 {
+		return
+	}
+	texture := c.textures[c.textureIndex]
+	c.mipLevel = min(c.mipLevel, max(len(texture.MipmapLayers)-1, 0))
+	c.face = min(c.face, max(viewport.TextureFaceCount(texture)-1, 0))
+}
+
+func (c *textureViewerComponent) refreshImage() {
+	c.releaseImage()
+	if len(c.textures) > 0 {
+		img, err := viewport.TextureImage(c.textures[c.textureIndex], viewport.TextureImageInfo{
+			MipLevel: c.mipLevel,
+			Face:     c.face,
+			Channel:  c.channel,
+			Exposure: c.exposure,
+		})
+		if err != nil {
+			log.Error("Error converting texture: %v", err)
+		} else {
+			c.image = co.CreateImage(c.Scope(), img)
+		}
+	}
+	c.Invalidate()
+}
+
+func (c *textureViewerComponent) releaseImage() {
+	if c.image != nil {
+		c.image.Destroy()
+		c.image = nil
+	}
+}
+
+func (c *textureViewerComponent) renderText(text string) co.Instance {
+	return co.New(std.Label, func() {
+		co.WithData(std.LabelData{
+			Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
+			FontSize:  opt.V(float32(16)),
+			FontColor: opt.V(theme.OnSurface()),
+			Text:      text,
+		})
+	})
+}
+
+func (c *textureViewerComponent) textureType(texture asset.Texture) string {
+	switch {
+	case texture.Flags.Has(asset.TextureFlagCubeMap):
+		return "Cube"
+	case texture.Flags.Has(asset.TextureFlag2DArray):
+		return "2D Array"
+	case texture.Flags.Has(asset.TextureFlag3D):
+		return "3D"
+	default:
+		return "2D"
+	}
+}
+
+func (c *textureViewerComponent) textureItems() []std.DropdownItem {
+	result := make([]std.DropdownItem, len(c.textures))
+	for i := range c.textures {
+		result[i] = std.DropdownItem{
+			Key:   i,
+			Label: fmt.Sprintf("Texture #%d", i),
+		}
+	}
+	return result
+}
+
+func (c *textureViewerComponent) mipLevelItems(texture asset.Texture) []std.DropdownItem {
+	result := make([]std.DropdownItem, len(texture.MipmapLayers))
+	for i, layer := range texture.MipmapLayers {
+		result[i] = std.DropdownItem{
+			Key:   i,
+			Label: fmt.Sprintf("Mip Level %d (%dx%d)", i, layer.Width, layer.Height),
+		}
+	}
+	return result
+}
+
+func (c *textureViewerComponent) faceItems(texture asset.Texture) []std.DropdownItem {
+	count := viewport.TextureFaceCount(texture)
+	result := make([]std.DropdownItem, count)
+	for i := range count {
+		label := fmt.Sprintf("Layer %d", i)
+		if texture.Flags.Has(asset.TextureFlagCubeMap) && i < len(cubeFaceNames) {
+			label = cubeFaceNames[i]
+		}
+		result[i] = std.DropdownItem{
+			Key:   i,
+			Label: label,
+		}
+	}
+	return result
+}
+
+func (c *textureViewerComponent) channelItems() []std.DropdownItem {
+	channels := viewport.TextureChannels()
+	result := make([]std.DropdownItem, len(channels))
+	for i, channel := range channels {
+		result[i] = std.DropdownItem{
+			Key:   channel,
+			Label: channel.String(),
+		}
+	}
+	return result
+}
+
+func (c *textureViewerComponent) handleTextureSelected(key any) {
+	c.textureIndex = key.(int)
+	c.clampLevels()
+	c.refreshImage()
+}
+
+func (c *textureViewerComponent) handleMipLevelSelected(key any) {
+	c.mipLevel = key.(int)
+	c.refreshImage()
+}
+
+func (c *textureViewerComponent) handleFaceSelected(key any) {
+	c.face = key.(int)
+	c.refreshImage()
+}
+
+func (c *textureViewerComponent) handleChannelSelected(key any) {
+	c.channel = key.(viewport.TextureChannel)
+	c.refreshImage()
+}
+
+func (c *textureViewerComponent) handleExposureChange(value float64) {
+	c.exposure = (value - 0.5) * textureExposureSpan
+	c.refreshImage()
+}
+
+func (c *textureViewerComponent) resetView() {
+	c.zoom = 1.0
+	c.pan = sprec.ZeroVec2()
+	c.Invalidate()
+}
@@ -1,8 +1,13 @@
 package view
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/theme"
 	"github.com/mokiat/lacking-studio/internal/widget"
 	"github.com/mokiat/lacking/debug/log"
 	co "github.com/mokiat/lacking/ui/component"
@@ -45,6 +50,66 @@ func (c *toolbarComponent) Render() co.Instance {
 			})
 		}))
 
+		co.WithChild("separator-between-refresh-undo", co.New(std.ToolbarSeparator, nil))
+
+		co.WithChild("undo", co.New(std.ToolbarButton, func() {
+			co.WithData(std.ToolbarButtonData{
+				Icon:    co.OpenImage(c.Scope(), "icons/undo.png"),
+				Text:    "Undo",
+				Enabled: opt.V(c.appModel.History().CanUndo()),
+			})
+			co.WithCallbackData(std.ToolbarButtonCallbackData{
+				OnClick: c.handleUndo,
+			})
+		}))
+
+		co.WithChild("redo", co.New(std.ToolbarButton, func() {
+			co.WithData(std.ToolbarButtonData{
+				Icon:    co.OpenImage(c.Scope(), "icons/redo.png"),
+				Text:    "Redo",
+				Enabled: opt.V(c.appModel.History().CanRedo()),
+			})
+			co.WithCallbackData(std.ToolbarButtonCallbackData{
+				OnClick: c.handleRedo,
+			})
+		}))
+
+		co.WithChild("separator-between-redo-split", co.New(std.ToolbarSeparator, nil))
+
+		co.WithChild("split", co.New(std.ToolbarButton, func() {
+			co.WithData(std.ToolbarButtonData{
+				Text:     "Split View",
+				Enabled:  opt.V(c.appModel.SelectedResource() != nil && len(c.appModel.OpenResources()) > 1),
+				Selected: c.appModel.SplitResource() != nil,
+			})
+			co.WithCallbackData(std.ToolbarButtonCallbackData{
+				OnClick: c.handleSplitToggle,
+			})
+		}))
+
+		co.WithChild("linked-cameras", co.New(std.ToolbarButton, func() {
+			co.WithData(std.ToolbarButtonData{
+				Text:     "Link Cameras",
+				Enabled:  opt.V(c.appModel.SplitResource() != nil),
+				Selected: c.appModel.LinkedCameras(),
+			})
+			co.WithCallbackData(std.ToolbarButtonCallbackData{
+				OnClick: c.handleLinkedCamerasToggle,
+			})
+		}))
+
+		co.WithChild("separator-between-cameras-console", co.New(std.ToolbarSeparator, nil))
+
+		co.WithChild("console", co.New(std.ToolbarButton, func() {
+			co.WithData(std.ToolbarButtonData{
+				Text:     "Console",
+				Selected: c.appModel.Console().Visible(),
+			})
+			co.WithCallbackData(std.ToolbarButtonCallbackData{
+				OnClick: c.handleConsoleToggle,
+			})
+		}))
+
 		// The following are listed in reverse.
 
 		co.WithChild("quit", co.New(std.ToolbarButton, func() {
@@ -79,46 +144,178 @@ func (c *toolbarComponent) Render() co.Instance {
 				OnClick: c.handleBack,
 			})
 		}))
+
+		co.WithChild("separator-between-back-theme", co.New(std.ToolbarSeparator, func() {
+			co.WithLayoutData(layout.Data{
+				HorizontalAlignment: layout.HorizontalAlignmentRight,
+			})
+		}))
+
+		co.WithChild("dark-theme", co.New(std.ToolbarButton, func() {
+			co.WithLayoutData(layout.Data{
+				HorizontalAlignment: layout.HorizontalAlignmentRight,
+			})
+			co.WithData(std.ToolbarButtonData{
+				Text:     "Dark Theme",
+				Selected: c.appModel.Theme() == theme.NameDark,
+			})
+			co.WithCallbackData(std.ToolbarButtonCallbackData{
+				OnClick: c.handleDarkThemeToggle,
+			})
+		}))
+
+		co.WithChild("separator-between-theme-shortcuts", co.New(std.ToolbarSeparator, func() {
+			co.WithLayoutData(layout.Data{
+				HorizontalAlignment: layout.HorizontalAlignmentRight,
+			})
+		}))
+
+		co.WithChild("shortcuts", co.New(std.ToolbarButton, func() {
+			co.WithLayoutData(layout.Data{
+				HorizontalAlignment: layout.HorizontalAlignmentRight,
+			})
+			co.WithData(std.ToolbarButtonData{
+				Text: "Shortcuts",
+			})
+			co.WithCallbackData(std.ToolbarButtonCallbackData{
+				OnClick: c.handleShortcuts,
+			})
+		}))
 	})
 }
 
 func (c *toolbarComponent) OnEvent(event mvc.Event) {
 	switch event := event.(type) {
+	case model.RefreshStartedEvent:
+		c.handleRefreshStarted()
+		c.Invalidate()
 	case model.RefreshEvent:
-		c.handleRefreshComplete(nil)
+		c.handleRefreshComplete(nil, 0)
 		c.Invalidate()
 	case model.RefreshErrorEvent:
-		c.handleRefreshComplete(event.Err)
+		c.handleRefreshComplete(event.Err, event.LogID)
+		c.Invalidate()
+	case model.OpenResourcesChangedEvent:
 		c.Invalidate()
 	case model.SelectedResourceChangedEvent:
 		c.Invalidate()
+	case model.SplitResourceChangedEvent:
+		c.Invalidate()
+	case model.LinkedCamerasChangedEvent:
+		c.Invalidate()
+	case model.HistoryChangedEvent:
+		c.Invalidate()
+	case model.ThemeChangedEvent:
+		c.Invalidate()
+	case model.ConsoleLayoutChangedEvent:
+		c.Invalidate()
 	}
 }
 
 func (c *toolbarComponent) handleRefresh() {
 	c.appModel.Refresh()
-	c.loadingModal = co.OpenOverlay(c.Scope(), co.New(widget.LoadingModal, nil))
-	c.Invalidate()
 }
 
-func (c *toolbarComponent) handleRefreshComplete(err error) {
+func (c *toolbarComponent) handleRefreshStarted() {
+	c.loadingModal = co.OpenOverlay(c.Scope(), co.New(RefreshModal, func() {
+		co.WithData(RefreshModalData{
+			AppModel: c.appModel,
+		})
+	}))
+}
+
+func (c *toolbarComponent) handleRefreshComplete(err error, logID uint64) {
 	c.loadingModal.Close()
+	if errors.Is(err, context.Canceled) {
+		log.Info("Refresh cancelled")
+		return
+	}
 	if err != nil {
 		log.Error("Refresh error: %v", err)
+		if errorCount := c.appModel.Problems().ErrorCount(); errorCount > 0 {
+			co.OpenOverlay(c.Scope(), co.New(widget.NotificationModal, func() {
+				co.WithData(widget.NotificationModalData{
+					Icon:       co.OpenImage(c.Scope(), "icons/error.png"),
+					Text:       fmt.Sprintf("Error during refresh.\n\n%d problems were reported.", errorCount),
+					ActionText: "Show Problems",
+				})
+				co.WithCallbackData(widget.NotificationModalCallbackData{
+					OnAction: c.appModel.Console().ShowProblems,
+				})
+			}))
+			return
+		}
 		co.OpenOverlay(c.Scope(), co.New(widget.NotificationModal, func() {
 			co.WithData(widget.NotificationModalData{
-				Icon: co.OpenImage(c.Scope(), "icons/error.png"),
-				Text: "Error during refresh.\n\nCheck logs for more info.",
+				Icon:       co.OpenImage(c.Scope(), "icons/error.png"),
+				Text:       c.refreshErrorText(err) + "\n\nCheck logs for more info.",
+				ActionText: "Show Logs",
+			})
+			co.WithCallbackData(widget.NotificationModalCallbackData{
+				OnAction: func() {
+					c.appModel.Console().ShowSince(logID)
+				},
 			})
 		}))
 	}
 }
 
+func (c *toolbarComponent) refreshErrorText(err error) string {
+	var refreshErr *model.RefreshError
+	if errors.As(err, &refreshErr) && refreshErr.Stage == model.RefreshStageReload {
+		return "Error reloading the registry."
+	}
+	return "Error during refresh."
+}
+
+func (c *toolbarComponent) handleConsoleToggle() {
+	console := c.appModel.Console()
+	console.SetVisible(!console.Visible())
+}
+
+func (c *toolbarComponent) handleUndo() {
+	c.appModel.History().Undo()
+}
+
+func (c *toolbarComponent) handleRedo() {
+	c.appModel.History().Redo()
+}
+
+func (c *toolbarComponent) handleSplitToggle() {
+	if c.appModel.SplitResource() != nil {
+		c.appModel.SetSplitResource(nil)
+		return
+	}
+	for _, resource := range c.appModel.OpenResources() {
+		if resource != c.appModel.SelectedResource() {
+			c.appModel.SetSplitResource(resource)
+			return
+		}
+	}
+}
+
+func (c *toolbarComponent) handleLinkedCamerasToggle() {
+	c.appModel.SetLinkedCameras(!c.appModel.LinkedCameras())
+}
+
+func (c *toolbarComponent) handleDarkThemeToggle() {
+	if c.appModel.Theme() == theme.NameDark {
+		c.appModel.SetTheme(theme.NameLight)
+	} else {
+		c.appModel.SetTheme(theme.NameDark)
+	}
+}
+
+func (c *toolbarComponent) handleShortcuts() {
+	c.appModel.ShowShortcuts()
+}
+
 func (c *toolbarComponent) handleBack() {
 	c.appModel.SetSelectedResource(nil)
 	c.Invalidate()
 }
 
 func (c *toolbarComponent) handleQuit() {
+	c.appModel.SaveSettings()
 	co.Window(c.Scope()).Close()
 }
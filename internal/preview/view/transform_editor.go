@@ -0,0 +1,265 @@
+package view
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/hierarchy"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/layout"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+const transformFieldWidth = 70
+
+var TransformEditor = co.Define(&transformEditorComponent{})
+
+type TransformEditorData struct {
+	AppModel  *model.AppModel
+	Resource  *asset.Resource
+	Content   *asset.Model
+	Node      *hierarchy.Node
+	NodeIndex int
+}
+
+type transformEditorComponent struct {
+	co.BaseComponent
+
+	appModel  *model.AppModel
+	resource  *asset.Resource
+	content   *asset.Model
+	node      *hierarchy.Node
+	nodeIndex int
+}
+
+func (c *transformEditorComponent) OnUpsert() {
+	data := co.GetData[TransformEditorData](c.Properties())
+	c.appModel = data.AppModel
+	c.resource = data.Resource
+	c.content = data.Content
+	c.node = data.Node
+	c.nodeIndex = data.NodeIndex
+}
+
+func (c *transformEditorComponent) Render() co.Instance {
+	transforms := c.appModel.Transforms()
+	return co.New(std.Element, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ElementData{
+			Layout: layout.Vertical(layout.VerticalSettings{
+				ContentAlignment: layout.HorizontalAlignmentLeft,
+				ContentSpacing:   10,
+			}),
+		})
+
+		co.WithChild("tool", co.New(std.Dropdown, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+			co.WithData(std.DropdownData{
+				Items:       c.toolItems(),
+				SelectedKey: transforms.Tool(),
+			})
+			co.WithCallbackData(std.DropdownCallbackData{
+				OnItemSelected: c.handleToolSelected,
+			})
+		}))
+
+		co.WithChild("local-space", co.New(std.Checkbox, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+			co.WithData(std.CheckboxData{
+				Label:   "Local Space",
+				Checked: transforms.LocalSpace(),
+			})
+			co.WithCallbackData(std.CheckboxCallbackData{
+				OnToggle: transforms.SetLocalSpace,
+			})
+		}))
+
+		co.WithChild("snapping", co.New(std.Checkbox, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+			co.WithData(std.CheckboxData{
+				Label: fmt.Sprintf("Snap (%g, %g°, %g)",
+					transforms.TranslationSnap(),
+					transforms.RotationSnap().Degrees(),
+					transforms.ScaleSnap(),
+				),
+				Checked: transforms.Snapping(),
+			})
+			co.WithCallbackData(std.CheckboxCallbackData{
+				OnToggle: transforms.SetSnapping,
+			})
+		}))
+
+		if c.node != nil && c.content != nil && transforms.Tool().IsEditing() {
+			transform := nodeTransform(c.node)
+			rotationX, rotationY, rotationZ := transform.Rotation.EulerAngles(dprec.RotationOrderGlobalXYZ)
+
+			co.WithChild("position", c.renderRow("Position", [3]float64{
+				transform.Translation.X,
+				transform.Translation.Y,
+				transform.Translation.Z,
+			}, func(values [3]float64) {
+				transform.Translation = dprec.NewVec3(values[0], values[1], values[2])
+				c.changeTransform(transform)
+			}))
+
+			co.WithChild("rotation", c.renderRow("Rotation", [3]float64{
+				rotationX.Degrees(),
+				rotationY.Degrees(),
+				rotationZ.Degrees(),
+			}, func(values [3]float64) {
+				transform.Rotation = dprec.EulerQuat(
+					dprec.Degrees(values[0]),
+					dprec.Degrees(values[1]),
+					dprec.Degrees(values[2]),
+					dprec.RotationOrderGlobalXYZ,
+				)
+				c.changeTransform(transform)
+			}))
+
+			co.WithChild("scale", c.renderRow("Scale", [3]float64{
+				transform.Scale.X,
+				transform.Scale.Y,
+				transform.Scale.Z,
+			}, func(values [3]float64) {
+				transform.Scale = dprec.NewVec3(values[0], values[1], values[2])
+				c.changeTransform(transform)
+			}))
+		}
+
+		co.WithChild("write", co.New(std.Button, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+			co.WithData(std.ButtonData{
+				Text:    "Write to Source",
+				Enabled: opt.V(transforms.HasChanges(c.resource)),
+			})
+			co.WithCallbackData(std.ButtonCallbackData{
+				OnClick: c.handleWriteBack,
+			})
+		}))
+
+		co.WithChild("copy", co.New(std.Button, func() {
+			co.WithLayoutData(layout.Data{
+				GrowHorizontally: true,
+			})
+			co.WithData(std.ButtonData{
+				Text:    "Copy as DSL",
+				Enabled: opt.V(transforms.HasChanges(c.resource)),
+			})
+			co.WithCallbackData(std.ButtonCallbackData{
+				OnClick: c.handleCopy,
+			})
+		}))
+	})
+}
+
+func (c *transformEditorComponent) renderRow(label string, values [3]float64, onChange func([3]float64)) co.Instance {
+	return co.New(std.Element, func() {
+		co.WithLayoutData(layout.Data{
+			GrowHorizontally: true,
+		})
+		co.WithData(std.ElementData{
+			Layout: layout.Vertical(layout.VerticalSettings{
+				ContentAlignment: layout.HorizontalAlignmentLeft,
+				ContentSpacing:   2,
+			}),
+		})
+
+		co.WithChild("label", co.New(std.Label, func() {
+			co.WithData(std.LabelData{
+				Font:      co.OpenFont(c.Scope(), "ui:///roboto-bold.ttf"),
+				FontSize:  opt.V(float32(16)),
+				FontColor: opt.V(theme.OnSurface()),
+				Text:      label,
+			})
+		}))
+
+		co.WithChild("fields", co.New(std.Element, func() {
+			co.WithData(std.ElementData{
+				Layout: layout.Horizontal(layout.HorizontalSettings{
+					ContentAlignment: layout.VerticalAlignmentCenter,
+					ContentSpacing:   5,
+				}),
+			})
+
+			for i, value := range values {
+				co.WithChild(fmt.Sprintf("field-%d", i), co.New(std.EditBox, func() {
+					co.WithLayoutData(layout.Data{
+						Width: opt.V(transformFieldWidth),
+					})
+					co.WithData(std.EditBoxData{
+						Text: strconv.FormatFloat(value, 'f', 3, 64),
+					})
+					co.WithCallbackData(std.EditBoxCallbackData{
+						OnSubmit: func(text string) {
+							newValue, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+							if err != nil {
+								c.Invalidate() // restore the previous value
+								return
+							}
+							newValues := values
+							newValues[i] = newValue
+							onChange(newValues)
+						},
+					})
+				}))
+			}
+		}))
+	})
+}
+
+func (c *transformEditorComponent) toolItems() []std.DropdownItem {
+	tools := model.TransformTools()
+	result := make([]std.DropdownItem, len(tools))
+	for i, tool := range tools {
+		result[i] = std.DropdownItem{
+			Key:   tool,
+			Label: tool.String(),
+		}
+	}
+	return result
+}
+
+func (c *transformEditorComponent) handleToolSelected(key any) {
+	c.appModel.Transforms().SetTool(key.(model.TransformTool))
+}
+
+func (c *transformEditorComponent) changeTransform(transform model.NodeTransform) {
+	c.appModel.Transforms().ChangeTransform(c.resource, c.content, c.nodeIndex, nodeTransform(c.node), transform)
+}
+
+func (c *transformEditorComponent) handleWriteBack() {
+	err := c.appModel.Transforms().WriteBack(c.resource)
+	openWriteBackResult(c.Scope(), err, c.handleCopy)
+}
+
+func (c *transformEditorComponent) handleCopy() {
+	c.appModel.Transforms().Copy(c.resource)
+}
+
+func nodeTransform(node *hierarchy.Node) model.NodeTransform {
+	return model.NodeTransform{
+		Translation: node.Position(),
+		Rotation:    node.Rotation(),
+		Scale:       node.Scale(),
+	}
+}
+
+func applyNodeTransform(node *hierarchy.Node, transform model.NodeTransform) {
+	node.SetPosition(transform.Translation)
+	node.SetRotation(transform.Rotation)
+	node.SetScale(transform.Scale)
+}
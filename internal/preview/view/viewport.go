@@ -1,14 +1,16 @@
 package view
 
 import (
-	"time"
+	"fmt"
 
 	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/gomath/dprec"
-	"github.com/mokiat/gomath/sprec"
 	"github.com/mokiat/lacking-studio/internal/global"
 	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking-studio/internal/theme"
 	"github.com/mokiat/lacking-studio/internal/viewport"
+	"github.com/mokiat/lacking-studio/internal/widget"
+	"github.com/mokiat/lacking/debug/log"
 	"github.com/mokiat/lacking/game"
 	"github.com/mokiat/lacking/game/asset"
 	"github.com/mokiat/lacking/game/graphics"
@@ -21,8 +23,6 @@ import (
 	"github.com/mokiat/lacking/ui/std"
 )
 
-const defaultExposure = 1.0
-
 var Viewport = mvc.EventListener(co.Define(&viewportComponent{}))
 
 type ViewportData struct {
@@ -39,22 +39,22 @@ type viewportComponent struct {
 	renderAPI render.API
 
 	gameEngine *game.Engine
-	gameScene  *game.Scene
 
-	commonData  *viewport.CommonData
-	cameraGizmo *viewport.CameraGizmo
+	commonData   *viewport.CommonData
+	releaseQueue *viewport.ReleaseQueue
+	cameraGizmo  *viewport.CameraGizmo
 
-	currentResourceSet *game.ResourceSet
-	newResourceSet     *game.ResourceSet
+	// loadGeneration is incremented with every load of the resource, so
+	// that results of loads that have since been superseded are ignored.
+	loadGeneration     int
+	pendingResourceSet *game.ResourceSet
 
-	gfxCamera           *graphics.Camera
-	gfxGrid             *graphics.Mesh
-	gfxAmbientLight     *graphics.AmbientLight
-	gfxDirectionalLight *graphics.DirectionalLight
-	gfxSky              *graphics.Sky
+	// dragStartTransform is the transform of the selected node from before
+	// a transform handle started being dragged.
+	dragStartTransform model.NodeTransform
 
-	modelNode     *hierarchy.Node
-	modelPlayback *game.AnimationPlayback
+	stage       *viewport.Stage
+	beforeStage *viewport.Stage
 }
 
 func (c *viewportComponent) OnCreate() {
@@ -67,80 +67,92 @@ func (c *viewportComponent) OnCreate() {
 
 	ctx := co.TypedValue[*global.Context](c.Scope())
 	c.commonData = ctx.CommonData
+	c.releaseQueue = ctx.ReleaseQueue
 	c.gameEngine = ctx.GameEngine
 
-	c.gameScene = c.gameEngine.CreateScene()
-	gfxScene := c.gameScene.Graphics()
-
-	c.gfxCamera = gfxScene.CreateCamera()
-	c.gfxCamera.SetExposure(defaultExposure)
-	c.gfxCamera.SetAutoExposure(false)
-	c.gfxCamera.SetFoV(sprec.Degrees(60))
-	c.gfxCamera.SetFoVMode(graphics.FoVModeHorizontalPlus)
-	c.gfxCamera.SetCascadeDistances([]float32{16.0, 64.0, 256.0, 1024.0})
-	c.refreshAutoExposure()
-
-	c.gfxGrid = gfxScene.CreateMesh(graphics.MeshInfo{
-		Definition: c.commonData.GridMeshDefinition(),
-	})
-	c.gfxGrid.SetMatrix(dprec.IdentityMat4())
-	c.refreshShowGrid()
-
-	c.gfxAmbientLight = gfxScene.CreateAmbientLight(graphics.AmbientLightInfo{
-		Position:          dprec.ZeroVec3(),
-		InnerRadius:       20000.0,
-		OuterRadius:       20000.0,
-		ReflectionTexture: c.commonData.SkyTexture(),
-		RefractionTexture: c.commonData.SkyTexture(),
-		CastShadow:        false,
-	})
-	c.refreshShowAmbientLight()
-
-	c.gfxDirectionalLight = gfxScene.CreateDirectionalLight(graphics.DirectionalLightInfo{
-		Position:   dprec.ZeroVec3(),
-		Rotation:   dprec.RotationQuat(dprec.Degrees(-45), dprec.BasisXVec3()),
-		EmitColor:  dprec.NewVec3(1.5, 1.5, 1.5),
-		CastShadow: true,
-	})
-	c.refreshShowDirectionalLight()
+	c.stage = c.createStage()
 
-	c.gfxSky = gfxScene.CreateSky(graphics.SkyInfo{
-		Definition: c.commonData.SkyDefinition(),
-	})
-	c.refreshShowSky()
-
-	c.cameraGizmo = viewport.NewCameraGizmo(c.gfxCamera)
+	c.cameraGizmo = viewport.NewCameraGizmo(c.stage.Environment().Camera())
+	if camera := c.appModel.Camera(); camera != nil {
+		c.applyCamera(*camera)
+	}
 
 	c.loadResource()
 }
 
 func (c *viewportComponent) OnDelete() {
-	c.gameScene.Delete()
-	if c.currentResourceSet != nil {
-		c.currentResourceSet.Delete()
+	c.cancelPendingLoad()
+	if c.beforeStage != nil {
+		c.releaseStage(c.beforeStage)
 	}
+	c.releaseStage(c.stage)
 }
 
 func (c *viewportComponent) Render() co.Instance {
+	comparison := c.appModel.Comparison()
+	physics := c.appModel.Physics()
+	measure := c.appModel.Measure()
 	return co.New(std.Element, func() {
 		co.WithLayoutData(c.Properties().LayoutData())
 		co.WithData(std.ElementData{
 			Layout: layout.Frame(),
 		})
 
-		co.WithChild("canvas", co.New(std.Viewport, func() {
+		co.WithChild("stage-area", co.New(std.Element, func() {
 			co.WithLayoutData(layout.Data{
 				HorizontalAlignment: layout.HorizontalAlignmentCenter,
 				VerticalAlignment:   layout.VerticalAlignmentCenter,
 			})
-			co.WithData(std.ViewportData{
-				API: c.renderAPI,
-			})
-			co.WithCallbackData(std.ViewportCallbackData{
-				OnKeyboardEvent: c.handleViewportKeyboardEvent,
-				OnMouseEvent:    c.handleViewportMouseEvent,
-				OnRender:        c.handleViewportRender,
+			co.WithData(std.ElementData{
+				Layout: layout.Fill(),
 			})
+
+			co.WithChild("stages", co.New(std.Element, func() {
+				co.WithData(std.ElementData{
+					Layout: wipeLayout(comparison.Wipe()),
+				})
+
+				co.WithChild("canvas", co.New(std.Viewport, func() {
+					co.WithData(std.ViewportData{
+						API: c.renderAPI,
+					})
+					co.WithCallbackData(std.ViewportCallbackData{
+						OnMouseEvent: c.handleViewportMouseEvent,
+						OnRender:     c.handleViewportRender,
+					})
+				}))
+
+				if c.isWipeActive() {
+					co.WithChild("before-clip", co.New(std.Element, func() {
+						co.WithData(std.ElementData{
+							Layout: wipeClipLayout(),
+						})
+
+						co.WithChild("before-canvas", co.New(std.Viewport, func() {
+							co.WithData(std.ViewportData{
+								API: c.renderAPI,
+							})
+							co.WithCallbackData(std.ViewportCallbackData{
+								OnMouseEvent: c.handleViewportMouseEvent,
+								OnRender:     c.handleBeforeViewportRender,
+							})
+						}))
+					}))
+
+					co.WithChild("wipe-divider", co.New(std.Container, func() {
+						co.WithData(std.ContainerData{
+							BackgroundColor: opt.V(theme.Primary()),
+						})
+					}))
+				}
+			}))
+
+			co.WithChild("labels", co.New(MeasureLabels, func() {
+				co.WithCallbackData(MeasureLabelsCallbackData{
+					OnLabels:     c.measureLabels,
+					OnMouseEvent: c.handleViewportMouseEvent,
+				})
+			}))
 		}))
 
 		co.WithChild("sidebar", co.New(std.Container, func() {
@@ -151,141 +163,748 @@ func (c *viewportComponent) Render() co.Instance {
 			})
 			co.WithData(std.ContainerData{
 				Padding:     ui.UniformSpacing(5),
-				BorderColor: opt.V(std.OutlineColor),
+				BorderColor: opt.V(theme.Outline()),
 				BorderSize: ui.Spacing{
 					Left: 1,
 				},
-				Layout: layout.Vertical(layout.VerticalSettings{
-					ContentAlignment: layout.HorizontalAlignmentLeft,
-					ContentSpacing:   10,
-				}),
+				Layout: layout.Fill(),
 			})
 
-			co.WithChild("camera-settings", co.New(std.Accordion, func() {
-				co.WithLayoutData(layout.Data{
-					GrowHorizontally: true,
-				})
-				co.WithData(std.AccordionData{
-					Title:    "Camera",
-					Expanded: c.appModel.CameraSectionExpanded(),
-				})
-				co.WithCallbackData(std.AccordionCallbackData{
-					OnToggle: c.handleCameraSectionExpandedToggle,
+			co.WithChild("scroll", co.New(std.ScrollPane, func() {
+				co.WithData(std.ScrollPaneData{
+					DisableHorizontal: true,
 				})
 
-				co.WithChild("panel", co.New(std.Container, func() {
+				co.WithChild("sections", co.New(std.Element, func() {
 					co.WithLayoutData(layout.Data{
 						GrowHorizontally: true,
 					})
-					co.WithData(std.ContainerData{
-						BorderColor: opt.V(std.OutlineColor),
-						BorderSize: ui.Spacing{
-							Left:   1,
-							Right:  1,
-							Bottom: 1,
-						},
-						Padding: ui.UniformSpacing(2),
+					co.WithData(std.ElementData{
 						Layout: layout.Vertical(layout.VerticalSettings{
 							ContentAlignment: layout.HorizontalAlignmentLeft,
 							ContentSpacing:   10,
 						}),
 					})
 
-					co.WithChild("auto-exposure", co.New(std.Checkbox, func() {
+					co.WithChild("hierarchy", co.New(std.Accordion, func() {
 						co.WithLayoutData(layout.Data{
 							GrowHorizontally: true,
 						})
-						co.WithData(std.CheckboxData{
-							Label:   "Auto Exposure",
-							Checked: c.appModel.AutoExposure(),
+						co.WithData(std.AccordionData{
+							Title:    "Hierarchy",
+							Expanded: c.appModel.HierarchySectionExpanded(),
 						})
-						co.WithCallbackData(std.CheckboxCallbackData{
-							OnToggle: c.handleAutoExposureToggle,
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handleHierarchySectionExpandedToggle,
 						})
+
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(2),
+								Layout:  layout.Fill(),
+							})
+
+							co.WithChild("tree", co.New(Hierarchy, func() {
+								co.WithData(HierarchyData{
+									Root:         c.stage.ModelNode(),
+									SelectedNode: c.selectedNode(),
+								})
+								co.WithCallbackData(HierarchyCallbackData{
+									OnSelected: c.selectNode,
+								})
+							}))
+						}))
 					}))
-				}))
-			}))
 
-			co.WithChild("scene-settings", co.New(std.Accordion, func() {
-				co.WithLayoutData(layout.Data{
-					GrowHorizontally: true,
-				})
-				co.WithData(std.AccordionData{
-					Title:    "Scene",
-					Expanded: c.appModel.SceneSectionExpanded(),
-				})
-				co.WithCallbackData(std.AccordionCallbackData{
-					OnToggle: c.handleSceneSectionExpandedToggle,
-				})
+					co.WithChild("properties", co.New(std.Accordion, func() {
+						co.WithLayoutData(layout.Data{
+							GrowHorizontally: true,
+						})
+						co.WithData(std.AccordionData{
+							Title:    "Properties",
+							Expanded: c.appModel.PropertiesSectionExpanded(),
+						})
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handlePropertiesSectionExpandedToggle,
+						})
 
-				co.WithChild("panel", co.New(std.Container, func() {
-					co.WithLayoutData(layout.Data{
-						GrowHorizontally: true,
-					})
-					co.WithData(std.ContainerData{
-						BorderColor: opt.V(std.OutlineColor),
-						BorderSize: ui.Spacing{
-							Left:   1,
-							Right:  1,
-							Bottom: 1,
-						},
-						Padding: ui.UniformSpacing(2),
-						Layout: layout.Vertical(layout.VerticalSettings{
-							ContentAlignment: layout.HorizontalAlignmentLeft,
-							ContentSpacing:   10,
-						}),
-					})
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(5),
+								Layout:  layout.Fill(),
+							})
+
+							co.WithChild("node", co.New(Properties, func() {
+								co.WithData(PropertiesData{
+									Node: c.selectedNode(),
+								})
+							}))
+						}))
+					}))
+
+					co.WithChild("transform", co.New(std.Accordion, func() {
+						co.WithLayoutData(layout.Data{
+							GrowHorizontally: true,
+						})
+						co.WithData(std.AccordionData{
+							Title:    "Transform",
+							Expanded: c.appModel.TransformSectionExpanded(),
+						})
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handleTransformSectionExpandedToggle,
+						})
+
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(5),
+								Layout:  layout.Fill(),
+							})
+
+							co.WithChild("editor", co.New(TransformEditor, func() {
+								nodeIndex, _ := c.selectedNodeIndex()
+								co.WithData(TransformEditorData{
+									AppModel:  c.appModel,
+									Resource:  c.resource,
+									Content:   c.stage.ModelContent(),
+									Node:      c.selectedNode(),
+									NodeIndex: nodeIndex,
+								})
+							}))
+						}))
+					}))
+
+					co.WithChild("materials", co.New(std.Accordion, func() {
+						co.WithLayoutData(layout.Data{
+							GrowHorizontally: true,
+						})
+						co.WithData(std.AccordionData{
+							Title:    "Materials",
+							Expanded: c.appModel.MaterialsSectionExpanded(),
+						})
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handleMaterialsSectionExpandedToggle,
+						})
+
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(5),
+								Layout:  layout.Fill(),
+							})
+
+							co.WithChild("inspector", co.New(MaterialInspector, func() {
+								co.WithData(MaterialInspectorData{
+									AppModel: c.appModel,
+									Resource: c.resource,
+									Content:  c.stage.ModelContent(),
+								})
+							}))
+						}))
+					}))
+
+					co.WithChild("physics", co.New(std.Accordion, func() {
+						co.WithLayoutData(layout.Data{
+							GrowHorizontally: true,
+						})
+						co.WithData(std.AccordionData{
+							Title:    "Physics",
+							Expanded: c.appModel.PhysicsSectionExpanded(),
+						})
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handlePhysicsSectionExpandedToggle,
+						})
+
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(2),
+								Layout: layout.Vertical(layout.VerticalSettings{
+									ContentAlignment: layout.HorizontalAlignmentLeft,
+									ContentSpacing:   10,
+								}),
+							})
 
-					co.WithChild("show-grid", co.New(std.Checkbox, func() {
+							co.WithChild("show-spheres", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Collision Spheres",
+									Checked: physics.ShowSpheres(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: physics.SetShowSpheres,
+								})
+							}))
+
+							co.WithChild("show-boxes", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Collision Boxes",
+									Checked: physics.ShowBoxes(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: physics.SetShowBoxes,
+								})
+							}))
+
+							co.WithChild("show-meshes", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Collision Meshes",
+									Checked: physics.ShowMeshes(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: physics.SetShowMeshes,
+								})
+							}))
+
+							co.WithChild("simulation", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Simulate Bodies",
+									Checked: physics.Simulation(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: physics.SetSimulation,
+								})
+							}))
+
+							if !physics.Simulation() {
+								return
+							}
+
+							co.WithChild("play", co.New(std.Button, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.ButtonData{
+									Text: c.playPhysicsText(),
+								})
+								co.WithCallbackData(std.ButtonCallbackData{
+									OnClick: c.handlePlayPhysics,
+								})
+							}))
+
+							co.WithChild("reset", co.New(std.Button, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.ButtonData{
+									Text: "Reset Simulation",
+								})
+								co.WithCallbackData(std.ButtonCallbackData{
+									OnClick: physics.ResetSimulation,
+								})
+							}))
+						}))
+					}))
+
+					co.WithChild("measure", co.New(std.Accordion, func() {
 						co.WithLayoutData(layout.Data{
 							GrowHorizontally: true,
 						})
-						co.WithData(std.CheckboxData{
-							Label:   "Grid",
-							Checked: c.appModel.ShowGrid(),
+						co.WithData(std.AccordionData{
+							Title:    "Measure",
+							Expanded: c.appModel.MeasureSectionExpanded(),
 						})
-						co.WithCallbackData(std.CheckboxCallbackData{
-							OnToggle: c.handleShowGridToggle,
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handleMeasureSectionExpandedToggle,
 						})
+
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(2),
+								Layout: layout.Vertical(layout.VerticalSettings{
+									ContentAlignment: layout.HorizontalAlignmentLeft,
+									ContentSpacing:   10,
+								}),
+							})
+
+							co.WithChild("ruler", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Ruler",
+									Checked: measure.Active(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: measure.SetActive,
+								})
+							}))
+
+							co.WithChild("show-dimensions", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Model Dimensions",
+									Checked: measure.ShowDimensions(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: measure.SetShowDimensions,
+								})
+							}))
+
+							co.WithChild("unit", co.New(std.Dropdown, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.DropdownData{
+									Items:       c.lengthUnitItems(),
+									SelectedKey: measure.Unit(),
+								})
+								co.WithCallbackData(std.DropdownCallbackData{
+									OnItemSelected: c.handleLengthUnitSelected,
+								})
+							}))
+
+							for i, line := range c.measureSummary() {
+								co.WithChild(fmt.Sprintf("summary-%d", i), co.New(std.Label, func() {
+									co.WithData(std.LabelData{
+										Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
+										FontSize:  opt.V(float32(16)),
+										FontColor: opt.V(theme.OnSurface()),
+										Text:      line,
+									})
+								}))
+							}
+
+							co.WithChild("clear", co.New(std.Button, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.ButtonData{
+									Text:    "Clear Ruler",
+									Enabled: opt.V(len(measure.Points(c.resource)) > 0),
+								})
+								co.WithCallbackData(std.ButtonCallbackData{
+									OnClick: measure.Clear,
+								})
+							}))
+						}))
 					}))
 
-					co.WithChild("show-ambient-light", co.New(std.Checkbox, func() {
+					co.WithChild("dependencies", co.New(std.Accordion, func() {
 						co.WithLayoutData(layout.Data{
 							GrowHorizontally: true,
 						})
-						co.WithData(std.CheckboxData{
-							Label:   "Default Ambient Light",
-							Checked: c.appModel.ShowAmbientLight(),
+						co.WithData(std.AccordionData{
+							Title:    "Dependencies",
+							Expanded: c.appModel.DependenciesSectionExpanded(),
 						})
-						co.WithCallbackData(std.CheckboxCallbackData{
-							OnToggle: c.handleShowAmbientLightToggle,
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handleDependenciesSectionExpandedToggle,
 						})
+
+						// NOTE: The graph is only requested while the section is
+						// expanded, since measuring it reads all of the content.
+						if !c.appModel.DependenciesSectionExpanded() {
+							return
+						}
+
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(5),
+								Layout:  layout.Fill(),
+							})
+
+							co.WithChild("graph", co.New(Dependencies, func() {
+								co.WithData(DependenciesData{
+									AppModel: c.appModel,
+									Resource: c.resource,
+								})
+							}))
+						}))
 					}))
 
-					co.WithChild("show-directional-light", co.New(std.Checkbox, func() {
+					co.WithChild("camera-settings", co.New(std.Accordion, func() {
 						co.WithLayoutData(layout.Data{
 							GrowHorizontally: true,
 						})
-						co.WithData(std.CheckboxData{
-							Label:   "Default Directional Light",
-							Checked: c.appModel.ShowDirectionalLight(),
+						co.WithData(std.AccordionData{
+							Title:    "Camera",
+							Expanded: c.appModel.CameraSectionExpanded(),
 						})
-						co.WithCallbackData(std.CheckboxCallbackData{
-							OnToggle: c.handleShowDirectionalLightToggle,
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handleCameraSectionExpandedToggle,
 						})
+
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(2),
+								Layout: layout.Vertical(layout.VerticalSettings{
+									ContentAlignment: layout.HorizontalAlignmentLeft,
+									ContentSpacing:   10,
+								}),
+							})
+
+							co.WithChild("view-mode", co.New(std.Dropdown, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.DropdownData{
+									Items:       c.viewModeItems(),
+									SelectedKey: c.appModel.ViewMode(),
+								})
+								co.WithCallbackData(std.DropdownCallbackData{
+									OnItemSelected: c.handleViewModeSelected,
+								})
+							}))
+
+							co.WithChild("auto-exposure", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Auto Exposure",
+									Checked: c.appModel.AutoExposure(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleAutoExposureToggle,
+								})
+							}))
+						}))
 					}))
 
-					co.WithChild("show-sky", co.New(std.Checkbox, func() {
+					co.WithChild("scene-settings", co.New(std.Accordion, func() {
 						co.WithLayoutData(layout.Data{
 							GrowHorizontally: true,
 						})
-						co.WithData(std.CheckboxData{
-							Label:   "Default Sky",
-							Checked: c.appModel.ShowSky(),
+						co.WithData(std.AccordionData{
+							Title:    "Scene",
+							Expanded: c.appModel.SceneSectionExpanded(),
 						})
-						co.WithCallbackData(std.CheckboxCallbackData{
-							OnToggle: c.handleShowSkyToggle,
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handleSceneSectionExpandedToggle,
 						})
+
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(2),
+								Layout: layout.Vertical(layout.VerticalSettings{
+									ContentAlignment: layout.HorizontalAlignmentLeft,
+									ContentSpacing:   10,
+								}),
+							})
+
+							co.WithChild("show-grid", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Grid",
+									Checked: c.appModel.ShowGrid(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleShowGridToggle,
+								})
+							}))
+
+							co.WithChild("show-ambient-light", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Default Ambient Light",
+									Checked: c.appModel.ShowAmbientLight(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleShowAmbientLightToggle,
+								})
+							}))
+
+							co.WithChild("show-directional-light", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Default Directional Light",
+									Checked: c.appModel.ShowDirectionalLight(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleShowDirectionalLightToggle,
+								})
+							}))
+
+							co.WithChild("show-sky", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Default Sky",
+									Checked: c.appModel.ShowSky(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleShowSkyToggle,
+								})
+							}))
+
+							co.WithChild("show-bounds", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Bounding Box",
+									Checked: c.appModel.ShowBounds(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleShowBoundsToggle,
+								})
+							}))
+
+							co.WithChild("show-node-bounds", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Node Bounding Boxes",
+									Checked: c.appModel.ShowNodeBounds(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleShowNodeBoundsToggle,
+								})
+							}))
+
+							co.WithChild("show-skeleton", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Skeleton",
+									Checked: c.appModel.ShowSkeleton(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleShowSkeletonToggle,
+								})
+							}))
+
+							co.WithChild("show-normals", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Normals",
+									Checked: c.appModel.ShowNormals(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleShowNormalsToggle,
+								})
+							}))
+
+							co.WithChild("show-tangents", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Tangents",
+									Checked: c.appModel.ShowTangents(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleShowTangentsToggle,
+								})
+							}))
+						}))
+					}))
+
+					co.WithChild("comparison-settings", co.New(std.Accordion, func() {
+						co.WithLayoutData(layout.Data{
+							GrowHorizontally: true,
+						})
+						co.WithData(std.AccordionData{
+							Title:    "Comparison",
+							Expanded: c.appModel.ComparisonSectionExpanded(),
+						})
+						co.WithCallbackData(std.AccordionCallbackData{
+							OnToggle: c.handleComparisonSectionExpandedToggle,
+						})
+
+						co.WithChild("panel", co.New(std.Container, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ContainerData{
+								BorderColor: opt.V(theme.Outline()),
+								BorderSize: ui.Spacing{
+									Left:   1,
+									Right:  1,
+									Bottom: 1,
+								},
+								Padding: ui.UniformSpacing(2),
+								Layout: layout.Vertical(layout.VerticalSettings{
+									ContentAlignment: layout.HorizontalAlignmentLeft,
+									ContentSpacing:   10,
+								}),
+							})
+
+							co.WithChild("enabled", co.New(std.Checkbox, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.CheckboxData{
+									Label:   "Keep Previous Version",
+									Checked: comparison.Enabled(),
+								})
+								co.WithCallbackData(std.CheckboxCallbackData{
+									OnToggle: c.handleComparisonEnabledToggle,
+								})
+							}))
+
+							if !comparison.Enabled() {
+								return
+							}
+
+							co.WithChild("mode", co.New(std.Dropdown, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.DropdownData{
+									Items:       c.compareModeItems(),
+									SelectedKey: comparison.Mode(),
+								})
+								co.WithCallbackData(std.DropdownCallbackData{
+									OnItemSelected: c.handleCompareModeSelected,
+								})
+							}))
+
+							switch comparison.Mode() {
+							case model.CompareModeToggle:
+								co.WithChild("show-before", co.New(std.Checkbox, func() {
+									co.WithLayoutData(layout.Data{
+										GrowHorizontally: true,
+									})
+									co.WithData(std.CheckboxData{
+										Label:   "Show Previous Version",
+										Checked: comparison.ShowBefore(),
+									})
+									co.WithCallbackData(std.CheckboxCallbackData{
+										OnToggle: c.handleShowBeforeToggle,
+									})
+								}))
+							case model.CompareModeWipe:
+								co.WithChild("wipe", co.New(widget.Slider, func() {
+									co.WithLayoutData(layout.Data{
+										GrowHorizontally: true,
+									})
+									co.WithData(widget.SliderData{
+										Value: comparison.Wipe(),
+									})
+									co.WithCallbackData(widget.SliderCallbackData{
+										OnChange: c.handleWipeChange,
+									})
+								}))
+							}
+
+							if statsDiff := comparison.StatsDiff(); statsDiff != "" {
+								co.WithChild("stats-diff", co.New(std.Label, func() {
+									co.WithData(std.LabelData{
+										Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
+										FontSize:  opt.V(float32(16)),
+										FontColor: opt.V(theme.OnSurface()),
+										Text:      statsDiff,
+									})
+								}))
+							}
+
+							co.WithChild("discard", co.New(std.Button, func() {
+								co.WithLayoutData(layout.Data{
+									GrowHorizontally: true,
+								})
+								co.WithData(std.ButtonData{
+									Text:    "Discard Previous Version",
+									Enabled: opt.V(c.beforeStage != nil),
+								})
+								co.WithCallbackData(std.ButtonCallbackData{
+									OnClick: c.discardBeforeStage,
+								})
+							}))
+						}))
 					}))
 				}))
 			}))
@@ -294,10 +913,23 @@ func (c *viewportComponent) Render() co.Instance {
 }
 
 func (c *viewportComponent) OnEvent(event mvc.Event) {
-	switch event.(type) {
+	switch event := event.(type) {
 	case model.RefreshEvent:
 		c.loadResource()
 		c.Invalidate()
+	case model.ViewModeChangedEvent:
+		if c.isSelected() {
+			c.refreshViewMode()
+		}
+		c.Invalidate()
+	case model.FrameModelEvent:
+		if c.isSelected() {
+			c.frameModel()
+		}
+	case model.CameraChangedEvent:
+		if c.appModel.IsResourceVisible(c.resource) {
+			c.applyCamera(event.Camera)
+		}
 	case model.CameraSectionExpandedChangedEvent:
 		c.Invalidate()
 	case model.AutoExposureChangedEvent:
@@ -317,35 +949,267 @@ func (c *viewportComponent) OnEvent(event mvc.Event) {
 	case model.ShowSkyChangedEvent:
 		c.refreshShowSky()
 		c.Invalidate()
+	case model.ShowBoundsChangedEvent, model.ShowNodeBoundsChangedEvent, model.ShowSkeletonChangedEvent, model.ShowNormalsChangedEvent, model.ShowTangentsChangedEvent:
+		c.refreshOverlays()
+		c.Invalidate()
+	case model.ComparisonSectionExpandedChangedEvent:
+		c.Invalidate()
+	case model.HierarchySectionExpandedChangedEvent:
+		c.Invalidate()
+	case model.PropertiesSectionExpandedChangedEvent:
+		c.Invalidate()
+	case model.SelectionChangedEvent:
+		c.refreshTransformGizmo()
+		c.Invalidate()
+	case model.TransformSectionExpandedChangedEvent:
+		c.Invalidate()
+	case model.TransformSettingsChangedEvent:
+		if c.stage.HasModel() && c.isDynamic() && !c.stage.IsDynamic() {
+			// NOTE: Static models can't be edited, so the model needs to be
+			// loaded again as a dynamic one.
+			c.loadResource()
+		}
+		c.refreshTransformGizmo()
+		c.Invalidate()
+	case model.NodeTransformChangedEvent:
+		if event.Resource == c.resource {
+			c.applyTransforms(c.stage)
+			c.Invalidate()
+		}
+	case model.MaterialsSectionExpandedChangedEvent:
+		c.Invalidate()
+	case model.MaterialsChangedEvent:
+		if event.Resource == c.resource {
+			c.loadResource()
+			c.Invalidate()
+		}
+	case model.PhysicsSectionExpandedChangedEvent:
+		c.Invalidate()
+	case model.PhysicsSettingsChangedEvent:
+		if c.stage.HasModel() && c.isDynamic() && !c.stage.IsDynamic() {
+			// NOTE: Only dynamic models get bodies, so the model needs to be
+			// loaded again in order to be simulated.
+			c.loadResource()
+		}
+		c.refreshPhysics()
+		c.Invalidate()
+	case model.PhysicsResetEvent:
+		if c.stage.HasModel() {
+			// NOTE: Bodies cannot be moved back reliably while they are in
+			// contact, so a fresh model is loaded instead.
+			c.loadResource()
+		}
+	case model.MeasureSectionExpandedChangedEvent:
+		c.Invalidate()
+	case model.MeasureChangedEvent:
+		c.Invalidate()
+	case model.DependenciesSectionExpandedChangedEvent:
+		c.Invalidate()
+	case model.DependenciesChangedEvent:
+		c.Invalidate()
+	case model.ComparisonChangedEvent:
+		c.refreshComparison()
+		c.Invalidate()
+	}
+}
+
+func (c *viewportComponent) createStage() *viewport.Stage {
+	stage := viewport.NewStage(c.gameEngine, c.commonData)
+	environment := stage.Environment()
+	environment.SetAutoExposure(c.appModel.AutoExposure())
+	environment.SetShowGrid(c.appModel.ShowGrid())
+	environment.SetShowAmbientLight(c.appModel.ShowAmbientLight())
+	environment.SetShowDirectionalLight(c.appModel.ShowDirectionalLight())
+	environment.SetShowSky(c.appModel.ShowSky())
+	stage.SetOverlayFilter(c.overlayFilter())
+	return stage
+}
+
+// stages returns all stages that are currently held by the viewport.
+func (c *viewportComponent) stages() []*viewport.Stage {
+	if c.beforeStage != nil {
+		return []*viewport.Stage{c.stage, c.beforeStage}
 	}
+	return []*viewport.Stage{c.stage}
+}
+
+func (c *viewportComponent) releaseStage(stage *viewport.Stage) {
+	c.releaseQueue.Release(stage.Delete)
 }
 
 func (c *viewportComponent) loadResource() {
-	c.newResourceSet = c.gameEngine.CreateResourceSet()
-	promise := c.newResourceSet.OpenModelByID(c.resource.ID())
+	c.cancelPendingLoad()
+
+	generation := c.loadGeneration
+	resourceSet := c.gameEngine.CreateResourceSet()
+	c.pendingResourceSet = resourceSet
+
+	promise := resourceSet.OpenModelByID(c.resource.ID())
 	promise.OnSuccess(func(modelDefinition *game.ModelDefinition) {
+		// NOTE: The raw content is needed for picking, since the model
+		// definition does not expose the geometry bounds.
+		var content *asset.Model
+		if modelContent, err := c.resource.OpenContent(); err == nil {
+			content = &modelContent
+		} else {
+			log.Warn("Error opening content of %q: %v", c.resource.Name(), err)
+		}
 		co.Schedule(c.Scope(), func() {
-			c.handleModelLoaded(modelDefinition)
+			if generation == c.loadGeneration {
+				c.pendingResourceSet = nil
+				c.handleModelLoaded(resourceSet, modelDefinition, content)
+			}
 		})
 	})
 	promise.OnError(func(err error) {
 		co.Schedule(c.Scope(), func() {
-			c.handleModelLoadError(err)
+			if generation == c.loadGeneration {
+				c.cancelPendingLoad()
+				c.handleModelLoadError(err)
+			}
 		})
 	})
 }
 
-func (c *viewportComponent) handleViewportKeyboardEvent(element *ui.Element, event ui.KeyboardEvent) bool {
-	return c.cameraGizmo.OnKeyboardEvent(element, event)
+// cancelPendingLoad makes sure that the result of any load that is still
+// in progress is ignored. The ResourceSet of such a load is released as
+// soon as the load completes, since nothing could have rendered from it.
+func (c *viewportComponent) cancelPendingLoad() {
+	c.loadGeneration++
+	if c.pendingResourceSet != nil {
+		c.pendingResourceSet.Delete()
+		c.pendingResourceSet = nil
+	}
 }
 
 func (c *viewportComponent) handleViewportMouseEvent(element *ui.Element, event ui.MouseEvent) bool {
-	return c.cameraGizmo.OnMouseEvent(element, event)
+	gizmo := c.stage.TransformGizmo()
+	switch {
+	case event.Action == ui.MouseActionDown && event.Button == ui.MouseButtonLeft && c.appModel.Measure().Active():
+		c.pickMeasurePoint(element, event.X, event.Y)
+		return true
+	case event.Action == ui.MouseActionDown && event.Button == ui.MouseButtonLeft:
+		if gizmo.BeginDrag(c.screenRay(element, event.X, event.Y)) {
+			c.dragStartTransform = nodeTransform(gizmo.Target())
+			return true
+		}
+		c.pickNode(element, event.X, event.Y)
+		return true
+	case event.Action == ui.MouseActionMove && gizmo.Dragging():
+		gizmo.Drag(c.screenRay(element, event.X, event.Y))
+		return true
+	case event.Action == ui.MouseActionUp && event.Button == ui.MouseButtonLeft && gizmo.Dragging():
+		gizmo.EndDrag()
+		node := gizmo.Target()
+		if index, ok := c.stage.NodeIndex(node); ok {
+			c.appModel.Transforms().ChangeTransform(c.resource, c.stage.ModelContent(), index, c.dragStartTransform, nodeTransform(node))
+		}
+		return true
+	case event.Action == ui.MouseActionLeave && gizmo.Dragging():
+		gizmo.CancelDrag()
+		return false
+	}
+	if !c.cameraGizmo.OnMouseEvent(element, event) {
+		return false
+	}
+	c.storeCamera()
+	return true
+}
+
+func (c *viewportComponent) isSelected() bool {
+	return c.appModel.SelectedResource() == c.resource
+}
+
+// selectedNodeIndex returns the index of the selected node, if the
+// resource of this viewport is the selected one.
+func (c *viewportComponent) selectedNodeIndex() (int, bool) {
+	if !c.isSelected() {
+		return 0, false
+	}
+	return c.appModel.Selection().NodeIndex()
+}
+
+// selectedNode returns the node of the current stage that is selected.
+func (c *viewportComponent) selectedNode() *hierarchy.Node {
+	index, ok := c.selectedNodeIndex()
+	if !ok {
+		return nil
+	}
+	if volume, ok := c.stage.PickVolume(index); ok {
+		return volume.Node
+	}
+	return nil
+}
+
+func (c *viewportComponent) screenRay(element *ui.Element, x, y int) viewport.Ray {
+	bounds := element.Bounds()
+	return viewport.ScreenRay(c.cameraGizmo.Matrix(), dprec.Degrees(viewport.DefaultFoV), x, y, bounds.Width, bounds.Height)
+}
+
+func (c *viewportComponent) pickNode(element *ui.Element, x, y int) {
+	if node, ok := c.stage.Pick(c.screenRay(element, x, y)); ok {
+		c.selectNode(node)
+	} else if c.isSelected() {
+		c.appModel.Selection().Clear()
+	}
+}
+
+func (c *viewportComponent) selectNode(node *hierarchy.Node) {
+	if !c.isSelected() {
+		c.appModel.SetSelectedResource(c.resource)
+	}
+	if index, ok := c.stage.NodeIndex(node); ok {
+		c.appModel.Selection().SetNodeIndex(index)
+	}
+}
+
+func (c *viewportComponent) applyCamera(camera model.CameraSettings) {
+	c.cameraGizmo.SetPosition(camera.Position)
+	c.cameraGizmo.SetYaw(camera.Yaw)
+	c.cameraGizmo.SetPitch(camera.Pitch)
+	c.cameraGizmo.SetZoom(camera.Zoom)
+}
+
+func (c *viewportComponent) storeCamera() {
+	c.appModel.SetCamera(model.CameraSettings{
+		Position: c.cameraGizmo.Position(),
+		Yaw:      c.cameraGizmo.Yaw(),
+		Pitch:    c.cameraGizmo.Pitch(),
+		Zoom:     c.cameraGizmo.Zoom(),
+	})
 }
 
 func (c *viewportComponent) handleViewportRender(framebuffer render.Framebuffer, size ui.Size) {
-	c.gameEngine.Update()
-	c.gameEngine.Render(framebuffer, graphics.Viewport{
+	c.renderStage(c.displayedStage(), framebuffer, size)
+}
+
+func (c *viewportComponent) handleBeforeViewportRender(framebuffer render.Framebuffer, size ui.Size) {
+	if c.beforeStage != nil {
+		c.renderStage(c.beforeStage, framebuffer, size)
+	}
+}
+
+func (c *viewportComponent) renderStage(stage *viewport.Stage, framebuffer render.Framebuffer, size ui.Size) {
+	// NOTE: The camera gizmo drives the camera of the current stage only,
+	// so the camera of any other stage needs to be kept in sync.
+	stage.Environment().Camera().SetMatrix(c.cameraGizmo.Matrix())
+
+	stage.TransformGizmo().Update(c.cameraGizmo.Matrix())
+
+	debug := c.gameEngine.Graphics().Debug()
+	debug.Reset()
+	if index, ok := c.selectedNodeIndex(); ok {
+		if volume, ok := stage.PickVolume(index); ok {
+			center, radius := volume.Sphere()
+			viewport.DrawSphere(debug, center, radius, viewport.SelectionColor)
+		}
+	}
+	if filter := c.physicsShapeFilter(); !filter.IsEmpty() {
+		viewport.DrawBodyShapes(debug, stage.BodyShapes(), filter, viewport.PhysicsColor)
+	}
+	stage.DrawOverlays(debug)
+	c.drawMeasurement(stage, debug)
+	stage.Render(framebuffer, graphics.Viewport{
 		X:      0,
 		Y:      0,
 		Width:  uint32(size.Width),
@@ -353,56 +1217,308 @@ func (c *viewportComponent) handleViewportRender(framebuffer render.Framebuffer,
 	})
 }
 
-func (c *viewportComponent) handleModelLoaded(modelDefinition *game.ModelDefinition) {
-	if c.modelPlayback != nil {
-		c.gameScene.StopAnimationTree(c.modelPlayback)
-		c.modelPlayback = nil
+// displayedStage returns the stage that should be rendered in the main
+// canvas of the viewport.
+func (c *viewportComponent) displayedStage() *viewport.Stage {
+	comparison := c.appModel.Comparison()
+	if c.beforeStage != nil && comparison.Mode() == model.CompareModeToggle && comparison.ShowBefore() {
+		return c.beforeStage
 	}
-	if c.modelNode != nil {
-		c.modelNode.Delete()
-		c.modelNode = nil
+	return c.stage
+}
+
+func (c *viewportComponent) isWipeActive() bool {
+	return c.beforeStage != nil && c.appModel.Comparison().Mode() == model.CompareModeWipe
+}
+
+func (c *viewportComponent) handleModelLoaded(resourceSet *game.ResourceSet, modelDefinition *game.ModelDefinition, content *asset.Model) {
+	stage := c.createStage()
+	stage.ShowModel(viewport.StageModelInfo{
+		ResourceSet: resourceSet,
+		Definition:  modelDefinition,
+		Content:     content,
+		Dynamic:     c.isDynamic(),
+	})
+	c.applyTransforms(stage)
+
+	oldStage := c.stage
+	c.stage = stage
+	c.cameraGizmo.SetCamera(stage.Environment().Camera())
+
+	if c.appModel.Comparison().Enabled() && oldStage.HasModel() {
+		if c.beforeStage != nil {
+			c.releaseStage(c.beforeStage)
+		}
+		oldStage.HideGhost()
+		c.beforeStage = oldStage
+	} else {
+		c.releaseStage(oldStage)
 	}
-	if c.currentResourceSet != nil {
-		resourceSet := c.currentResourceSet
-		// TODO: Figure out a more elegant approach to this.
-		co.After(c.Scope(), time.Second, func() {
-			resourceSet.Delete()
+	c.refreshComparison()
+	c.refreshTransformGizmo()
+	c.refreshPhysics()
+	c.Invalidate()
+}
+
+func (c *viewportComponent) handleModelLoadError(err error) {
+}
+
+func (c *viewportComponent) handleHierarchySectionExpandedToggle(expanded bool) {
+	c.appModel.SetHierarchySectionExpanded(expanded)
+}
+
+func (c *viewportComponent) handlePropertiesSectionExpandedToggle(expanded bool) {
+	c.appModel.SetPropertiesSectionExpanded(expanded)
+}
+
+func (c *viewportComponent) handleTransformSectionExpandedToggle(expanded bool) {
+	c.appModel.SetTransformSectionExpanded(expanded)
+}
+
+func (c *viewportComponent) handleMaterialsSectionExpandedToggle(expanded bool) {
+	c.appModel.SetMaterialsSectionExpanded(expanded)
+}
+
+// isDynamic returns whether the nodes of the model need to be editable,
+// either because an editing tool is active, because there are previewed
+// edits to the nodes, or because the bodies of the model are simulated.
+func (c *viewportComponent) isDynamic() bool {
+	transforms := c.appModel.Transforms()
+	return transforms.Tool().IsEditing() || transforms.HasChanges(c.resource) || c.appModel.Physics().Simulation()
+}
+
+func (c *viewportComponent) applyTransforms(stage *viewport.Stage) {
+	modelNode := stage.ModelNode()
+	if modelNode == nil || !stage.IsDynamic() {
+		return
+	}
+	for index, transform := range c.appModel.Transforms().Transforms(c.resource) {
+		if volume, ok := stage.PickVolume(index); ok {
+			applyNodeTransform(volume.Node, transform)
+		}
+	}
+}
+
+func (c *viewportComponent) refreshTransformGizmo() {
+	transforms := c.appModel.Transforms()
+	gizmo := c.stage.TransformGizmo()
+
+	var target *hierarchy.Node
+	if transforms.Tool().IsEditing() && c.stage.IsDynamic() {
+		target = c.selectedNode()
+	}
+	if target != gizmo.Target() {
+		gizmo.SetTarget(target)
+	}
+
+	switch transforms.Tool() {
+	case model.TransformToolTranslate:
+		gizmo.SetMode(viewport.TransformModeTranslate)
+	case model.TransformToolRotate:
+		gizmo.SetMode(viewport.TransformModeRotate)
+	case model.TransformToolScale:
+		gizmo.SetMode(viewport.TransformModeScale)
+	}
+	if transforms.LocalSpace() {
+		gizmo.SetSpace(viewport.TransformSpaceLocal)
+	} else {
+		gizmo.SetSpace(viewport.TransformSpaceWorld)
+	}
+	if transforms.Snapping() {
+		gizmo.SetSnapping(viewport.Snapping{
+			Translation: transforms.TranslationSnap(),
+			Rotation:    transforms.RotationSnap(),
+			Scale:       transforms.ScaleSnap(),
 		})
+	} else {
+		gizmo.SetSnapping(viewport.Snapping{})
 	}
-	c.currentResourceSet = c.newResourceSet
+}
 
-	model := c.gameScene.CreateModel(game.ModelInfo{
-		Name:       "Model",
-		Definition: modelDefinition,
-		IsDynamic:  false, // NOTE: Setting this to true kills large scenes
-	})
-	c.modelNode = model.Root()
-	if len(model.Animations()) > 0 {
-		animation := model.Animations()[0]
-		c.modelPlayback = animation.Playback().SetLoop(true)
-		c.gameScene.PlayAnimationTree(c.modelPlayback)
+func (c *viewportComponent) handlePhysicsSectionExpandedToggle(expanded bool) {
+	c.appModel.SetPhysicsSectionExpanded(expanded)
+}
+
+func (c *viewportComponent) handlePlayPhysics() {
+	physics := c.appModel.Physics()
+	physics.SetPlaying(!physics.Playing())
+}
+
+func (c *viewportComponent) playPhysicsText() string {
+	if c.appModel.Physics().Playing() {
+		return "Pause"
 	}
-	// TODO: Find camera and light nodes and attach indicator gizmos to them
-	// from the common data.
+	return "Play"
 }
 
-func (c *viewportComponent) handleModelLoadError(err error) {
+func (c *viewportComponent) physicsShapeFilter() viewport.PhysicsShapeFilter {
+	physics := c.appModel.Physics()
+	return viewport.PhysicsShapeFilter{
+		Spheres: physics.ShowSpheres(),
+		Boxes:   physics.ShowBoxes(),
+		Meshes:  physics.ShowMeshes(),
+	}
+}
+
+// refreshPhysics starts or pauses the simulation of the current stage. The
+// stage of the previous version is never simulated.
+func (c *viewportComponent) refreshPhysics() {
+	c.stage.SetPhysicsRunning(c.appModel.Physics().Playing())
+}
+
+func (c *viewportComponent) handleMeasureSectionExpandedToggle(expanded bool) {
+	c.appModel.SetMeasureSectionExpanded(expanded)
+}
+
+func (c *viewportComponent) handleDependenciesSectionExpandedToggle(expanded bool) {
+	c.appModel.SetDependenciesSectionExpanded(expanded)
+}
+
+func (c *viewportComponent) lengthUnitItems() []std.DropdownItem {
+	units := model.LengthUnits()
+	result := make([]std.DropdownItem, len(units))
+	for i, unit := range units {
+		result[i] = std.DropdownItem{
+			Key:   unit,
+			Label: unit.String(),
+		}
+	}
+	return result
+}
+
+func (c *viewportComponent) handleLengthUnitSelected(key any) {
+	c.appModel.Measure().SetUnit(key.(model.LengthUnit))
+}
+
+func (c *viewportComponent) pickMeasurePoint(element *ui.Element, x, y int) {
+	if point, ok := c.stage.PickSurface(c.screenRay(element, x, y)); ok {
+		c.appModel.Measure().AddPoint(c.resource, point)
+	}
+}
+
+// measureSummary returns the lines of text that describe the ruler
+// measurement and the model dimensions.
+func (c *viewportComponent) measureSummary() []string {
+	measure := c.appModel.Measure()
+	unit := measure.Unit()
+
+	var result []string
+	if distance, ok := measure.Distance(c.resource); ok {
+		result = append(result, "Distance: "+unit.Format(distance))
+	} else if measure.Active() {
+		result = append(result, "Click two points on the model.")
+	}
+	if measure.ShowDimensions() {
+		if minCoord, maxCoord, ok := c.stage.ModelBounds(); ok {
+			size := dprec.Vec3Diff(maxCoord, minCoord)
+			result = append(result,
+				"Width (X): "+unit.Format(size.X),
+				"Height (Y): "+unit.Format(size.Y),
+				"Depth (Z): "+unit.Format(size.Z),
+			)
+		}
+	}
+	return result
+}
+
+func (c *viewportComponent) drawMeasurement(stage *viewport.Stage, debug *graphics.Debug) {
+	measure := c.appModel.Measure()
+	viewport.DrawMeasurement(debug, measure.Points(c.resource), viewport.MeasureColor)
+	if measure.ShowDimensions() {
+		if minCoord, maxCoord, ok := stage.ModelBounds(); ok {
+			viewport.DrawDimensions(debug, minCoord, maxCoord)
+		}
+	}
+}
+
+// measureLabels places the texts of the ruler and of the model dimensions
+// at the middle of the lines that they describe.
+func (c *viewportComponent) measureLabels(size ui.Size) []MeasureLabel {
+	measure := c.appModel.Measure()
+	unit := measure.Unit()
+
+	var result []MeasureLabel
+	addLabel := func(from, to dprec.Vec3, text string) {
+		middle := dprec.Vec3Prod(dprec.Vec3Sum(from, to), 0.5)
+		x, y, ok := viewport.ScreenPosition(c.cameraGizmo.Matrix(), dprec.Degrees(viewport.DefaultFoV), middle, size.Width, size.Height)
+		if ok {
+			result = append(result, MeasureLabel{
+				X:    x,
+				Y:    y,
+				Text: text,
+			})
+		}
+	}
+
+	if points := measure.Points(c.resource); len(points) >= 2 {
+		addLabel(points[0], points[1], unit.Format(dprec.Vec3Diff(points[1], points[0]).Length()))
+	}
+	if measure.ShowDimensions() {
+		if minCoord, maxCoord, ok := c.displayedStage().ModelBounds(); ok {
+			axisNames := [3]string{"X", "Y", "Z"}
+			for i, edge := range viewport.DimensionEdges(minCoord, maxCoord) {
+				addLabel(edge[0], edge[1], axisNames[i]+": "+unit.Format(dprec.Vec3Diff(edge[1], edge[0]).Length()))
+			}
+		}
+	}
+	return result
 }
 
 func (c *viewportComponent) handleCameraSectionExpandedToggle(expanded bool) {
 	c.appModel.SetCameraSectionExpanded(expanded)
 }
 
+func (c *viewportComponent) viewModeItems() []std.DropdownItem {
+	modes := model.ViewModes()
+	result := make([]std.DropdownItem, len(modes))
+	for i, mode := range modes {
+		result[i] = std.DropdownItem{
+			Key:   mode,
+			Label: mode.String(),
+		}
+	}
+	return result
+}
+
+func (c *viewportComponent) handleViewModeSelected(key any) {
+	c.appModel.SetViewMode(key.(model.ViewMode))
+}
+
+func (c *viewportComponent) refreshViewMode() {
+	switch c.appModel.ViewMode() {
+	case model.ViewModePerspective:
+		c.cameraGizmo.SetYaw(dprec.Degrees(15))
+		c.cameraGizmo.SetPitch(dprec.Degrees(30))
+	case model.ViewModeFront:
+		c.cameraGizmo.SetYaw(dprec.Degrees(0))
+		c.cameraGizmo.SetPitch(dprec.Degrees(0))
+	case model.ViewModeSide:
+		c.cameraGizmo.SetYaw(dprec.Degrees(90))
+		c.cameraGizmo.SetPitch(dprec.Degrees(0))
+	case model.ViewModeTop:
+		c.cameraGizmo.SetYaw(dprec.Degrees(0))
+		c.cameraGizmo.SetPitch(dprec.Degrees(90))
+	}
+	c.storeCamera()
+}
+
+func (c *viewportComponent) frameModel() {
+	modelNode := c.stage.ModelNode()
+	if modelNode == nil {
+		return
+	}
+	center, radius := viewport.NodeBounds(modelNode)
+	c.cameraGizmo.Frame(center, radius)
+	c.storeCamera()
+}
+
 func (c *viewportComponent) handleAutoExposureToggle(checked bool) {
 	c.appModel.SetAutoExposure(checked)
 }
 
 func (c *viewportComponent) refreshAutoExposure() {
-	if c.appModel.AutoExposure() {
-		c.gfxCamera.SetAutoExposure(true)
-	} else {
-		c.gfxCamera.SetExposure(defaultExposure)
-		c.gfxCamera.SetAutoExposure(false)
+	for _, stage := range c.stages() {
+		stage.Environment().SetAutoExposure(c.appModel.AutoExposure())
 	}
 }
 
@@ -415,7 +1531,9 @@ func (c *viewportComponent) handleShowGridToggle(checked bool) {
 }
 
 func (c *viewportComponent) refreshShowGrid() {
-	c.gfxGrid.SetActive(c.appModel.ShowGrid())
+	for _, stage := range c.stages() {
+		stage.Environment().SetShowGrid(c.appModel.ShowGrid())
+	}
 }
 
 func (c *viewportComponent) handleShowAmbientLightToggle(checked bool) {
@@ -423,7 +1541,9 @@ func (c *viewportComponent) handleShowAmbientLightToggle(checked bool) {
 }
 
 func (c *viewportComponent) refreshShowAmbientLight() {
-	c.gfxAmbientLight.SetActive(c.appModel.ShowAmbientLight())
+	for _, stage := range c.stages() {
+		stage.Environment().SetShowAmbientLight(c.appModel.ShowAmbientLight())
+	}
 }
 
 func (c *viewportComponent) handleShowDirectionalLightToggle(checked bool) {
@@ -431,7 +1551,9 @@ func (c *viewportComponent) handleShowDirectionalLightToggle(checked bool) {
 }
 
 func (c *viewportComponent) refreshShowDirectionalLight() {
-	c.gfxDirectionalLight.SetActive(c.appModel.ShowDirectionalLight())
+	for _, stage := range c.stages() {
+		stage.Environment().SetShowDirectionalLight(c.appModel.ShowDirectionalLight())
+	}
 }
 
 func (c *viewportComponent) handleShowSkyToggle(checked bool) {
@@ -439,5 +1561,98 @@ func (c *viewportComponent) handleShowSkyToggle(checked bool) {
 }
 
 func (c *viewportComponent) refreshShowSky() {
-	c.gfxSky.SetActive(c.appModel.ShowSky())
+	for _, stage := range c.stages() {
+		stage.Environment().SetShowSky(c.appModel.ShowSky())
+	}
+}
+
+func (c *viewportComponent) handleShowBoundsToggle(checked bool) {
+	c.appModel.SetShowBounds(checked)
+}
+
+func (c *viewportComponent) handleShowNodeBoundsToggle(checked bool) {
+	c.appModel.SetShowNodeBounds(checked)
+}
+
+func (c *viewportComponent) handleShowSkeletonToggle(checked bool) {
+	c.appModel.SetShowSkeleton(checked)
+}
+
+func (c *viewportComponent) handleShowNormalsToggle(checked bool) {
+	c.appModel.SetShowNormals(checked)
+}
+
+func (c *viewportComponent) handleShowTangentsToggle(checked bool) {
+	c.appModel.SetShowTangents(checked)
+}
+
+func (c *viewportComponent) overlayFilter() viewport.OverlayFilter {
+	return viewport.OverlayFilter{
+		Bounds:     c.appModel.ShowBounds(),
+		NodeBounds: c.appModel.ShowNodeBounds(),
+		Skeleton:   c.appModel.ShowSkeleton(),
+		Normals:    c.appModel.ShowNormals(),
+		Tangents:   c.appModel.ShowTangents(),
+	}
+}
+
+func (c *viewportComponent) refreshOverlays() {
+	for _, stage := range c.stages() {
+		stage.SetOverlayFilter(c.overlayFilter())
+	}
+}
+
+func (c *viewportComponent) handleComparisonSectionExpandedToggle(expanded bool) {
+	c.appModel.SetComparisonSectionExpanded(expanded)
+}
+
+func (c *viewportComponent) handleComparisonEnabledToggle(checked bool) {
+	c.appModel.Comparison().SetEnabled(checked)
+}
+
+func (c *viewportComponent) compareModeItems() []std.DropdownItem {
+	modes := model.CompareModes()
+	result := make([]std.DropdownItem, len(modes))
+	for i, mode := range modes {
+		result[i] = std.DropdownItem{
+			Key:   mode,
+			Label: mode.String(),
+		}
+	}
+	return result
+}
+
+func (c *viewportComponent) handleCompareModeSelected(key any) {
+	c.appModel.Comparison().SetMode(key.(model.CompareMode))
+}
+
+func (c *viewportComponent) handleShowBeforeToggle(checked bool) {
+	c.appModel.Comparison().SetShowBefore(checked)
+}
+
+func (c *viewportComponent) handleWipeChange(value float64) {
+	c.appModel.Comparison().SetWipe(value)
+}
+
+func (c *viewportComponent) discardBeforeStage() {
+	if c.beforeStage == nil {
+		return
+	}
+	c.stage.HideGhost()
+	c.releaseStage(c.beforeStage)
+	c.beforeStage = nil
+	c.Invalidate()
+}
+
+func (c *viewportComponent) refreshComparison() {
+	comparison := c.appModel.Comparison()
+	if !comparison.Enabled() {
+		c.discardBeforeStage()
+		return
+	}
+	if c.beforeStage != nil && comparison.Mode() == model.CompareModeOverlay {
+		c.stage.ShowGhost(c.beforeStage.ModelContent())
+	} else {
+		c.stage.HideGhost()
+	}
 }
@@ -0,0 +1,69 @@
+package view
+
+import "github.com/mokiat/lacking/ui"
+
+const wipeDividerWidth = 2
+
+// wipeLayout stretches the first child over the whole element and limits
+// the second child to the specified fraction of the width, starting from
+// the left. The third child, if present, is used as a divider that marks
+// the edge of the second child.
+func wipeLayout(fraction float64) ui.Layout {
+	return &wipeLayoutImpl{
+		fraction: min(max(fraction, 0.0), 1.0),
+	}
+}
+
+type wipeLayoutImpl struct {
+	fraction float64
+}
+
+func (l *wipeLayoutImpl) Apply(element *ui.Element) {
+	contentBounds := element.ContentBounds()
+	wipeWidth := int(float64(contentBounds.Width) * l.fraction)
+
+	var index int
+	for childElement := element.FirstChild(); childElement != nil; childElement = childElement.RightSibling() {
+		switch index {
+		case 0:
+			childElement.SetBounds(contentBounds)
+		case 1:
+			childElement.SetBounds(ui.Bounds{
+				Position: contentBounds.Position,
+				Size:     ui.NewSize(wipeWidth, contentBounds.Height),
+			})
+		case 2:
+			childElement.SetBounds(ui.Bounds{
+				Position: ui.NewPosition(contentBounds.X+wipeWidth-wipeDividerWidth/2, contentBounds.Y),
+				Size:     ui.NewSize(wipeDividerWidth, contentBounds.Height),
+			})
+		}
+		index++
+	}
+
+	element.SetIdealSize(element.Padding().Size())
+}
+
+// wipeClipLayout sizes the children of an element to match the content
+// area of its parent. Combined with wipeLayout, this allows the children
+// to be cut off at the wipe edge, instead of being squashed.
+func wipeClipLayout() ui.Layout {
+	return &wipeClipLayoutImpl{}
+}
+
+type wipeClipLayoutImpl struct{}
+
+func (l *wipeClipLayoutImpl) Apply(element *ui.Element) {
+	var size ui.Size
+	if parent := element.Parent(); parent != nil {
+		size = parent.ContentBounds().Size
+	}
+	for childElement := element.FirstChild(); childElement != nil; childElement = childElement.RightSibling() {
+		childElement.SetBounds(ui.Bounds{
+			Position: ui.NewPosition(0, 0),
+			Size:     size,
+		})
+	}
+
+	element.SetIdealSize(element.Padding().Size())
+}
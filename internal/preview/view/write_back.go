@@ -0,0 +1,33 @@
+package view
+
+import (
+	"github.com/mokiat/lacking-studio/internal/widget"
+	"github.com/mokiat/lacking/debug/log"
+	co "github.com/mokiat/lacking/ui/component"
+)
+
+// openWriteBackResult notifies the user of the outcome of writing edits
+// back to the source. Edits that could not be written can still be copied
+// as DSL with the specified function and pasted by hand.
+func openWriteBackResult(scope co.Scope, err error, copyDSL func()) {
+	if err != nil {
+		log.Error("Error writing to source: %v", err)
+		co.OpenOverlay(scope, co.New(widget.NotificationModal, func() {
+			co.WithData(widget.NotificationModalData{
+				Icon:       co.OpenImage(scope, "icons/error.png"),
+				Text:       "Error writing to source.\n\nCheck logs for more info.",
+				ActionText: "Copy as DSL",
+			})
+			co.WithCallbackData(widget.NotificationModalCallbackData{
+				OnAction: copyDSL,
+			})
+		}))
+		return
+	}
+	co.OpenOverlay(scope, co.New(widget.NotificationModal, func() {
+		co.WithData(widget.NotificationModalData{
+			Icon: co.OpenImage(scope, "icons/info.png"),
+			Text: "Written to source.\n\nRefresh to pack the resource again.",
+		})
+	}))
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// NewArchiveStorage creates a read-only asset.Storage for the assets in a
+// zip or tar archive, such as the one of a shipped build. The format is
+// determined by the file extension: .zip, .tar, .tar.gz or .tgz.
+//
+// The assets can be placed in a directory inside the archive, which is
+// located by its resources.dat file.
+func NewArchiveStorage(archivePath string) (*ArchiveStorage, error) {
+	lowerPath := strings.ToLower(archivePath)
+	switch {
+	case strings.HasSuffix(lowerPath, ".zip"):
+		return newZipStorage(archivePath)
+	case strings.HasSuffix(lowerPath, ".tar"):
+		return newTarStorage(archivePath, false)
+	case strings.HasSuffix(lowerPath, ".tar.gz"), strings.HasSuffix(lowerPath, ".tgz"):
+		return newTarStorage(archivePath, true)
+	default:
+		return nil, fmt.Errorf("unsupported archive %q", archivePath)
+	}
+}
+
+// NOTE: The zip file stays open for the lifetime of the process, since
+// asset.Storage has no way to be closed.
+func newZipStorage(archivePath string) (*ArchiveStorage, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening zip archive: %w", err)
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		if !file.FileInfo().IsDir() {
+			files[path.Clean(file.Name)] = file
+		}
+	}
+	prefix, err := assetsPrefix(mapKeys(files))
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	return &ArchiveStorage{readOnlyStorage{
+		open: func(name string) (io.ReadCloser, error) {
+			file, ok := files[path.Join(prefix, name)]
+			if !ok {
+				return nil, fmt.Errorf("error opening %q: %w", name, asset.ErrNotFound)
+			}
+			return file.Open()
+		},
+	}}, nil
+}
+
+// NOTE: Tar archives cannot be read at random, so their content is loaded
+// into memory in full.
+func newTarStorage(archivePath string, compressed bool) (*ArchiveStorage, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening tar archive: %w", err)
+	}
+	defer file.Close()
+
+	var in io.Reader = file
+	if compressed {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("error opening gzip stream: %w", err)
+		}
+		defer gzipReader.Close()
+		in = gzipReader
+	}
+
+	files := make(map[string][]byte)
+	tarReader := tar.NewReader(in)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading tar archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %q from tar archive: %w", header.Name, err)
+		}
+		files[path.Clean(header.Name)] = data
+	}
+	prefix, err := assetsPrefix(mapKeys(files))
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveStorage{readOnlyStorage{
+		open: func(name string) (io.ReadCloser, error) {
+			data, ok := files[path.Join(prefix, name)]
+			if !ok {
+				return nil, fmt.Errorf("error opening %q: %w", name, asset.ErrNotFound)
+			}
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+	}}, nil
+}
+
+// assetsPrefix returns the directory inside an archive that contains the
+// registry file. The shallowest one is used, if there are several.
+func assetsPrefix(names []string) (string, error) {
+	var (
+		prefix string
+		found  bool
+	)
+	for _, name := range names {
+		if path.Base(name) != registryFile {
+			continue
+		}
+		dir := path.Dir(name)
+		if !found || strings.Count(dir, "/") < strings.Count(prefix, "/") {
+			prefix = dir
+			found = true
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("archive does not contain %s", registryFile)
+	}
+	return prefix, nil
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
+
+// ArchiveStorage is a read-only asset.Storage for the assets in an
+// archive.
+type ArchiveStorage struct {
+	readOnlyStorage
+}
+
+// OpenFile opens a file by its path relative to the assets directory in
+// the archive.
+func (s *ArchiveStorage) OpenFile(name string) (io.ReadCloser, error) {
+	return s.open(name)
+}
+
+// readOnlyStorage is an asset.Storage that rejects all modifications.
+type readOnlyStorage struct {
+	open func(name string) (io.ReadCloser, error)
+}
+
+func (s *readOnlyStorage) OpenRegistryRead() (io.ReadCloser, error) {
+	return s.open(registryFile)
+}
+
+func (s *readOnlyStorage) OpenRegistryWrite() (io.WriteCloser, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (s *readOnlyStorage) OpenContentRead(id string) (io.ReadCloser, error) {
+	return s.open(contentFile(id))
+}
+
+func (s *readOnlyStorage) OpenContentWrite(id string) (io.WriteCloser, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (s *readOnlyStorage) DeleteContent(id string) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// HTTPTimeout is the time limit of the requests of an HTTP storage that
+// uses the default client, so that a hung server cannot block the loading
+// of the registry forever.
+const HTTPTimeout = time.Minute
+
+// NewHTTPStorage creates a read-only asset.Storage that fetches the assets
+// from the specified base URL, which serves the layout of an assets
+// directory. If no client is specified, one with HTTPTimeout is used.
+func NewHTTPStorage(baseURL string, client *http.Client) (asset.Storage, error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing assets URL: %w", err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported assets URL scheme %q", parsedURL.Scheme)
+	}
+	if client == nil {
+		client = &http.Client{
+			Timeout: HTTPTimeout,
+		}
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return &readOnlyStorage{
+		open: func(name string) (io.ReadCloser, error) {
+			return fetch(client, baseURL+"/"+name)
+		},
+	}, nil
+}
+
+func fetch(client *http.Client, fileURL string) (io.ReadCloser, error) {
+	response, err := client.Get(fileURL)
+	if err != nil {
+		return nil, fmt.Errorf("error performing request: %w", err)
+	}
+	switch response.StatusCode {
+	case http.StatusOK:
+		return response.Body, nil
+	case http.StatusNotFound:
+		response.Body.Close()
+		return nil, fmt.Errorf("error fetching %q: %w", fileURL, asset.ErrNotFound)
+	default:
+		response.Body.Close()
+		return nil, fmt.Errorf("unexpected status code %d for %q", response.StatusCode, fileURL)
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	files := map[string]string{
+		"/assets/resources.dat":     "registry",
+		"/assets/content/model.dat": "content",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func readAll(t *testing.T, open func() (io.ReadCloser, error)) string {
+	t.Helper()
+	in, err := open()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer in.Close()
+	data, err := io.ReadAll(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(data)
+}
+
+func TestHTTPStorageRead(t *testing.T) {
+	server := newTestServer(t)
+	storage, err := NewHTTPStorage(server.URL+"/assets/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data := readAll(t, storage.OpenRegistryRead); data != "registry" {
+		t.Errorf("unexpected registry %q", data)
+	}
+	if data := readAll(t, func() (io.ReadCloser, error) {
+		return storage.OpenContentRead("model")
+	}); data != "content" {
+		t.Errorf("unexpected content %q", data)
+	}
+}
+
+func TestHTTPStorageNotFound(t *testing.T) {
+	server := newTestServer(t)
+	storage, err := NewHTTPStorage(server.URL+"/assets", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := storage.OpenContentRead("missing"); !errors.Is(err, asset.ErrNotFound) {
+		t.Errorf("expected asset.ErrNotFound, got %v", err)
+	}
+}
+
+func TestHTTPStorageWrite(t *testing.T) {
+	server := newTestServer(t)
+	storage, err := NewHTTPStorage(server.URL+"/assets", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := storage.OpenRegistryWrite(); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("expected errors.ErrUnsupported for the registry, got %v", err)
+	}
+	if _, err := storage.OpenContentWrite("model"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("expected errors.ErrUnsupported for content, got %v", err)
+	}
+	if err := storage.DeleteContent("model"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("expected errors.ErrUnsupported for deletion, got %v", err)
+	}
+}
+
+func TestHTTPStorageTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	storage, err := NewHTTPStorage(server.URL, &http.Client{
+		Timeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.OpenRegistryRead(); err == nil {
+		t.Errorf("expected a timeout error")
+	}
+}
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"fmt"
+	"path"
+)
+
+// NOTE: The layout matches the one of the file system storage of the
+// engine, so that an assets directory can be archived or served as is.
+
+const registryFile = "resources.dat"
+
+func contentFile(id string) string {
+	return path.Join("content", fmt.Sprintf("%s.dat", id))
+}
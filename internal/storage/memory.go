@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// NewMemoryStorage creates an asset.Storage that keeps all data in memory.
+// It starts out empty and is mostly useful for tests.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		files: make(map[string][]byte),
+	}
+}
+
+// MemoryStorage is an asset.Storage that keeps all data in memory. It is
+// safe for concurrent use.
+type MemoryStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+var _ asset.Storage = (*MemoryStorage)(nil)
+
+func (s *MemoryStorage) OpenRegistryRead() (io.ReadCloser, error) {
+	return s.open(registryFile)
+}
+
+func (s *MemoryStorage) OpenRegistryWrite() (io.WriteCloser, error) {
+	return s.create(registryFile), nil
+}
+
+func (s *MemoryStorage) OpenContentRead(id string) (io.ReadCloser, error) {
+	return s.open(contentFile(id))
+}
+
+func (s *MemoryStorage) OpenContentWrite(id string) (io.WriteCloser, error) {
+	return s.create(contentFile(id)), nil
+}
+
+func (s *MemoryStorage) DeleteContent(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := contentFile(id)
+	if _, ok := s.files[name]; !ok {
+		return asset.ErrNotFound
+	}
+	delete(s.files, name)
+	return nil
+}
+
+// Files returns the names of the stored files, in the layout of an assets
+// directory.
+func (s *MemoryStorage) Files() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]string, 0, len(s.files))
+	for name := range s.files {
+		result = append(result, name)
+	}
+	return result
+}
+
+func (s *MemoryStorage) open(name string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[name]
+	if !ok {
+		return nil, fmt.Errorf("error opening %q: %w", name, asset.ErrNotFound)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *MemoryStorage) create(name string) io.WriteCloser {
+	return &memoryWriter{
+		storage: s,
+		name:    name,
+	}
+}
+
+// memoryWriter stores its data in the storage once it is closed, so that
+// readers never observe partially written files.
+type memoryWriter struct {
+	bytes.Buffer
+	storage *MemoryStorage
+	name    string
+}
+
+func (w *memoryWriter) Close() error {
+	w.storage.mu.Lock()
+	defer w.storage.mu.Unlock()
+	w.storage.files[w.name] = bytes.Clone(w.Bytes())
+	return nil
+}
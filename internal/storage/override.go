@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// NewOverrideStorage creates an asset.Storage that passes everything through
+// to the specified storage, except for the content of resources that has
+// been overridden, which is kept in memory and encoded with the specified
+// formatter.
+func NewOverrideStorage(base asset.Storage, formatter asset.Formatter) *OverrideStorage {
+	return &OverrideStorage{
+		base:      base,
+		formatter: formatter,
+		overrides: NewMemoryStorage(),
+	}
+}
+
+// OverrideStorage is an asset.Storage that allows the content of resources
+// to be replaced for as long as the application runs, without changing the
+// underlying storage.
+type OverrideStorage struct {
+	base      asset.Storage
+	formatter asset.Formatter
+	overrides *MemoryStorage
+}
+
+var _ asset.Storage = (*OverrideStorage)(nil)
+
+func (s *OverrideStorage) OpenRegistryRead() (io.ReadCloser, error) {
+	return s.base.OpenRegistryRead()
+}
+
+func (s *OverrideStorage) OpenRegistryWrite() (io.WriteCloser, error) {
+	return s.base.OpenRegistryWrite()
+}
+
+func (s *OverrideStorage) OpenContentRead(id string) (io.ReadCloser, error) {
+	if in, err := s.overrides.OpenContentRead(id); err == nil {
+		return in, nil
+	}
+	return s.base.OpenContentRead(id)
+}
+
+func (s *OverrideStorage) OpenContentWrite(id string) (io.WriteCloser, error) {
+	return s.base.OpenContentWrite(id)
+}
+
+func (s *OverrideStorage) DeleteContent(id string) error {
+	s.ResetContent(id)
+	return s.base.DeleteContent(id)
+}
+
+// OverrideContent replaces the content of the resource with the specified
+// ID with the specified one.
+func (s *OverrideStorage) OverrideContent(id string, content asset.Model) error {
+	out, err := s.overrides.OpenContentWrite(id)
+	if err != nil {
+		return fmt.Errorf("error opening override: %w", err)
+	}
+	// NOTE: The writer is not closed on error, since memory writers only
+	// store their data once closed.
+	if err := s.formatter.Encode(out, content); err != nil {
+		return fmt.Errorf("error encoding content: %w", err)
+	}
+	return out.Close()
+}
+
+// ResetContent restores the content of the resource with the specified ID
+// to the one in the underlying storage.
+func (s *OverrideStorage) ResetContent(id string) {
+	// NOTE: Content that was not overridden is not found, which is fine.
+	_ = s.overrides.DeleteContent(id)
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+func TestOverrideStorageKeepsBaseContent(t *testing.T) {
+	formatter := asset.NewBlobFormatter()
+	base := NewMemoryStorage()
+	baseRegistry, err := asset.NewRegistry(base, formatter)
+	if err != nil {
+		t.Fatalf("error creating registry: %v", err)
+	}
+	resource, err := baseRegistry.CreateResource("Model", asset.Model{
+		Nodes: []asset.Node{{Name: "Packed"}},
+	})
+	if err != nil {
+		t.Fatalf("error creating resource: %v", err)
+	}
+
+	storage := NewOverrideStorage(base, formatter)
+	registry, err := asset.NewRegistry(storage, formatter)
+	if err != nil {
+		t.Fatalf("error creating registry: %v", err)
+	}
+	overridden := registry.ResourceByID(resource.ID())
+	if err := storage.OverrideContent(resource.ID(), asset.Model{
+		Nodes: []asset.Node{{Name: "Edited"}},
+	}); err != nil {
+		t.Fatalf("error overriding content: %v", err)
+	}
+
+	nodeName := func(resource *asset.Resource) string {
+		t.Helper()
+		content, err := resource.OpenContent()
+		if err != nil {
+			t.Fatalf("error opening content: %v", err)
+		}
+		return content.Nodes[0].Name
+	}
+	if name := nodeName(overridden); name != "Edited" {
+		t.Fatalf("expected overridden content, got %q", name)
+	}
+	if name := nodeName(resource); name != "Packed" {
+		t.Fatalf("expected base content to be unchanged, got %q", name)
+	}
+
+	storage.ResetContent(resource.ID())
+	if name := nodeName(overridden); name != "Packed" {
+		t.Fatalf("expected base content after reset, got %q", name)
+	}
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"io"
+
+	"github.com/mokiat/lacking/game/asset"
+)
+
+// ContentSize returns the size in bytes of the content of the resource
+// with the specified ID.
+func ContentSize(storage asset.Storage, id string) (int64, error) {
+	// NOTE: The storage interface has no way to stat a file, so the
+	// content is read in full.
+	in, err := storage.OpenContentRead(id)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	return io.Copy(io.Discard, in)
+}
@@ -0,0 +1,160 @@
+package theme
+
+import (
+	"github.com/mokiat/lacking/ui"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+const (
+	NameLight Name = "light"
+	NameDark  Name = "dark"
+)
+
+// Name identifies a theme.
+type Name string
+
+// Palette holds the colors of a theme.
+type Palette struct {
+	Primary          ui.Color
+	OnPrimary        ui.Color
+	PrimaryLight     ui.Color
+	OnPrimaryLight   ui.Color
+	PrimaryDark      ui.Color
+	OnPrimaryDark    ui.Color
+	Secondary        ui.Color
+	OnSecondary      ui.Color
+	SecondaryLight   ui.Color
+	OnSecondaryLight ui.Color
+	SecondaryDark    ui.Color
+	OnSecondaryDark  ui.Color
+	Background       ui.Color
+	OnBackground     ui.Color
+	Surface          ui.Color
+	OnSurface        ui.Color
+	Error            ui.Color
+	OnError          ui.Color
+	Outline          ui.Color
+	HoverOverlay     ui.Color
+	PressOverlay     ui.Color
+	ModalOverlay     ui.Color
+}
+
+var LightPalette = Palette{
+	Primary:          ui.RGB(0x40, 0x89, 0xBD),
+	OnPrimary:        ui.RGB(0x00, 0x00, 0x00),
+	PrimaryLight:     ui.RGB(0x7C, 0xAE, 0xEB),
+	OnPrimaryLight:   ui.RGB(0x00, 0x00, 0x00),
+	PrimaryDark:      ui.RGB(0x32, 0x59, 0x73),
+	OnPrimaryDark:    ui.RGB(0xFF, 0xFF, 0xFF),
+	Secondary:        ui.RGB(0x8B, 0xC3, 0x4A),
+	OnSecondary:      ui.RGB(0x00, 0x00, 0x00),
+	SecondaryLight:   ui.RGB(0xA9, 0xC2, 0x8A),
+	OnSecondaryLight: ui.RGB(0x00, 0x00, 0x00),
+	SecondaryDark:    ui.RGB(0x4B, 0x6B, 0x3A),
+	OnSecondaryDark:  ui.RGB(0xFF, 0xFF, 0xFF),
+	Background:       ui.RGB(0x3B, 0x3B, 0x3B),
+	OnBackground:     ui.RGB(0xFF, 0xFF, 0xFF),
+	Surface:          ui.RGB(0xFF, 0xFF, 0xFF),
+	OnSurface:        ui.RGB(0x00, 0x00, 0x00),
+	Error:            ui.RGB(0xB3, 0x1E, 0x00),
+	OnError:          ui.RGB(0xFF, 0xFF, 0xFF),
+	Outline:          ui.RGB(0xB0, 0xB0, 0xB0),
+	HoverOverlay:     ui.RGBA(0x00, 0x00, 0x00, 0x40),
+	PressOverlay:     ui.RGBA(0x00, 0x00, 0x00, 0x80),
+	ModalOverlay:     ui.RGBA(0x00, 0x00, 0x00, 0xA0),
+}
+
+var DarkPalette = Palette{
+	Primary:          ui.RGB(0x4F, 0x9A, 0xD0),
+	OnPrimary:        ui.RGB(0x00, 0x00, 0x00),
+	PrimaryLight:     ui.RGB(0x7C, 0xAE, 0xEB),
+	OnPrimaryLight:   ui.RGB(0x00, 0x00, 0x00),
+	PrimaryDark:      ui.RGB(0x32, 0x59, 0x73),
+	OnPrimaryDark:    ui.RGB(0xFF, 0xFF, 0xFF),
+	Secondary:        ui.RGB(0x6E, 0x9E, 0x38),
+	OnSecondary:      ui.RGB(0xFF, 0xFF, 0xFF),
+	SecondaryLight:   ui.RGB(0x8B, 0xA8, 0x6A),
+	OnSecondaryLight: ui.RGB(0x00, 0x00, 0x00),
+	SecondaryDark:    ui.RGB(0x3A, 0x55, 0x2C),
+	OnSecondaryDark:  ui.RGB(0xFF, 0xFF, 0xFF),
+	Background:       ui.RGB(0x1E, 0x1E, 0x1E),
+	OnBackground:     ui.RGB(0xE0, 0xE0, 0xE0),
+	Surface:          ui.RGB(0x2B, 0x2B, 0x2B),
+	OnSurface:        ui.RGB(0xE0, 0xE0, 0xE0),
+	Error:            ui.RGB(0xCF, 0x66, 0x79),
+	OnError:          ui.RGB(0x00, 0x00, 0x00),
+	Outline:          ui.RGB(0x55, 0x55, 0x55),
+	HoverOverlay:     ui.RGBA(0xFF, 0xFF, 0xFF, 0x20),
+	PressOverlay:     ui.RGBA(0xFF, 0xFF, 0xFF, 0x40),
+	ModalOverlay:     ui.RGBA(0x00, 0x00, 0x00, 0xC0),
+}
+
+var current = LightPalette
+
+// PaletteByName returns the palette of the specified theme. Unknown names
+// fall back to the light palette.
+func PaletteByName(name Name) Palette {
+	switch name {
+	case NameDark:
+		return DarkPalette
+	default:
+		return LightPalette
+	}
+}
+
+// Apply makes the specified palette the active one. The std component
+// colors are updated as well, so that library components match the
+// studio ones. Components need to be re-rendered for the change to take
+// effect.
+func Apply(palette Palette) {
+	current = palette
+
+	std.PrimaryColor = palette.Primary
+	std.OnPrimaryColor = palette.OnPrimary
+	std.PrimaryLightColor = palette.PrimaryLight
+	std.OnPrimaryLightColor = palette.OnPrimaryLight
+	std.PrimaryDarkColor = palette.PrimaryDark
+	std.OnPrimaryDarkColor = palette.OnPrimaryDark
+	std.SecondaryColor = palette.Secondary
+	std.OnSecondaryColor = palette.OnSecondary
+	std.SecondaryLightColor = palette.SecondaryLight
+	std.OnSecondaryLightColor = palette.OnSecondaryLight
+	std.SecondaryDarkColor = palette.SecondaryDark
+	std.OnSecondaryDarkColor = palette.OnSecondaryDark
+	std.BackgroundColor = palette.Background
+	std.OnBackgroundColor = palette.OnBackground
+	std.SurfaceColor = palette.Surface
+	std.OnSurfaceColor = palette.OnSurface
+	std.ErrorColor = palette.Error
+	std.OnErrorColor = palette.OnError
+	std.OutlineColor = palette.Outline
+	std.HoverOverlayColor = palette.HoverOverlay
+	std.PressOverlayColor = palette.PressOverlay
+	std.ModalOverlayColor = palette.ModalOverlay
+}
+
+// Surface returns the background color of panels.
+func Surface() ui.Color {
+	return current.Surface
+}
+
+// OnSurface returns the color of text and icons that are placed on
+// a surface.
+func OnSurface() ui.Color {
+	return current.OnSurface
+}
+
+// Outline returns the color of borders and separators.
+func Outline() ui.Color {
+	return current.Outline
+}
+
+// Error returns the color used to highlight problems.
+func Error() ui.Color {
+	return current.Error
+}
+
+// Primary returns the accent color of interactive elements.
+func Primary() ui.Color {
+	return current.Primary
+}
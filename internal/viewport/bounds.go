@@ -0,0 +1,41 @@
+package viewport
+
+import (
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+// NodeBounds returns a sphere that encloses the absolute positions of the
+// specified node and all of its descendants.
+//
+// NOTE: Mesh geometry is not accessible through the hierarchy, so the
+// result is only an approximation of the visible extent of a model.
+func NodeBounds(node *hierarchy.Node) (dprec.Vec3, float64) {
+	var (
+		minPosition dprec.Vec3
+		maxPosition dprec.Vec3
+		initialized bool
+	)
+	node.Visit(func(current *hierarchy.Node) {
+		position := current.AbsoluteMatrix().Translation()
+		if !initialized {
+			minPosition = position
+			maxPosition = position
+			initialized = true
+			return
+		}
+		minPosition = dprec.NewVec3(
+			dprec.Min(minPosition.X, position.X),
+			dprec.Min(minPosition.Y, position.Y),
+			dprec.Min(minPosition.Z, position.Z),
+		)
+		maxPosition = dprec.NewVec3(
+			dprec.Max(maxPosition.X, position.X),
+			dprec.Max(maxPosition.Y, position.Y),
+			dprec.Max(maxPosition.Z, position.Z),
+		)
+	})
+	center := dprec.Vec3Quot(dprec.Vec3Sum(minPosition, maxPosition), 2.0)
+	radius := dprec.Vec3Diff(maxPosition, center).Length()
+	return center, radius
+}
@@ -8,6 +8,11 @@ import (
 	"github.com/mokiat/lacking/ui"
 )
 
+const (
+	minFrameRadius      = 0.5
+	frameDistanceFactor = 2.5
+)
+
 func NewCameraGizmo(camera *graphics.Camera) *CameraGizmo {
 	gizmo := &CameraGizmo{
 		camera:   camera,
@@ -31,17 +36,63 @@ type CameraGizmo struct {
 	oldMouseX float64
 	oldMouseY float64
 	wheelDown bool
-	shiftDown bool
 }
 
-func (g *CameraGizmo) OnKeyboardEvent(element *ui.Element, event ui.KeyboardEvent) bool {
-	switch event.Code {
-	case ui.KeyCodeLeftShift:
-		g.shiftDown = (event.Action != ui.KeyboardActionUp)
-		return true
-	}
+func (g *CameraGizmo) Position() dprec.Vec3 {
+	return g.position
+}
 
-	return false
+func (g *CameraGizmo) SetPosition(position dprec.Vec3) {
+	g.position = position
+	g.updateCamera()
+}
+
+func (g *CameraGizmo) Yaw() dprec.Angle {
+	return g.yaw
+}
+
+func (g *CameraGizmo) SetYaw(yaw dprec.Angle) {
+	g.yaw = yaw
+	g.updateCamera()
+}
+
+func (g *CameraGizmo) Pitch() dprec.Angle {
+	return g.pitch
+}
+
+func (g *CameraGizmo) SetPitch(pitch dprec.Angle) {
+	g.pitch = pitch
+	g.updateCamera()
+}
+
+func (g *CameraGizmo) Zoom() float64 {
+	return g.zoom
+}
+
+func (g *CameraGizmo) SetZoom(zoom float64) {
+	g.zoom = zoom
+	g.updateCamera()
+}
+
+// SetCamera changes the camera that is controlled by this gizmo.
+func (g *CameraGizmo) SetCamera(camera *graphics.Camera) {
+	g.camera = camera
+	g.updateCamera()
+}
+
+// Matrix returns the transformation of the camera that this gizmo
+// currently describes.
+func (g *CameraGizmo) Matrix() dprec.Mat4 {
+	return g.cameraMatrix()
+}
+
+// Frame positions the camera so that the specified sphere is in view,
+// keeping the current orientation.
+func (g *CameraGizmo) Frame(center dprec.Vec3, radius float64) {
+	radius = dprec.Max(radius, minFrameRadius)
+	g.position = center
+	g.zoom = math.Log2(radius * frameDistanceFactor)
+	g.updateCamera()
 }
 
 func (g *CameraGizmo) OnMouseEvent(element *ui.Element, event ui.MouseEvent) bool {
@@ -69,7 +120,7 @@ func (g *CameraGizmo) OnMouseEvent(element *ui.Element, event ui.MouseEvent) boo
 		g.oldMouseY = newMouseY
 
 		switch {
-		case g.wheelDown && g.shiftDown:
+		case g.wheelDown && event.Modifiers.Contains(ui.KeyModifierShift):
 			g.handlePan(deltaMouseX, -deltaMouseY)
 			element.Invalidate()
 			return true
@@ -2,6 +2,7 @@ package viewport
 
 import (
 	"github.com/mokiat/gblob"
+	"github.com/mokiat/gog/opt"
 	"github.com/mokiat/gomath/dprec"
 	"github.com/mokiat/gomath/dtos"
 	"github.com/mokiat/gomath/sprec"
@@ -31,6 +32,7 @@ type CommonData struct {
 	grayMaterial       *graphics.Material
 	yellowMaterial     *graphics.Material
 	darkYellowMaterial *graphics.Material
+	ghostMaterial      *graphics.Material
 
 	gridGeometry *graphics.MeshGeometry
 	gridMeshDef  *graphics.MeshDefinition
@@ -52,6 +54,15 @@ type CommonData struct {
 
 	directionalLightMeshGeometry *graphics.MeshGeometry
 	directionalLightMeshDef      *graphics.MeshDefinition
+
+	translateHandleMeshGeometries [3]*graphics.MeshGeometry
+	translateHandleMeshDefs       [3]*graphics.MeshDefinition
+
+	rotateHandleMeshGeometries [3]*graphics.MeshGeometry
+	rotateHandleMeshDefs       [3]*graphics.MeshDefinition
+
+	scaleHandleMeshGeometries [3]*graphics.MeshGeometry
+	scaleHandleMeshDefs       [3]*graphics.MeshDefinition
 }
 
 func (d *CommonData) Create() {
@@ -64,6 +75,7 @@ func (d *CommonData) Create() {
 	d.createPointLightMesh()
 	d.createSpotLightMesh()
 	d.createDirectionalLightMesh()
+	d.createHandleMeshes()
 }
 
 func (d *CommonData) Delete() {
@@ -77,6 +89,7 @@ func (d *CommonData) Delete() {
 	defer d.deletePointLightMesh()
 	defer d.deleteSpotLightMesh()
 	defer d.deleteDirectionalLightMesh()
+	defer d.deleteHandleMeshes()
 }
 
 func (d *CommonData) SkyColor() dprec.Vec4 {
@@ -119,6 +132,24 @@ func (d *CommonData) DirectionalLightMeshDefinition() *graphics.MeshDefinition {
 	return d.directionalLightMeshDef
 }
 
+// TranslateHandleMeshDefinition returns the mesh of the translation handle
+// for the specified axis (0 for X, 1 for Y and 2 for Z).
+func (d *CommonData) TranslateHandleMeshDefinition(axis int) *graphics.MeshDefinition {
+	return d.translateHandleMeshDefs[axis]
+}
+
+// RotateHandleMeshDefinition returns the mesh of the rotation handle
+// for the specified axis (0 for X, 1 for Y and 2 for Z).
+func (d *CommonData) RotateHandleMeshDefinition(axis int) *graphics.MeshDefinition {
+	return d.rotateHandleMeshDefs[axis]
+}
+
+// ScaleHandleMeshDefinition returns the mesh of the scale handle
+// for the specified axis (0 for X, 1 for Y and 2 for Z).
+func (d *CommonData) ScaleHandleMeshDefinition(axis int) *graphics.MeshDefinition {
+	return d.scaleHandleMeshDefs[axis]
+}
+
 func colorToRGBA32FData(color sprec.Vec3) []byte {
 	color = sprec.Vec3Prod(sprec.NewVec3(1.66, 1.81, 1.86), 0.4)
 	colorData := make(gblob.LittleEndianBlock, 4*4)
@@ -293,6 +324,23 @@ func (d *CommonData) createMaterials() {
 		},
 	})
 	d.darkYellowMaterial.SetProperty("color", sprec.NewVec4(0.3, 0.3, 0.0, 1.0))
+
+	// NOTE: Blending of forward passes is additive, so the color of the
+	// ghost is kept dark in order for the model behind it to show through.
+	// It does not write depth and has no culling, so that hidden parts of
+	// the ghost show as well.
+	d.ghostMaterial = d.gfxEngine.CreateMaterial(graphics.MaterialInfo{
+		Name: "Ghost",
+		ForwardPasses: []graphics.MaterialPassInfo{
+			{
+				Culling:    opt.V(render.CullModeNone),
+				DepthWrite: opt.V(false),
+				Blending:   opt.V(true),
+				Shader:     colorShader,
+			},
+		},
+	})
+	d.ghostMaterial.SetProperty("color", sprec.NewVec4(0.08, 0.14, 0.2, 1.0))
 }
 
 func (d *CommonData) deleteMaterials() {
@@ -640,6 +688,63 @@ func (d *CommonData) deleteDirectionalLightMesh() {
 	defer d.directionalLightMeshDef.Delete()
 }
 
+func (d *CommonData) createHandleMeshes() {
+	// NOTE: The colors follow the grid and node meshes, where X is red,
+	// Y is blue and Z is green.
+	materials := [3]*graphics.Material{d.redMaterial, d.blueMaterial, d.greenMaterial}
+
+	// Rotations that turn the Y axis, along which the cone shape is built,
+	// into the respective handle axis.
+	axisRotations := [3]sprec.Quat{
+		sprec.RotationQuat(sprec.Degrees(-90), sprec.BasisZVec3()),
+		sprec.IdentityQuat(),
+		sprec.RotationQuat(sprec.Degrees(90), sprec.BasisXVec3()),
+	}
+	// Rotations that turn the Z axis, around which the circle shape is built,
+	// into the respective handle axis.
+	ringRotations := [3]sprec.Quat{
+		sprec.RotationQuat(sprec.Degrees(90), sprec.BasisYVec3()),
+		sprec.RotationQuat(sprec.Degrees(-90), sprec.BasisXVec3()),
+		sprec.IdentityQuat(),
+	}
+	directions := [3]sprec.Vec3{sprec.BasisXVec3(), sprec.BasisYVec3(), sprec.BasisZVec3()}
+
+	for axis := range 3 {
+		meshBuilder := graphics.NewShapeBuilder()
+		meshBuilder.Wireframe(materials[axis]).
+			Line(sprec.ZeroVec3(), sprec.Vec3Prod(directions[axis], 0.8))
+		meshBuilder.Solid(materials[axis]).
+			Cone(sprec.Vec3Prod(directions[axis], 0.9), axisRotations[axis], 0.06, 0.2, 12)
+		d.translateHandleMeshGeometries[axis] = d.gfxEngine.CreateMeshGeometry(meshBuilder.BuildGeometryInfo())
+		d.translateHandleMeshDefs[axis] = d.gfxEngine.CreateMeshDefinition(meshBuilder.BuildMeshDefinitionInfo(d.translateHandleMeshGeometries[axis]))
+
+		meshBuilder = graphics.NewShapeBuilder()
+		meshBuilder.Wireframe(materials[axis]).
+			Circle(sprec.ZeroVec3(), ringRotations[axis], 1.0, 48)
+		d.rotateHandleMeshGeometries[axis] = d.gfxEngine.CreateMeshGeometry(meshBuilder.BuildGeometryInfo())
+		d.rotateHandleMeshDefs[axis] = d.gfxEngine.CreateMeshDefinition(meshBuilder.BuildMeshDefinitionInfo(d.rotateHandleMeshGeometries[axis]))
+
+		meshBuilder = graphics.NewShapeBuilder()
+		meshBuilder.Wireframe(materials[axis]).
+			Line(sprec.ZeroVec3(), sprec.Vec3Prod(directions[axis], 0.9))
+		meshBuilder.Solid(materials[axis]).
+			Cuboid(sprec.Vec3Prod(directions[axis], 0.95), sprec.IdentityQuat(), sprec.NewVec3(0.1, 0.1, 0.1))
+		d.scaleHandleMeshGeometries[axis] = d.gfxEngine.CreateMeshGeometry(meshBuilder.BuildGeometryInfo())
+		d.scaleHandleMeshDefs[axis] = d.gfxEngine.CreateMeshDefinition(meshBuilder.BuildMeshDefinitionInfo(d.scaleHandleMeshGeometries[axis]))
+	}
+}
+
+func (d *CommonData) deleteHandleMeshes() {
+	for axis := range 3 {
+		defer d.translateHandleMeshGeometries[axis].Delete()
+		defer d.translateHandleMeshDefs[axis].Delete()
+		defer d.rotateHandleMeshGeometries[axis].Delete()
+		defer d.rotateHandleMeshDefs[axis].Delete()
+		defer d.scaleHandleMeshGeometries[axis].Delete()
+		defer d.scaleHandleMeshDefs[axis].Delete()
+	}
+}
+
 type ColorUniform struct {
 	Color sprec.Vec4
 }
@@ -0,0 +1,33 @@
+package viewport
+
+import (
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/graphics"
+)
+
+const sphereSegments = 32
+
+// SelectionColor is the color that is used to outline selected nodes.
+var SelectionColor = dprec.NewVec3(1.0, 0.6, 0.0)
+
+// DrawSphere outlines a sphere by drawing a circle around each of the
+// three axes.
+func DrawSphere(debug *graphics.Debug, center dprec.Vec3, radius float64, color dprec.Vec3) {
+	axes := [3][2]dprec.Vec3{
+		{dprec.BasisXVec3(), dprec.BasisYVec3()},
+		{dprec.BasisYVec3(), dprec.BasisZVec3()},
+		{dprec.BasisZVec3(), dprec.BasisXVec3()},
+	}
+	for _, axis := range axes {
+		point := func(index int) dprec.Vec3 {
+			angle := dprec.Degrees(360.0 * float64(index) / sphereSegments)
+			return dprec.Vec3Sum(center, dprec.Vec3Sum(
+				dprec.Vec3Prod(axis[0], radius*dprec.Cos(angle)),
+				dprec.Vec3Prod(axis[1], radius*dprec.Sin(angle)),
+			))
+		}
+		for i := range sphereSegments {
+			debug.Line(point(i), point(i+1), color)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package viewport
+
+import (
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/graphics"
+)
+
+const (
+	DefaultExposure = 1.0
+
+	// DefaultFoV is the vertical field of view of the camera, in degrees.
+	DefaultFoV = 60.0
+)
+
+// NewEnvironment creates the camera, grid, lights and sky that are used
+// to preview a model in the specified scene.
+func NewEnvironment(gfxScene *graphics.Scene, commonData *CommonData) *Environment {
+	camera := gfxScene.CreateCamera()
+	camera.SetExposure(DefaultExposure)
+	camera.SetAutoExposure(false)
+	camera.SetFoV(sprec.Degrees(DefaultFoV))
+	camera.SetFoVMode(graphics.FoVModeHorizontalPlus)
+	camera.SetCascadeDistances([]float32{16.0, 64.0, 256.0, 1024.0})
+
+	grid := gfxScene.CreateMesh(graphics.MeshInfo{
+		Definition: commonData.GridMeshDefinition(),
+	})
+	grid.SetMatrix(dprec.IdentityMat4())
+
+	ambientLight := gfxScene.CreateAmbientLight(graphics.AmbientLightInfo{
+		Position:          dprec.ZeroVec3(),
+		InnerRadius:       20000.0,
+		OuterRadius:       20000.0,
+		ReflectionTexture: commonData.SkyTexture(),
+		RefractionTexture: commonData.SkyTexture(),
+		CastShadow:        false,
+	})
+
+	directionalLight := gfxScene.CreateDirectionalLight(graphics.DirectionalLightInfo{
+		Position:   dprec.ZeroVec3(),
+		Rotation:   dprec.RotationQuat(dprec.Degrees(-45), dprec.BasisXVec3()),
+		EmitColor:  dprec.NewVec3(1.5, 1.5, 1.5),
+		CastShadow: true,
+	})
+
+	sky := gfxScene.CreateSky(graphics.SkyInfo{
+		Definition: commonData.SkyDefinition(),
+	})
+
+	return &Environment{
+		camera:           camera,
+		grid:             grid,
+		ambientLight:     ambientLight,
+		directionalLight: directionalLight,
+		sky:              sky,
+	}
+}
+
+// Environment holds the default entities that surround a previewed model.
+type Environment struct {
+	camera           *graphics.Camera
+	grid             *graphics.Mesh
+	ambientLight     *graphics.AmbientLight
+	directionalLight *graphics.DirectionalLight
+	sky              *graphics.Sky
+}
+
+func (e *Environment) Camera() *graphics.Camera {
+	return e.camera
+}
+
+func (e *Environment) SetAutoExposure(enabled bool) {
+	if enabled {
+		e.camera.SetAutoExposure(true)
+	} else {
+		e.camera.SetExposure(DefaultExposure)
+		e.camera.SetAutoExposure(false)
+	}
+}
+
+func (e *Environment) SetExposure(exposure float32) {
+	e.camera.SetAutoExposure(false)
+	e.camera.SetExposure(exposure)
+}
+
+func (e *Environment) SetShowGrid(show bool) {
+	e.grid.SetActive(show)
+}
+
+func (e *Environment) SetShowAmbientLight(show bool) {
+	e.ambientLight.SetActive(show)
+}
+
+func (e *Environment) SetShowDirectionalLight(show bool) {
+	e.directionalLight.SetActive(show)
+}
+
+func (e *Environment) SetShowSky(show bool) {
+	e.sky.SetActive(show)
+}
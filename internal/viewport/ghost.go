@@ -0,0 +1,201 @@
+package viewport
+
+import (
+	"github.com/mokiat/gblob"
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/graphics"
+	"github.com/mokiat/lacking/render"
+)
+
+// NewGhost creates translucent meshes for the geometry of the specified
+// model content, which are used to overlay a different version of a model.
+//
+// NOTE: The graphics engine does not allow the materials of a loaded model
+// to be overridden, so the meshes are built from the content instead.
+// Skinned meshes are shown in their bind pose.
+func NewGhost(scene *graphics.Scene, commonData *CommonData, content *asset.Model) *Ghost {
+	gfxEngine := commonData.gfxEngine
+	result := &Ghost{
+		content: content,
+	}
+	absoluteMatrices := contentAbsoluteMatrices(content)
+	definitions := make(map[int]*graphics.MeshDefinition)
+	for _, mesh := range content.Meshes {
+		if int(mesh.NodeIndex) >= len(content.Nodes) || int(mesh.MeshDefinitionIndex) >= len(content.MeshDefinitions) {
+			continue
+		}
+		geometryIndex := int(content.MeshDefinitions[mesh.MeshDefinitionIndex].GeometryIndex)
+		if geometryIndex >= len(content.Geometries) {
+			continue
+		}
+		definition, ok := definitions[geometryIndex]
+		if !ok {
+			if geometryInfo, ok := ghostGeometryInfo(content.Geometries[geometryIndex]); ok {
+				meshGeometry := gfxEngine.CreateMeshGeometry(geometryInfo)
+				materials := make([]*graphics.Material, len(geometryInfo.Fragments))
+				for i := range materials {
+					materials[i] = commonData.ghostMaterial
+				}
+				definition = gfxEngine.CreateMeshDefinition(graphics.MeshDefinitionInfo{
+					Geometry:  meshGeometry,
+					Materials: materials,
+				})
+				result.geometries = append(result.geometries, meshGeometry)
+				result.definitions = append(result.definitions, definition)
+			}
+			definitions[geometryIndex] = definition
+		}
+		if definition == nil {
+			continue
+		}
+		ghostMesh := scene.CreateMesh(graphics.MeshInfo{
+			Definition: definition,
+		})
+		ghostMesh.SetMatrix(absoluteMatrices[mesh.NodeIndex])
+		result.meshes = append(result.meshes, ghostMesh)
+	}
+	return result
+}
+
+// Ghost is a translucent version of a model.
+type Ghost struct {
+	content     *asset.Model
+	geometries  []*graphics.MeshGeometry
+	definitions []*graphics.MeshDefinition
+	meshes      []*graphics.Mesh
+}
+
+// Content returns the model content that the ghost was built from.
+func (g *Ghost) Content() *asset.Model {
+	return g.content
+}
+
+// SetActive controls whether the ghost is rendered.
+func (g *Ghost) SetActive(active bool) {
+	for _, mesh := range g.meshes {
+		mesh.SetActive(active)
+	}
+}
+
+// Delete releases the meshes of the ghost.
+func (g *Ghost) Delete() {
+	for _, mesh := range g.meshes {
+		mesh.Delete()
+	}
+	for _, definition := range g.definitions {
+		definition.Delete()
+	}
+	for _, geometry := range g.geometries {
+		geometry.Delete()
+	}
+}
+
+// contentAbsoluteMatrices returns the world matrices of the nodes of the
+// content in their initial placement.
+func contentAbsoluteMatrices(content *asset.Model) []dprec.Mat4 {
+	result := make([]dprec.Mat4, len(content.Nodes))
+	resolved := make([]bool, len(content.Nodes))
+	var resolve func(index int, depth int) dprec.Mat4
+	resolve = func(index int, depth int) dprec.Mat4 {
+		if resolved[index] {
+			return result[index]
+		}
+		node := content.Nodes[index]
+		matrix := dprec.TRSMat4(node.Translation, node.Rotation, node.Scale)
+		// NOTE: The depth guards against malformed content with cycles.
+		if parentIndex := int(node.ParentIndex); parentIndex >= 0 && parentIndex < len(content.Nodes) && depth < len(content.Nodes) {
+			matrix = dprec.Mat4Prod(resolve(parentIndex, depth+1), matrix)
+		}
+		result[index] = matrix
+		resolved[index] = true
+		return matrix
+	}
+	for i := range content.Nodes {
+		resolve(i, 0)
+	}
+	return result
+}
+
+// ghostGeometryInfo converts the triangles of the geometry into a mesh
+// geometry that only has coordinates. Fragments that are not made of
+// triangles are skipped.
+func ghostGeometryInfo(geometry asset.Geometry) (graphics.MeshGeometryInfo, bool) {
+	count := vertexCount(geometry)
+	meshBuilder := graphics.NewMeshGeometryBuilder(graphics.MeshGeometryBuilderWithCoords())
+	for i := range count {
+		coord, ok := readVertexVec3(geometry, geometry.VertexLayout.Coord, i)
+		if !ok {
+			return graphics.MeshGeometryInfo{}, false
+		}
+		meshBuilder.Vertex().CoordVec3(toSprecVec3(coord))
+	}
+
+	fragmentCount := 0
+	for _, fragment := range geometry.Fragments {
+		indices, ok := readFragmentIndices(geometry.IndexBuffer, fragment)
+		if !ok {
+			continue
+		}
+		switch fragment.Topology {
+		case asset.TopologyTriangleList:
+			indices = indices[:len(indices)/3*3]
+		case asset.TopologyTriangleStrip:
+			indices = triangleStripToList(indices)
+		default:
+			continue
+		}
+		indexOffset := meshBuilder.IndexOffset()
+		for _, index := range indices {
+			if int(index) >= count {
+				return graphics.MeshGeometryInfo{}, false
+			}
+			meshBuilder.Index(index)
+		}
+		if len(indices) > 0 {
+			meshBuilder.Fragment(render.TopologyTriangleList, indexOffset, uint32(len(indices)))
+			fragmentCount++
+		}
+	}
+	if fragmentCount == 0 {
+		return graphics.MeshGeometryInfo{}, false
+	}
+	return meshBuilder.BuildInfo(), true
+}
+
+func readFragmentIndices(buffer asset.IndexBuffer, fragment asset.Fragment) ([]uint32, bool) {
+	indexSize := 2
+	if buffer.IndexLayout == asset.IndexLayoutUint32 {
+		indexSize = 4
+	}
+	start := int(fragment.IndexByteOffset)
+	end := start + int(fragment.IndexCount)*indexSize
+	if end > len(buffer.Data) {
+		return nil, false
+	}
+	data := gblob.LittleEndianBlock(buffer.Data)
+	result := make([]uint32, fragment.IndexCount)
+	for i := range result {
+		offset := start + i*indexSize
+		if indexSize == 4 {
+			result[i] = data.Uint32(offset)
+		} else {
+			result[i] = uint32(data.Uint16(offset))
+		}
+	}
+	return result, true
+}
+
+// triangleStripToList returns the triangles of the strip as a list, with
+// every other triangle flipped in order to keep the winding consistent.
+func triangleStripToList(indices []uint32) []uint32 {
+	var result []uint32
+	for i := 2; i < len(indices); i++ {
+		a, b, c := indices[i-2], indices[i-1], indices[i]
+		if i%2 == 1 {
+			a, b = b, a
+		}
+		result = append(result, a, b, c)
+	}
+	return result
+}
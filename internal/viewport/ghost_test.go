@@ -0,0 +1,70 @@
+package viewport
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mokiat/gblob"
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+func TestTriangleStripToList(t *testing.T) {
+	result := triangleStripToList([]uint32{0, 1, 2, 3, 4})
+	expected := []uint32{0, 1, 2, 2, 1, 3, 2, 3, 4}
+	if !slices.Equal(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestReadFragmentIndices(t *testing.T) {
+	data := make(gblob.LittleEndianBlock, 5*2)
+	for i := range 5 {
+		data.SetUint16(i*2, uint16(10+i))
+	}
+	buffer := asset.IndexBuffer{
+		IndexLayout: asset.IndexLayoutUint16,
+		Data:        data,
+	}
+
+	indices, ok := readFragmentIndices(buffer, asset.Fragment{
+		IndexByteOffset: 4,
+		IndexCount:      3,
+	})
+	if !ok {
+		t.Fatalf("expected indices to be read")
+	}
+	if expected := []uint32{12, 13, 14}; !slices.Equal(indices, expected) {
+		t.Fatalf("expected %v, got %v", expected, indices)
+	}
+
+	if _, ok := readFragmentIndices(buffer, asset.Fragment{
+		IndexByteOffset: 4,
+		IndexCount:      4,
+	}); ok {
+		t.Fatalf("expected out of range fragment to be rejected")
+	}
+}
+
+func TestContentAbsoluteMatrices(t *testing.T) {
+	content := &asset.Model{
+		Nodes: []asset.Node{
+			{
+				ParentIndex: 1,
+				Translation: dprec.NewVec3(0.0, 1.0, 0.0),
+				Rotation:    dprec.IdentityQuat(),
+				Scale:       dprec.NewVec3(1.0, 1.0, 1.0),
+			},
+			{
+				ParentIndex: asset.UnspecifiedNodeIndex,
+				Translation: dprec.NewVec3(2.0, 0.0, 0.0),
+				Rotation:    dprec.IdentityQuat(),
+				Scale:       dprec.NewVec3(1.0, 1.0, 1.0),
+			},
+		},
+	}
+	matrices := contentAbsoluteMatrices(content)
+	if translation := matrices[0].Translation(); translation != dprec.NewVec3(2.0, 1.0, 0.0) {
+		t.Fatalf("expected child to follow its parent, got %v", translation)
+	}
+}
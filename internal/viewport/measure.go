@@ -0,0 +1,199 @@
+package viewport
+
+import (
+	"math"
+
+	"github.com/mokiat/gblob"
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/graphics"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+// MeasureMarkerSize is the size of the crosses that mark ruler points.
+const MeasureMarkerSize = 0.02
+
+var (
+	// MeasureColor is the color of the ruler line.
+	MeasureColor = dprec.NewVec3(1.0, 0.0, 1.0)
+
+	// DimensionColors are the colors of the model dimensions along the
+	// X, Y and Z axes.
+	DimensionColors = [3]dprec.Vec3{
+		dprec.NewVec3(1.0, 0.2, 0.2),
+		dprec.NewVec3(0.2, 1.0, 0.2),
+		dprec.NewVec3(0.2, 0.4, 1.0),
+	}
+)
+
+// DrawMeasurement draws a ruler between the specified points. When only
+// one point is specified, only its marker is drawn.
+func DrawMeasurement(debug *graphics.Debug, points []dprec.Vec3, color dprec.Vec3) {
+	for _, point := range points {
+		for _, axis := range [...]dprec.Vec3{dprec.BasisXVec3(), dprec.BasisYVec3(), dprec.BasisZVec3()} {
+			delta := dprec.Vec3Prod(axis, MeasureMarkerSize)
+			debug.Line(dprec.Vec3Diff(point, delta), dprec.Vec3Sum(point, delta), color)
+		}
+	}
+	if len(points) >= 2 {
+		debug.Line(points[0], points[1], color)
+	}
+}
+
+// DimensionEdges returns the three edges of the box with the specified
+// bounds that start from its minimum corner, one along each axis.
+func DimensionEdges(minCoord, maxCoord dprec.Vec3) [3][2]dprec.Vec3 {
+	return [3][2]dprec.Vec3{
+		{minCoord, dprec.NewVec3(maxCoord.X, minCoord.Y, minCoord.Z)},
+		{minCoord, dprec.NewVec3(minCoord.X, maxCoord.Y, minCoord.Z)},
+		{minCoord, dprec.NewVec3(minCoord.X, minCoord.Y, maxCoord.Z)},
+	}
+}
+
+// DrawDimensions draws the edges of the box with the specified bounds
+// along which the size of the model is measured.
+func DrawDimensions(debug *graphics.Debug, minCoord, maxCoord dprec.Vec3) {
+	drawBoxCorners(debug, boxCorners(dprec.IdentityMat4(), minCoord, maxCoord), BoundsColor)
+	for i, edge := range DimensionEdges(minCoord, maxCoord) {
+		debug.Line(edge[0], edge[1], DimensionColors[i])
+	}
+}
+
+// surfaceMesh holds the triangles of a mesh in the local space of the
+// node that the mesh is attached to.
+type surfaceMesh struct {
+	node      *hierarchy.Node
+	triangles [][3]dprec.Vec3
+}
+
+// surfaceMeshes extracts the triangles of all meshes of the content, so
+// that points on the surface of the model can be picked.
+//
+// NOTE: Skinned meshes are picked in their bind pose, since the armature
+// is applied on the GPU.
+func surfaceMeshes(nodes []*hierarchy.Node, content *asset.Model) []surfaceMesh {
+	var result []surfaceMesh
+	for _, mesh := range content.Meshes {
+		if int(mesh.MeshDefinitionIndex) >= len(content.MeshDefinitions) {
+			continue
+		}
+		node, ok := contentNode(nodes, mesh.NodeIndex)
+		if !ok {
+			continue
+		}
+		geometryIndex := int(content.MeshDefinitions[mesh.MeshDefinitionIndex].GeometryIndex)
+		if geometryIndex >= len(content.Geometries) {
+			continue
+		}
+		if triangles := geometryTriangles(content.Geometries[geometryIndex]); len(triangles) > 0 {
+			result = append(result, surfaceMesh{
+				node:      node,
+				triangles: triangles,
+			})
+		}
+	}
+	return result
+}
+
+func geometryTriangles(geometry asset.Geometry) [][3]dprec.Vec3 {
+	var result [][3]dprec.Vec3
+	for _, fragment := range geometry.Fragments {
+		if fragment.Topology != asset.TopologyTriangleList {
+			continue
+		}
+		for i := 0; i+2 < int(fragment.IndexCount); i += 3 {
+			var (
+				triangle [3]dprec.Vec3
+				valid    = true
+			)
+			for j := range triangle {
+				index, ok := readIndex(geometry.IndexBuffer, fragment, i+j)
+				if !ok {
+					valid = false
+					break
+				}
+				if triangle[j], ok = readVertexVec3(geometry, geometry.VertexLayout.Coord, index); !ok {
+					valid = false
+					break
+				}
+			}
+			if valid {
+				result = append(result, triangle)
+			}
+		}
+	}
+	return result
+}
+
+func readIndex(buffer asset.IndexBuffer, fragment asset.Fragment, index int) (int, bool) {
+	data := gblob.LittleEndianBlock(buffer.Data)
+	switch buffer.IndexLayout {
+	case asset.IndexLayoutUint16:
+		offset := int(fragment.IndexByteOffset) + index*2
+		if offset+2 > len(data) {
+			return 0, false
+		}
+		return int(data.Uint16(offset)), true
+	case asset.IndexLayoutUint32:
+		offset := int(fragment.IndexByteOffset) + index*4
+		if offset+4 > len(data) {
+			return 0, false
+		}
+		return int(data.Uint32(offset)), true
+	default:
+		return 0, false
+	}
+}
+
+// pickSurface returns the closest point at which the ray hits any of the
+// triangles of the meshes.
+func pickSurface(ray Ray, meshes []surfaceMesh) (dprec.Vec3, bool) {
+	bestDistance := math.Inf(1)
+	for _, mesh := range meshes {
+		matrix := mesh.node.AbsoluteMatrix()
+		for _, triangle := range mesh.triangles {
+			distance, ok := rayTriangleDistance(ray,
+				dprec.Mat4Vec3Transformation(matrix, triangle[0]),
+				dprec.Mat4Vec3Transformation(matrix, triangle[1]),
+				dprec.Mat4Vec3Transformation(matrix, triangle[2]),
+			)
+			if ok && distance < bestDistance {
+				bestDistance = distance
+			}
+		}
+	}
+	if math.IsInf(bestDistance, 1) {
+		return dprec.Vec3{}, false
+	}
+	return dprec.Vec3Sum(ray.Origin, dprec.Vec3Prod(ray.Direction, bestDistance)), true
+}
+
+// rayTriangleDistance uses the Möller–Trumbore algorithm. Both sides of
+// the triangle are considered, since back faces can be visible in the
+// preview.
+func rayTriangleDistance(ray Ray, a, b, c dprec.Vec3) (float64, bool) {
+	const epsilon = 1e-9
+	edgeAB := dprec.Vec3Diff(b, a)
+	edgeAC := dprec.Vec3Diff(c, a)
+	p := dprec.Vec3Cross(ray.Direction, edgeAC)
+	determinant := dprec.Vec3Dot(edgeAB, p)
+	if dprec.Abs(determinant) < epsilon {
+		return 0.0, false
+	}
+	inverseDeterminant := 1.0 / determinant
+	offset := dprec.Vec3Diff(ray.Origin, a)
+	u := dprec.Vec3Dot(offset, p) * inverseDeterminant
+	if u < 0.0 || u > 1.0 {
+		return 0.0, false
+	}
+	q := dprec.Vec3Cross(offset, edgeAB)
+	v := dprec.Vec3Dot(ray.Direction, q) * inverseDeterminant
+	if v < 0.0 || u+v > 1.0 {
+		return 0.0, false
+	}
+	distance := dprec.Vec3Dot(edgeAC, q) * inverseDeterminant
+	if distance < 0.0 {
+		return 0.0, false
+	}
+	return distance, true
+}
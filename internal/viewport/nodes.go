@@ -0,0 +1,50 @@
+package viewport
+
+import (
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+// ContentNodes returns the nodes below the specified root, which should
+// have been instantiated from the specified model content, indexed the same
+// way as the nodes of the content. Nodes that could not be matched are nil.
+//
+// NOTE: The engine appends the nodes of a model to their parents in the
+// order of the content, so the children of each node are in the order of
+// their content indices. This is exact, unlike matching nodes by name,
+// which can be empty or repeated.
+func ContentNodes(root *hierarchy.Node, content *asset.Model) []*hierarchy.Node {
+	result := make([]*hierarchy.Node, len(content.Nodes))
+	childIndices := make(map[int32][]int)
+	for i, node := range content.Nodes {
+		parentIndex := node.ParentIndex
+		if parentIndex < 0 || int(parentIndex) >= len(content.Nodes) {
+			parentIndex = asset.UnspecifiedNodeIndex
+		}
+		childIndices[parentIndex] = append(childIndices[parentIndex], i)
+	}
+
+	var visit func(node *hierarchy.Node, index int32)
+	visit = func(node *hierarchy.Node, index int32) {
+		child := node.FirstChild()
+		for _, childIndex := range childIndices[index] {
+			if child == nil {
+				return
+			}
+			result[childIndex] = child
+			visit(child, int32(childIndex))
+			child = child.RightSibling()
+		}
+	}
+	visit(root, asset.UnspecifiedNodeIndex)
+	return result
+}
+
+// contentNode returns the node with the specified content index, if it
+// was matched.
+func contentNode(nodes []*hierarchy.Node, index uint32) (*hierarchy.Node, bool) {
+	if int(index) >= len(nodes) || nodes[index] == nil {
+		return nil, false
+	}
+	return nodes[index], true
+}
@@ -0,0 +1,367 @@
+package viewport
+
+import (
+	"github.com/mokiat/gblob"
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/graphics"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+// VectorLength is the length of the lines that show normals and tangents.
+const VectorLength = 0.05
+
+var (
+	// BoundsColor is the color of the bounding box of the whole model.
+	BoundsColor = dprec.NewVec3(1.0, 1.0, 1.0)
+
+	// NodeBoundsColor is the color of the bounding boxes of single nodes.
+	NodeBoundsColor = dprec.NewVec3(1.0, 1.0, 0.0)
+
+	// BoneColor is the color of the bones of armatures.
+	BoneColor = dprec.NewVec3(0.0, 0.8, 1.0)
+)
+
+// OverlayFilter determines which overlays are displayed.
+type OverlayFilter struct {
+	Bounds     bool
+	NodeBounds bool
+	Skeleton   bool
+	Normals    bool
+	Tangents   bool
+}
+
+// NewOverlays prepares overlays for a model with the specified content.
+func NewOverlays(scene *graphics.Scene, commonData *CommonData, nodes []*hierarchy.Node, content *asset.Model) *Overlays {
+	result := &Overlays{
+		scene:      scene,
+		commonData: commonData,
+		content:    content,
+	}
+	for _, mesh := range content.Meshes {
+		if int(mesh.MeshDefinitionIndex) >= len(content.MeshDefinitions) {
+			continue
+		}
+		node, ok := contentNode(nodes, mesh.NodeIndex)
+		if !ok {
+			continue
+		}
+		geometryIndex := int(content.MeshDefinitions[mesh.MeshDefinitionIndex].GeometryIndex)
+		if geometryIndex >= len(content.Geometries) {
+			continue
+		}
+		minCoord, maxCoord, ok := geometryBounds(content.Geometries[geometryIndex])
+		if !ok {
+			continue
+		}
+		result.meshNodes = append(result.meshNodes, overlayMeshNode{
+			node:          node,
+			geometryIndex: geometryIndex,
+			minCoord:      minCoord,
+			maxCoord:      maxCoord,
+		})
+	}
+	for _, armature := range content.Armatures {
+		for _, joint := range armature.Joints {
+			node, ok := contentNode(nodes, joint.NodeIndex)
+			if !ok {
+				continue
+			}
+			bone := overlayBone{
+				node: node,
+			}
+			if parentIndex := content.Nodes[joint.NodeIndex].ParentIndex; parentIndex >= 0 {
+				bone.parent, _ = contentNode(nodes, uint32(parentIndex))
+			}
+			result.bones = append(result.bones, bone)
+		}
+	}
+	return result
+}
+
+// Overlays draws debug information about the geometry and the armatures
+// of a model on top of it.
+type Overlays struct {
+	scene      *graphics.Scene
+	commonData *CommonData
+	content    *asset.Model
+
+	filter    OverlayFilter
+	meshNodes []overlayMeshNode
+	bones     []overlayBone
+
+	// The vector meshes are only built once they are first displayed,
+	// since they can be expensive for large models.
+	normalMeshes  *overlayVectorMeshes
+	tangentMeshes *overlayVectorMeshes
+}
+
+type overlayMeshNode struct {
+	node          *hierarchy.Node
+	geometryIndex int
+	minCoord      dprec.Vec3
+	maxCoord      dprec.Vec3
+}
+
+type overlayBone struct {
+	node   *hierarchy.Node
+	parent *hierarchy.Node
+}
+
+type overlayVectorMeshes struct {
+	geometries  []*graphics.MeshGeometry
+	definitions []*graphics.MeshDefinition
+	meshes      []*graphics.Mesh
+	meshNodes   []*hierarchy.Node
+}
+
+// SetFilter changes the overlays that are displayed.
+func (o *Overlays) SetFilter(filter OverlayFilter) {
+	o.filter = filter
+	if filter.Normals && o.normalMeshes == nil {
+		o.normalMeshes = o.createVectorMeshes(func(layout asset.VertexLayout) asset.VertexAttribute {
+			return layout.Normal
+		}, o.commonData.blueMaterial)
+	}
+	if filter.Tangents && o.tangentMeshes == nil {
+		o.tangentMeshes = o.createVectorMeshes(func(layout asset.VertexLayout) asset.VertexAttribute {
+			return layout.Tangent
+		}, o.commonData.redMaterial)
+	}
+	if o.normalMeshes != nil {
+		o.normalMeshes.setActive(filter.Normals)
+	}
+	if o.tangentMeshes != nil {
+		o.tangentMeshes.setActive(filter.Tangents)
+	}
+}
+
+// Update moves the overlay meshes to the nodes that they belong to.
+func (o *Overlays) Update() {
+	if o.normalMeshes != nil && o.filter.Normals {
+		o.normalMeshes.update()
+	}
+	if o.tangentMeshes != nil && o.filter.Tangents {
+		o.tangentMeshes.update()
+	}
+}
+
+// Draw outlines the bounding boxes and the bones of the model.
+func (o *Overlays) Draw(debug *graphics.Debug) {
+	if o.filter.Bounds {
+		o.drawBounds(debug)
+	}
+	if o.filter.NodeBounds {
+		for _, meshNode := range o.meshNodes {
+			corners := boxCorners(meshNode.node.AbsoluteMatrix(), meshNode.minCoord, meshNode.maxCoord)
+			drawBoxCorners(debug, corners, NodeBoundsColor)
+		}
+	}
+	if o.filter.Skeleton {
+		for _, bone := range o.bones {
+			position := bone.node.AbsoluteMatrix().Translation()
+			DrawSphere(debug, position, VectorLength/2.0, BoneColor)
+			if bone.parent != nil {
+				debug.Line(bone.parent.AbsoluteMatrix().Translation(), position, BoneColor)
+			}
+		}
+	}
+}
+
+// Delete releases the meshes of the overlays.
+func (o *Overlays) Delete() {
+	if o.normalMeshes != nil {
+		o.normalMeshes.delete()
+		o.normalMeshes = nil
+	}
+	if o.tangentMeshes != nil {
+		o.tangentMeshes.delete()
+		o.tangentMeshes = nil
+	}
+}
+
+// Bounds returns the world-space axis-aligned box that contains all
+// meshes of the model.
+func (o *Overlays) Bounds() (dprec.Vec3, dprec.Vec3, bool) {
+	var (
+		minCoord    dprec.Vec3
+		maxCoord    dprec.Vec3
+		initialized bool
+	)
+	for _, meshNode := range o.meshNodes {
+		for _, corner := range boxCorners(meshNode.node.AbsoluteMatrix(), meshNode.minCoord, meshNode.maxCoord) {
+			if !initialized {
+				minCoord, maxCoord = corner, corner
+				initialized = true
+			}
+			minCoord = dprec.NewVec3(min(minCoord.X, corner.X), min(minCoord.Y, corner.Y), min(minCoord.Z, corner.Z))
+			maxCoord = dprec.NewVec3(max(maxCoord.X, corner.X), max(maxCoord.Y, corner.Y), max(maxCoord.Z, corner.Z))
+		}
+	}
+	return minCoord, maxCoord, initialized
+}
+
+func (o *Overlays) drawBounds(debug *graphics.Debug) {
+	if minCoord, maxCoord, ok := o.Bounds(); ok {
+		drawBoxCorners(debug, boxCorners(dprec.IdentityMat4(), minCoord, maxCoord), BoundsColor)
+	}
+}
+
+// createVectorMeshes builds a line for the specified attribute of each
+// vertex of each mesh of the model.
+//
+// NOTE: Skinned meshes are shown in their bind pose, since the lines are
+// not affected by the armature.
+func (o *Overlays) createVectorMeshes(attributeFunc func(layout asset.VertexLayout) asset.VertexAttribute, material *graphics.Material) *overlayVectorMeshes {
+	gfxEngine := o.commonData.gfxEngine
+
+	definitions := make(map[int]*graphics.MeshDefinition)
+	result := &overlayVectorMeshes{}
+	for _, meshNode := range o.meshNodes {
+		definition, ok := definitions[meshNode.geometryIndex]
+		if !ok {
+			geometry := o.content.Geometries[meshNode.geometryIndex]
+			meshBuilder := graphics.NewShapeBuilder()
+			wireframe := meshBuilder.Wireframe(material)
+			lineCount := 0
+			for i := range vertexCount(geometry) {
+				coord, ok := readVertexVec3(geometry, geometry.VertexLayout.Coord, i)
+				if !ok {
+					break
+				}
+				vector, ok := readVertexVec3(geometry, attributeFunc(geometry.VertexLayout), i)
+				if !ok {
+					break
+				}
+				end := dprec.Vec3Sum(coord, dprec.Vec3Prod(dprec.UnitVec3(vector), VectorLength))
+				wireframe.Line(toSprecVec3(coord), toSprecVec3(end))
+				lineCount++
+			}
+			if lineCount > 0 {
+				meshGeometry := gfxEngine.CreateMeshGeometry(meshBuilder.BuildGeometryInfo())
+				definition = gfxEngine.CreateMeshDefinition(meshBuilder.BuildMeshDefinitionInfo(meshGeometry))
+				result.geometries = append(result.geometries, meshGeometry)
+				result.definitions = append(result.definitions, definition)
+			}
+			definitions[meshNode.geometryIndex] = definition
+		}
+		if definition == nil {
+			continue
+		}
+		result.meshes = append(result.meshes, o.scene.CreateMesh(graphics.MeshInfo{
+			Definition: definition,
+		}))
+		result.meshNodes = append(result.meshNodes, meshNode.node)
+	}
+	result.update()
+	return result
+}
+
+func (m *overlayVectorMeshes) setActive(active bool) {
+	for _, mesh := range m.meshes {
+		mesh.SetActive(active)
+	}
+}
+
+func (m *overlayVectorMeshes) update() {
+	for i, mesh := range m.meshes {
+		mesh.SetMatrix(m.meshNodes[i].AbsoluteMatrix())
+	}
+}
+
+func (m *overlayVectorMeshes) delete() {
+	for _, mesh := range m.meshes {
+		mesh.Delete()
+	}
+	for _, definition := range m.definitions {
+		definition.Delete()
+	}
+	for _, geometry := range m.geometries {
+		geometry.Delete()
+	}
+}
+
+// geometryBounds returns the minimum and maximum coordinates of the
+// vertices of the geometry.
+func geometryBounds(geometry asset.Geometry) (dprec.Vec3, dprec.Vec3, bool) {
+	var minCoord, maxCoord dprec.Vec3
+	count := vertexCount(geometry)
+	for i := range count {
+		coord, ok := readVertexVec3(geometry, geometry.VertexLayout.Coord, i)
+		if !ok {
+			return dprec.Vec3{}, dprec.Vec3{}, false
+		}
+		if i == 0 {
+			minCoord, maxCoord = coord, coord
+			continue
+		}
+		minCoord = dprec.NewVec3(min(minCoord.X, coord.X), min(minCoord.Y, coord.Y), min(minCoord.Z, coord.Z))
+		maxCoord = dprec.NewVec3(max(maxCoord.X, coord.X), max(maxCoord.Y, coord.Y), max(maxCoord.Z, coord.Z))
+	}
+	return minCoord, maxCoord, count > 0
+}
+
+func vertexCount(geometry asset.Geometry) int {
+	attribute := geometry.VertexLayout.Coord
+	if attribute.BufferIndex < 0 || int(attribute.BufferIndex) >= len(geometry.VertexBuffers) {
+		return 0
+	}
+	buffer := geometry.VertexBuffers[attribute.BufferIndex]
+	if buffer.Stride == 0 {
+		return 0
+	}
+	return len(buffer.Data) / int(buffer.Stride)
+}
+
+// readVertexVec3 returns the first three components of the attribute of
+// the vertex at the specified index.
+func readVertexVec3(geometry asset.Geometry, attribute asset.VertexAttribute, index int) (dprec.Vec3, bool) {
+	if attribute.BufferIndex < 0 || int(attribute.BufferIndex) >= len(geometry.VertexBuffers) {
+		return dprec.Vec3{}, false
+	}
+	buffer := geometry.VertexBuffers[attribute.BufferIndex]
+	data := gblob.LittleEndianBlock(buffer.Data)
+	offset := index*int(buffer.Stride) + int(attribute.ByteOffset)
+
+	var (
+		componentSize int
+		read          func(offset int) float64
+	)
+	switch attribute.Format {
+	case asset.VertexAttributeFormatRGB32F, asset.VertexAttributeFormatRGBA32F:
+		componentSize = 4
+		read = func(offset int) float64 {
+			return float64(data.Float32(offset))
+		}
+	case asset.VertexAttributeFormatRGB16F, asset.VertexAttributeFormatRGBA16F:
+		componentSize = 2
+		read = func(offset int) float64 {
+			return float64(halfToFloat32(data.Uint16(offset)))
+		}
+	case asset.VertexAttributeFormatRGB16SN, asset.VertexAttributeFormatRGBA16SN:
+		componentSize = 2
+		read = func(offset int) float64 {
+			return max(float64(int16(data.Uint16(offset)))/32767.0, -1.0)
+		}
+	case asset.VertexAttributeFormatRGB8SN, asset.VertexAttributeFormatRGBA8SN:
+		componentSize = 1
+		read = func(offset int) float64 {
+			return max(float64(int8(data.Uint8(offset)))/127.0, -1.0)
+		}
+	default:
+		return dprec.Vec3{}, false
+	}
+	if offset+3*componentSize > len(data) {
+		return dprec.Vec3{}, false
+	}
+	return dprec.NewVec3(
+		read(offset),
+		read(offset+componentSize),
+		read(offset+2*componentSize),
+	), true
+}
+
+func toSprecVec3(vector dprec.Vec3) sprec.Vec3 {
+	return sprec.NewVec3(float32(vector.X), float32(vector.Y), float32(vector.Z))
+}
@@ -0,0 +1,138 @@
+package viewport
+
+import (
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/graphics"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+// GroundSize is the size of the floor that bodies land on when the
+// physics simulation is running.
+const GroundSize = 1000.0
+
+// PhysicsColor is the color that is used to outline collision shapes.
+var PhysicsColor = dprec.NewVec3(0.0, 1.0, 0.4)
+
+// PhysicsShapeFilter determines which kinds of collision shapes are drawn.
+type PhysicsShapeFilter struct {
+	Spheres bool
+	Boxes   bool
+	Meshes  bool
+}
+
+// IsEmpty returns whether no collision shapes pass the filter.
+func (f PhysicsShapeFilter) IsEmpty() bool {
+	return !f.Spheres && !f.Boxes && !f.Meshes
+}
+
+// BodyShapes holds the collision shapes of a body that is attached to
+// a node.
+type BodyShapes struct {
+	Node       *hierarchy.Node
+	Definition asset.BodyDefinition
+}
+
+// PhysicsBodyShapes returns the collision shapes of all bodies in the
+// content, attached to the specified nodes, as returned by ContentNodes.
+func PhysicsBodyShapes(nodes []*hierarchy.Node, content *asset.Model) []BodyShapes {
+	var result []BodyShapes
+	for _, body := range content.Bodies {
+		if int(body.BodyDefinitionIndex) >= len(content.BodyDefinitions) {
+			continue
+		}
+		node, ok := contentNode(nodes, body.NodeIndex)
+		if !ok {
+			continue
+		}
+		result = append(result, BodyShapes{
+			Node:       node,
+			Definition: content.BodyDefinitions[body.BodyDefinitionIndex],
+		})
+	}
+	return result
+}
+
+// DrawBodyShapes outlines the collision shapes of the specified bodies.
+func DrawBodyShapes(debug *graphics.Debug, bodies []BodyShapes, filter PhysicsShapeFilter, color dprec.Vec3) {
+	for _, body := range bodies {
+		// NOTE: Bodies are not affected by scale, so only the translation
+		// and rotation of the node are used.
+		absMatrix := body.Node.AbsoluteMatrix()
+		bodyMatrix := dprec.TRSMat4(absMatrix.Translation(), absMatrix.Rotation(), dprec.NewVec3(1.0, 1.0, 1.0))
+
+		definition := body.Definition
+		if filter.Spheres {
+			for _, sphere := range definition.CollisionSpheres {
+				center := dprec.Mat4Vec3Transformation(bodyMatrix, sphere.Translation)
+				DrawSphere(debug, center, sphere.Radius, color)
+			}
+		}
+		if filter.Boxes {
+			for _, box := range definition.CollisionBoxes {
+				drawBox(debug, bodyMatrix, box, color)
+			}
+		}
+		if filter.Meshes {
+			for _, mesh := range definition.CollisionMeshes {
+				drawMesh(debug, bodyMatrix, mesh, color)
+			}
+		}
+	}
+}
+
+func drawBox(debug *graphics.Debug, bodyMatrix dprec.Mat4, box asset.CollisionBox, color dprec.Vec3) {
+	matrix := dprec.Mat4Prod(
+		bodyMatrix,
+		dprec.TRSMat4(box.Translation, box.Rotation, dprec.NewVec3(box.Width/2.0, box.Height/2.0, box.Length/2.0)),
+	)
+	corners := boxCorners(matrix, dprec.NewVec3(-1.0, -1.0, -1.0), dprec.NewVec3(1.0, 1.0, 1.0))
+	drawBoxCorners(debug, corners, color)
+}
+
+func drawMesh(debug *graphics.Debug, bodyMatrix dprec.Mat4, mesh asset.CollisionMesh, color dprec.Vec3) {
+	matrix := dprec.Mat4Prod(
+		bodyMatrix,
+		dprec.TRSMat4(mesh.Translation, mesh.Rotation, dprec.NewVec3(1.0, 1.0, 1.0)),
+	)
+	for _, triangle := range mesh.Triangles {
+		a := dprec.Mat4Vec3Transformation(matrix, triangle.A)
+		b := dprec.Mat4Vec3Transformation(matrix, triangle.B)
+		c := dprec.Mat4Vec3Transformation(matrix, triangle.C)
+		debug.Line(a, b, color)
+		debug.Line(b, c, color)
+		debug.Line(c, a, color)
+	}
+}
+
+// boxCorners returns the corners of the box with the specified bounds
+// after the matrix has been applied to them.
+func boxCorners(matrix dprec.Mat4, minCoord, maxCoord dprec.Vec3) [8]dprec.Vec3 {
+	var corners [8]dprec.Vec3
+	for i := range corners {
+		corner := minCoord
+		if i&1 != 0 {
+			corner.X = maxCoord.X
+		}
+		if i&2 != 0 {
+			corner.Y = maxCoord.Y
+		}
+		if i&4 != 0 {
+			corner.Z = maxCoord.Z
+		}
+		corners[i] = dprec.Mat4Vec3Transformation(matrix, corner)
+	}
+	return corners
+}
+
+// drawBoxCorners outlines a box with corners as returned by boxCorners.
+func drawBoxCorners(debug *graphics.Debug, corners [8]dprec.Vec3, color dprec.Vec3) {
+	// Two corners form an edge when their indices differ in a single bit.
+	for i := range corners {
+		for _, bit := range [...]int{1, 2, 4} {
+			if i&bit == 0 {
+				debug.Line(corners[i], corners[i|bit], color)
+			}
+		}
+	}
+}
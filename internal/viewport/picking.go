@@ -0,0 +1,165 @@
+package viewport
+
+import (
+	"math"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+// DefaultPickRadius is the radius that is used for nodes that don't have
+// any geometry attached, so that they can still be picked.
+const DefaultPickRadius = 0.1
+
+// Ray represents a half-line in world space.
+type Ray struct {
+	Origin    dprec.Vec3
+	Direction dprec.Vec3
+}
+
+// ScreenRay returns the ray that passes through the specified pixel of a
+// viewport with the specified size, as seen by a camera with the specified
+// matrix and vertical field of view.
+func ScreenRay(cameraMatrix dprec.Mat4, fov dprec.Angle, x, y, width, height int) Ray {
+	width = max(width, 1)
+	height = max(height, 1)
+	// NOTE: The camera uses graphics.FoVModeHorizontalPlus, which applies
+	// the field of view vertically and widens the horizontal one with the
+	// aspect ratio.
+	tanHalfFoV := dprec.Tan(fov / 2.0)
+	ndcX := 2.0*(float64(x)+0.5)/float64(width) - 1.0
+	ndcY := 1.0 - 2.0*(float64(y)+0.5)/float64(height)
+	aspect := float64(width) / float64(height)
+
+	direction := dprec.Vec3Sum(
+		dprec.Vec3Sum(
+			dprec.Vec3Prod(cameraMatrix.OrientationX(), ndcX*tanHalfFoV*aspect),
+			dprec.Vec3Prod(cameraMatrix.OrientationY(), ndcY*tanHalfFoV),
+		),
+		dprec.InverseVec3(cameraMatrix.OrientationZ()),
+	)
+	return Ray{
+		Origin:    cameraMatrix.Translation(),
+		Direction: dprec.UnitVec3(direction),
+	}
+}
+
+// ScreenPosition returns the pixel at which the specified point appears in
+// a viewport with the specified size. It is the inverse of ScreenRay and
+// returns false if the point is behind the camera.
+func ScreenPosition(cameraMatrix dprec.Mat4, fov dprec.Angle, point dprec.Vec3, width, height int) (float64, float64, bool) {
+	width = max(width, 1)
+	height = max(height, 1)
+	tanHalfFoV := dprec.Tan(fov / 2.0)
+	aspect := float64(width) / float64(height)
+
+	offset := dprec.Vec3Diff(point, cameraMatrix.Translation())
+	depth := -dprec.Vec3Dot(offset, cameraMatrix.OrientationZ())
+	if depth <= 0.0 {
+		return 0.0, 0.0, false
+	}
+	ndcX := dprec.Vec3Dot(offset, cameraMatrix.OrientationX()) / (depth * tanHalfFoV * aspect)
+	ndcY := dprec.Vec3Dot(offset, cameraMatrix.OrientationY()) / (depth * tanHalfFoV)
+	x := (ndcX + 1.0) * float64(width) / 2.0
+	y := (1.0 - ndcY) * float64(height) / 2.0
+	return x, y, true
+}
+
+// PickVolume is a bounding sphere around a node, in the local space of
+// that node, that is used to pick the node with a Ray.
+type PickVolume struct {
+	Node   *hierarchy.Node
+	Radius float64
+}
+
+// Sphere returns the center and radius of the volume in world space.
+func (v PickVolume) Sphere() (dprec.Vec3, float64) {
+	matrix := v.Node.AbsoluteMatrix()
+	scale := dprec.Max(
+		matrix.OrientationX().Length(),
+		dprec.Max(matrix.OrientationY().Length(), matrix.OrientationZ().Length()),
+	)
+	return matrix.Translation(), v.Radius * scale
+}
+
+// PickVolumes creates volumes for the specified nodes, as returned by
+// ContentNodes for the specified model content. The volume at each index
+// belongs to the content node with the same index.
+//
+// NOTE: Bounding spheres of the geometries are used, since the actual
+// triangles are not kept in memory once uploaded to the GPU.
+func PickVolumes(nodes []*hierarchy.Node, content *asset.Model) []PickVolume {
+	result := make([]PickVolume, len(nodes))
+	for i, node := range nodes {
+		result[i] = PickVolume{
+			Node:   node,
+			Radius: DefaultPickRadius,
+		}
+	}
+	hasGeometry := make([]bool, len(nodes))
+	for _, mesh := range content.Meshes {
+		if int(mesh.NodeIndex) >= len(nodes) || int(mesh.MeshDefinitionIndex) >= len(content.MeshDefinitions) {
+			continue
+		}
+		meshDefinition := content.MeshDefinitions[mesh.MeshDefinitionIndex]
+		if int(meshDefinition.GeometryIndex) >= len(content.Geometries) {
+			continue
+		}
+		geometry := content.Geometries[meshDefinition.GeometryIndex]
+		volume := &result[mesh.NodeIndex]
+		if !hasGeometry[mesh.NodeIndex] {
+			volume.Radius = geometry.BoundingSphereRadius
+			hasGeometry[mesh.NodeIndex] = true
+		} else {
+			volume.Radius = dprec.Max(volume.Radius, geometry.BoundingSphereRadius)
+		}
+	}
+	return result
+}
+
+// Pick returns the node of the volume that is hit first by the ray.
+//
+// When the ray starts inside of multiple volumes, the smallest one is
+// preferred, since it is most likely what the user is trying to reach.
+func Pick(ray Ray, volumes []PickVolume) (*hierarchy.Node, bool) {
+	var (
+		bestNode     *hierarchy.Node
+		bestDistance = math.Inf(1)
+		bestRadius   = math.Inf(1)
+	)
+	for _, volume := range volumes {
+		if volume.Node == nil {
+			continue
+		}
+		center, radius := volume.Sphere()
+		distance, ok := raySphereDistance(ray, center, radius)
+		if !ok {
+			continue
+		}
+		if distance < bestDistance || (distance == bestDistance && radius < bestRadius) {
+			bestNode = volume.Node
+			bestDistance = distance
+			bestRadius = radius
+		}
+	}
+	return bestNode, bestNode != nil
+}
+
+func raySphereDistance(ray Ray, center dprec.Vec3, radius float64) (float64, bool) {
+	offset := dprec.Vec3Diff(ray.Origin, center)
+	b := dprec.Vec3Dot(offset, ray.Direction)
+	c := dprec.Vec3Dot(offset, offset) - radius*radius
+	discriminant := b*b - c
+	if discriminant < 0.0 {
+		return 0.0, false
+	}
+	sqrtDiscriminant := dprec.Sqrt(discriminant)
+	if distance := -b - sqrtDiscriminant; distance >= 0.0 {
+		return distance, true
+	}
+	if -b+sqrtDiscriminant >= 0.0 {
+		return 0.0, true // inside the sphere
+	}
+	return 0.0, false
+}
@@ -0,0 +1,114 @@
+package viewport
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+func TestScreenPositionRoundTrip(t *testing.T) {
+	const (
+		width  = 1600
+		height = 900
+	)
+	fov := dprec.Degrees(DefaultFoV)
+	cameraMatrix := dprec.TRSMat4(
+		dprec.NewVec3(2.0, 3.0, 10.0),
+		dprec.RotationQuat(dprec.Degrees(15.0), dprec.BasisYVec3()),
+		dprec.NewVec3(1.0, 1.0, 1.0),
+	)
+
+	points := []dprec.Vec3{
+		dprec.NewVec3(0.0, 0.0, 0.0),
+		dprec.NewVec3(-6.0, 5.0, 2.0),
+		dprec.NewVec3(9.0, -2.0, -4.0),
+	}
+	for _, point := range points {
+		x, y, ok := ScreenPosition(cameraMatrix, fov, point, width, height)
+		if !ok {
+			t.Fatalf("expected %v to be in front of the camera", point)
+		}
+		if x < 0 || x >= width || y < 0 || y >= height {
+			t.Fatalf("expected %v to be inside the viewport, got %f, %f", point, x, y)
+		}
+
+		ray := ScreenRay(cameraMatrix, fov, int(x), int(y), width, height)
+
+		// The ray passes through the center of the pixel, so it can miss the
+		// point by up to half a pixel at the depth of the point.
+		offset := dprec.Vec3Diff(point, ray.Origin)
+		depth := dprec.Vec3Dot(offset, ray.Direction)
+		closest := dprec.Vec3Sum(ray.Origin, dprec.Vec3Prod(ray.Direction, depth))
+		pixelSize := 2.0 * depth * dprec.Tan(fov/2.0) / height
+		if distance := dprec.Vec3Diff(point, closest).Length(); distance > pixelSize {
+			t.Errorf("expected the ray through %v to pass through it, missed by %f (pixel size %f)", point, distance, pixelSize)
+		}
+	}
+}
+
+func TestScreenRayCorners(t *testing.T) {
+	const (
+		width  = 1600
+		height = 900
+	)
+	fov := dprec.Degrees(DefaultFoV)
+	ray := ScreenRay(dprec.IdentityMat4(), fov, width-1, 0, width, height)
+
+	// The field of view is vertical and the horizontal one is widened by
+	// the aspect ratio.
+	tanHalfFoV := dprec.Tan(fov / 2.0)
+	expectedX := tanHalfFoV * float64(width) / float64(height)
+	slopeX := ray.Direction.X / -ray.Direction.Z
+	slopeY := ray.Direction.Y / -ray.Direction.Z
+	if math.Abs(slopeX-expectedX) > 0.01 || math.Abs(slopeY-tanHalfFoV) > 0.01 {
+		t.Errorf("unexpected corner ray slopes %f, %f", slopeX, slopeY)
+	}
+}
+
+func TestPickVolumesKeepsDuplicateNames(t *testing.T) {
+	content := &asset.Model{
+		Nodes: []asset.Node{
+			{Name: "Wheel", ParentIndex: asset.UnspecifiedNodeIndex},
+			{Name: "Wheel", ParentIndex: asset.UnspecifiedNodeIndex},
+			{Name: "", ParentIndex: 0},
+		},
+		Meshes: []asset.Mesh{
+			{NodeIndex: 1, MeshDefinitionIndex: 0},
+		},
+		MeshDefinitions: []asset.MeshDefinition{
+			{GeometryIndex: 0},
+		},
+		Geometries: []asset.Geometry{
+			{BoundingSphereRadius: 2.0},
+		},
+	}
+	root := hierarchy.NewNode()
+	first := hierarchy.NewNode()
+	first.SetName("Wheel")
+	root.AppendChild(first)
+	second := hierarchy.NewNode()
+	second.SetName("Wheel")
+	root.AppendChild(second)
+	unnamed := hierarchy.NewNode()
+	first.AppendChild(unnamed)
+
+	nodes := ContentNodes(root, content)
+	expected := []*hierarchy.Node{first, second, unnamed}
+	if !slices.Equal(nodes, expected) {
+		t.Fatalf("expected nodes to be indexed as in the content")
+	}
+
+	volumes := PickVolumes(nodes, content)
+	if len(volumes) != len(expected) {
+		t.Fatalf("expected %d volumes, got %d", len(expected), len(volumes))
+	}
+	for i, radius := range []float64{DefaultPickRadius, 2.0, DefaultPickRadius} {
+		if volumes[i].Node != expected[i] || volumes[i].Radius != radius {
+			t.Fatalf("unexpected volume %d: %q with radius %f", i, volumes[i].Node.Name(), volumes[i].Radius)
+		}
+	}
+}
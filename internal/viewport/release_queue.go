@@ -0,0 +1,87 @@
+package viewport
+
+import (
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/render"
+)
+
+// maxFenceFrames specifies the number of updates after which an entry is
+// released even though its fence has not been reached.
+//
+// NOTE: The native backend reports a fence that has errored as not ready,
+// so such an entry would otherwise never be released.
+const maxFenceFrames = 600
+
+// NewReleaseQueue creates a new ReleaseQueue that uses the specified API
+// to track the progress of the GPU.
+func NewReleaseQueue(api render.API) *ReleaseQueue {
+	return &ReleaseQueue{
+		api: api,
+	}
+}
+
+// ReleaseQueue delays the release of resources until the GPU has finished
+// processing all commands that could have referenced them.
+type ReleaseQueue struct {
+	api     render.API
+	entries []releaseEntry
+}
+
+type releaseEntry struct {
+	fence   render.Fence
+	frames  int
+	release func()
+}
+
+// Release schedules the specified function to be called once the GPU
+// no longer uses the resources that it frees.
+func (q *ReleaseQueue) Release(release func()) {
+	q.entries = append(q.entries, releaseEntry{
+		release: release,
+	})
+}
+
+// Update should be called once per frame, before any rendering takes place.
+//
+// Entries that were added since the last call are assigned a fence, which
+// covers all the frames that have been submitted so far. Entries whose
+// fence has been reached by the GPU, has errored or has not been reached
+// for too long are released.
+func (q *ReleaseQueue) Update() {
+	remaining := q.entries[:0]
+	for _, entry := range q.entries {
+		if entry.fence == nil {
+			entry.fence = q.api.Queue().TrackSubmittedWorkDone()
+			remaining = append(remaining, entry)
+			continue
+		}
+		entry.frames++
+		switch status := entry.fence.Status(); status {
+		case render.FenceStatusSuccess:
+		case render.FenceStatusNotReady:
+			if entry.frames < maxFenceFrames {
+				remaining = append(remaining, entry)
+				continue
+			}
+			log.Warn("Releasing resources after fence was not reached for %d frames", entry.frames)
+		default:
+			log.Warn("Releasing resources after fence status %s", status)
+		}
+		entry.fence.Release()
+		entry.release()
+	}
+	clear(q.entries[len(remaining):])
+	q.entries = remaining
+}
+
+// Flush releases all entries right away, regardless of the GPU progress.
+// This should only be used on shutdown.
+func (q *ReleaseQueue) Flush() {
+	for _, entry := range q.entries {
+		if entry.fence != nil {
+			entry.fence.Release()
+		}
+		entry.release()
+	}
+	q.entries = nil
+}
@@ -0,0 +1,102 @@
+package viewport
+
+import (
+	"testing"
+
+	"github.com/mokiat/lacking/render"
+)
+
+type fakeAPI struct {
+	render.API
+	queue *fakeQueue
+}
+
+func (a *fakeAPI) Queue() render.Queue {
+	return a.queue
+}
+
+type fakeQueue struct {
+	render.Queue
+	fences []*fakeFence
+}
+
+func (q *fakeQueue) TrackSubmittedWorkDone() render.Fence {
+	fence := &fakeFence{}
+	q.fences = append(q.fences, fence)
+	return fence
+}
+
+type fakeFence struct {
+	render.Fence
+	status   render.FenceStatus
+	released bool
+}
+
+func (f *fakeFence) Status() render.FenceStatus {
+	return f.status
+}
+
+func (f *fakeFence) Release() {
+	f.released = true
+}
+
+func newTestReleaseQueue() (*ReleaseQueue, *fakeQueue, *int) {
+	queue := &fakeQueue{}
+	releaseQueue := NewReleaseQueue(&fakeAPI{queue: queue})
+	var released int
+	releaseQueue.Release(func() {
+		released++
+	})
+	releaseQueue.Update()
+	return releaseQueue, queue, &released
+}
+
+func TestReleaseQueueReleasesReachedFence(t *testing.T) {
+	releaseQueue, queue, released := newTestReleaseQueue()
+
+	releaseQueue.Update()
+	if *released != 0 {
+		t.Fatalf("expected no release before the fence is reached")
+	}
+
+	queue.fences[0].status = render.FenceStatusSuccess
+	releaseQueue.Update()
+	if *released != 1 {
+		t.Fatalf("expected one release, got %d", *released)
+	}
+	if !queue.fences[0].released {
+		t.Fatalf("expected fence to be released")
+	}
+}
+
+func TestReleaseQueueReleasesErroredFence(t *testing.T) {
+	releaseQueue, queue, released := newTestReleaseQueue()
+
+	queue.fences[0].status = render.FenceStatus(0xFF)
+	releaseQueue.Update()
+	if *released != 1 {
+		t.Fatalf("expected one release, got %d", *released)
+	}
+	if !queue.fences[0].released {
+		t.Fatalf("expected fence to be released")
+	}
+}
+
+func TestReleaseQueueReleasesStuckFence(t *testing.T) {
+	releaseQueue, queue, released := newTestReleaseQueue()
+
+	for range maxFenceFrames - 1 {
+		releaseQueue.Update()
+	}
+	if *released != 0 {
+		t.Fatalf("expected no release before the frame limit")
+	}
+
+	releaseQueue.Update()
+	if *released != 1 {
+		t.Fatalf("expected one release, got %d", *released)
+	}
+	if !queue.fences[0].released {
+		t.Fatalf("expected fence to be released")
+	}
+}
@@ -0,0 +1,341 @@
+package viewport
+
+import (
+	"slices"
+	"time"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/mokiat/lacking/game/graphics"
+	"github.com/mokiat/lacking/game/hierarchy"
+	"github.com/mokiat/lacking/game/physics"
+	"github.com/mokiat/lacking/game/physics/acceleration"
+	"github.com/mokiat/lacking/game/physics/collision"
+	"github.com/mokiat/lacking/render"
+)
+
+// NewStage creates a new scene, together with its own Environment, that
+// is used to preview a single version of a model.
+//
+// The scene of the stage is not activated in the engine, instead it is
+// expected that the owner calls Render explicitly.
+func NewStage(engine *game.Engine, commonData *CommonData) *Stage {
+	scene := engine.CreateScene()
+	if engine.ActiveScene() == scene {
+		engine.SetActiveScene(nil)
+	}
+
+	// NOTE: The physics simulation is paused until it is explicitly started,
+	// so that bodies stay where they were authored.
+	physicsScene := scene.Physics()
+	physicsScene.SetTimeSpeed(0.0)
+	physicsScene.CreateGlobalAccelerator(acceleration.NewGravityDirection())
+	physicsScene.CreateProp(physics.PropInfo{
+		Name: "Ground",
+		CollisionSet: collision.NewSet(
+			collision.WithBoxes([]collision.Box{
+				collision.NewBox(
+					dprec.NewVec3(0.0, -0.5, 0.0),
+					dprec.IdentityQuat(),
+					dprec.NewVec3(GroundSize, 1.0, GroundSize),
+				),
+			}),
+		),
+	})
+
+	return &Stage{
+		commonData:     commonData,
+		scene:          scene,
+		environment:    NewEnvironment(scene.Graphics(), commonData),
+		transformGizmo: NewTransformGizmo(scene.Graphics(), commonData),
+		lastTick:       time.Now(),
+	}
+}
+
+// Stage holds a scene with a single model and the ResourceSet that the
+// model was loaded from.
+type Stage struct {
+	commonData  *CommonData
+	scene       *game.Scene
+	environment *Environment
+
+	resourceSet     *game.ResourceSet
+	modelDefinition *game.ModelDefinition
+	model           *game.Model
+	modelNode       *hierarchy.Node
+	modelPlayback   *game.AnimationPlayback
+	modelContent    *asset.Model
+	contentNodes    []*hierarchy.Node
+	dynamic         bool
+	pickVolumes     []PickVolume
+	surfaceMeshes   []surfaceMesh
+	surfaceBuilt    bool
+	bodyShapes      []BodyShapes
+	overlays        *Overlays
+	overlayFilter   OverlayFilter
+
+	transformGizmo *TransformGizmo
+
+	ghost *Ghost
+
+	lastTick time.Time
+}
+
+// maxUpdateTime limits the time that a single render advances the scene.
+// Stages are not rendered while their tab is hidden, and the whole time
+// that they spent hidden would otherwise be simulated at once, freezing
+// the UI and letting bodies tunnel through the ground.
+const maxUpdateTime = 100 * time.Millisecond
+
+func (s *Stage) Scene() *game.Scene {
+	return s.scene
+}
+
+func (s *Stage) Environment() *Environment {
+	return s.environment
+}
+
+// HasModel returns whether a model has been placed in this stage.
+func (s *Stage) HasModel() bool {
+	return s.modelDefinition != nil
+}
+
+func (s *Stage) ModelNode() *hierarchy.Node {
+	return s.modelNode
+}
+
+// ModelContent returns the raw content of the model, if it was provided.
+func (s *Stage) ModelContent() *asset.Model {
+	return s.modelContent
+}
+
+// IsDynamic returns whether the nodes of the model can be edited.
+func (s *Stage) IsDynamic() bool {
+	return s.dynamic
+}
+
+func (s *Stage) TransformGizmo() *TransformGizmo {
+	return s.transformGizmo
+}
+
+// StageModelInfo describes a model that is to be placed in a Stage.
+type StageModelInfo struct {
+
+	// ResourceSet is the set that the model was loaded from. The stage takes
+	// ownership of it and releases it when the stage is deleted.
+	ResourceSet *game.ResourceSet
+
+	// Definition is the model to be placed.
+	Definition *game.ModelDefinition
+
+	// Content is optional and is used to make the nodes of the model
+	// pickable and to inspect its materials.
+	Content *asset.Model
+
+	// Dynamic specifies whether the meshes of the model should follow
+	// changes to the nodes of the model.
+	//
+	// NOTE: Setting this to true kills large scenes, so it should only be
+	// used when the nodes need to be edited.
+	Dynamic bool
+}
+
+// ShowModel places the specified model in the stage.
+func (s *Stage) ShowModel(info StageModelInfo) {
+	s.resourceSet = info.ResourceSet
+	s.modelDefinition = info.Definition
+	s.modelContent = info.Content
+	s.dynamic = info.Dynamic
+
+	model := s.scene.CreateModel(game.ModelInfo{
+		Name:       "Model",
+		Definition: info.Definition,
+		IsDynamic:  info.Dynamic,
+	})
+	s.model = model
+	s.modelNode = model.Root()
+	if info.Content != nil {
+		s.contentNodes = ContentNodes(s.modelNode, info.Content)
+		s.pickVolumes = PickVolumes(s.contentNodes, info.Content)
+		s.bodyShapes = PhysicsBodyShapes(s.contentNodes, info.Content)
+		s.overlays = NewOverlays(s.scene.Graphics(), s.commonData, s.contentNodes, info.Content)
+		s.overlays.SetFilter(s.overlayFilter)
+	}
+	s.placeBodies()
+	if len(model.Animations()) > 0 {
+		animation := model.Animations()[0]
+		s.modelPlayback = animation.Playback().SetLoop(true)
+		s.scene.PlayAnimationTree(s.modelPlayback)
+	}
+	// TODO: Find camera and light nodes and attach indicator gizmos to them
+	// from the common data.
+}
+
+// BodyShapes returns the collision shapes of the bodies of the model.
+func (s *Stage) BodyShapes() []BodyShapes {
+	return s.bodyShapes
+}
+
+// SetOverlayFilter changes the overlays that are displayed for the model.
+func (s *Stage) SetOverlayFilter(filter OverlayFilter) {
+	s.overlayFilter = filter
+	if s.overlays != nil {
+		s.overlays.SetFilter(filter)
+	}
+}
+
+// DrawOverlays draws the overlays of the model that are made of debug
+// lines.
+func (s *Stage) DrawOverlays(debug *graphics.Debug) {
+	if s.overlays != nil {
+		s.overlays.Draw(debug)
+	}
+}
+
+// PhysicsRunning returns whether the physics simulation is advancing.
+func (s *Stage) PhysicsRunning() bool {
+	return s.scene.Physics().TimeSpeed() > 0.0
+}
+
+// SetPhysicsRunning starts or pauses the physics simulation. Only the
+// bodies of dynamic models are simulated.
+func (s *Stage) SetPhysicsRunning(running bool) {
+	if running {
+		s.scene.Physics().SetTimeSpeed(1.0)
+	} else {
+		s.scene.Physics().SetTimeSpeed(0.0)
+	}
+}
+
+// placeBodies moves the bodies of the model to the nodes that they are
+// attached to.
+//
+// NOTE: The engine creates the bodies of dynamic models at the origin and
+// lets them drive the nodes afterwards.
+func (s *Stage) placeBodies() {
+	if s.modelContent == nil {
+		return
+	}
+	for _, body := range s.modelContent.Bodies {
+		node, ok := contentNode(s.contentNodes, body.NodeIndex)
+		if !ok {
+			continue
+		}
+		if source, ok := node.Source().(game.BodyNodeSource); ok {
+			absMatrix := node.AbsoluteMatrix()
+			source.Body.SetPosition(absMatrix.Translation())
+			source.Body.SetRotation(absMatrix.Rotation())
+		}
+	}
+}
+
+// Pick returns the node of the model that is hit first by the ray.
+func (s *Stage) Pick(ray Ray) (*hierarchy.Node, bool) {
+	return Pick(ray, s.pickVolumes)
+}
+
+// PickSurface returns the point at which the ray first hits the surface
+// of the model.
+func (s *Stage) PickSurface(ray Ray) (dprec.Vec3, bool) {
+	if s.modelContent == nil {
+		return dprec.Vec3{}, false
+	}
+	// NOTE: The triangles are only extracted once they are first needed,
+	// since this can be expensive for large models.
+	if !s.surfaceBuilt {
+		s.surfaceMeshes = surfaceMeshes(s.contentNodes, s.modelContent)
+		s.surfaceBuilt = true
+	}
+	return pickSurface(ray, s.surfaceMeshes)
+}
+
+// ModelBounds returns the world-space axis-aligned box that contains all
+// meshes of the model.
+func (s *Stage) ModelBounds() (dprec.Vec3, dprec.Vec3, bool) {
+	if s.overlays == nil {
+		return dprec.Vec3{}, dprec.Vec3{}, false
+	}
+	return s.overlays.Bounds()
+}
+
+// PickVolume returns the volume of the model node with the specified
+// index.
+//
+// Nodes are indexed the same way as the nodes of the model content, so
+// stages of the same model use the same indices.
+func (s *Stage) PickVolume(index int) (PickVolume, bool) {
+	if index < 0 || index >= len(s.pickVolumes) || s.pickVolumes[index].Node == nil {
+		return PickVolume{}, false
+	}
+	return s.pickVolumes[index], true
+}
+
+// NodeIndex returns the content index of the specified model node.
+func (s *Stage) NodeIndex(node *hierarchy.Node) (int, bool) {
+	if node == nil {
+		return 0, false
+	}
+	index := slices.Index(s.contentNodes, node)
+	return index, index >= 0
+}
+
+// ShowGhost places a translucent version of the specified model content
+// in the stage, which is used to overlay a different version of the model.
+// The meshes of the ghost are only built once for the same content.
+func (s *Stage) ShowGhost(content *asset.Model) {
+	if s.ghost != nil && s.ghost.Content() != content {
+		s.ghost.Delete()
+		s.ghost = nil
+	}
+	if content == nil {
+		return
+	}
+	if s.ghost == nil {
+		s.ghost = NewGhost(s.scene.Graphics(), s.commonData, content)
+	}
+	s.ghost.SetActive(true)
+}
+
+// HideGhost stops rendering the ghost model, if there is one.
+func (s *Stage) HideGhost() {
+	if s.ghost != nil {
+		s.ghost.SetActive(false)
+	}
+}
+
+// Render updates the scene and renders it into the specified framebuffer.
+func (s *Stage) Render(framebuffer render.Framebuffer, viewport graphics.Viewport) {
+	// NOTE: Multiple stages can be visible at the same time, so each one
+	// tracks its own update time, instead of relying on the engine.
+	currentTime := time.Now()
+	elapsedTime := min(currentTime.Sub(s.lastTick), maxUpdateTime)
+	s.lastTick = currentTime
+
+	s.scene.Update(elapsedTime)
+	if s.overlays != nil {
+		s.overlays.Update()
+	}
+	s.scene.Render(framebuffer, viewport)
+}
+
+// Delete releases the scene of the stage and the ResourceSet of the model.
+func (s *Stage) Delete() {
+	if s.ghost != nil {
+		s.ghost.Delete()
+		s.ghost = nil
+	}
+	s.transformGizmo.Delete()
+	if s.overlays != nil {
+		s.overlays.Delete()
+		s.overlays = nil
+	}
+	if s.modelPlayback != nil {
+		s.scene.StopAnimationTree(s.modelPlayback)
+		s.modelPlayback = nil
+	}
+	s.scene.Delete()
+	if s.resourceSet != nil {
+		s.resourceSet.Delete()
+	}
+}
@@ -0,0 +1,248 @@
+package viewport
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/mokiat/gblob"
+	"github.com/mokiat/lacking/game/asset"
+)
+
+const (
+	TextureChannelAll TextureChannel = iota
+	TextureChannelRed
+	TextureChannelGreen
+	TextureChannelBlue
+	TextureChannelAlpha
+)
+
+// TextureChannel determines which channels of a texture are displayed.
+type TextureChannel int
+
+// String returns a human-readable name of this channel.
+func (c TextureChannel) String() string {
+	switch c {
+	case TextureChannelAll:
+		return "RGBA"
+	case TextureChannelRed:
+		return "R"
+	case TextureChannelGreen:
+		return "G"
+	case TextureChannelBlue:
+		return "B"
+	case TextureChannelAlpha:
+		return "A"
+	default:
+		return "Unknown"
+	}
+}
+
+// TextureChannels returns all channels that can be displayed.
+func TextureChannels() []TextureChannel {
+	return []TextureChannel{
+		TextureChannelAll,
+		TextureChannelRed,
+		TextureChannelGreen,
+		TextureChannelBlue,
+		TextureChannelAlpha,
+	}
+}
+
+// TextureImageInfo specifies which part of a texture is converted to an
+// image and how.
+type TextureImageInfo struct {
+
+	// MipLevel is the index of the mipmap layer to be used.
+	MipLevel int
+
+	// Face is the index of the layer to be used. For cube textures this is
+	// the side in the order front, back, left, right, top, bottom.
+	Face int
+
+	// Channel determines which channels are displayed.
+	Channel TextureChannel
+
+	// Exposure is the number of stops by which the colors of HDR textures
+	// are brightened or darkened.
+	Exposure float64
+}
+
+// TextureImage converts a texture into an image that can be displayed.
+func TextureImage(texture asset.Texture, info TextureImageInfo) (*image.NRGBA, error) {
+	if info.MipLevel < 0 || info.MipLevel >= len(texture.MipmapLayers) {
+		return nil, fmt.Errorf("mip level %d not found", info.MipLevel)
+	}
+	layer := texture.MipmapLayers[info.MipLevel]
+	if info.Face < 0 || info.Face >= len(layer.Layers) {
+		return nil, fmt.Errorf("face %d not found", info.Face)
+	}
+	texelLayout, ok := texelLayouts[texture.Format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported texel format %d", texture.Format)
+	}
+
+	width, height := int(layer.Width), int(layer.Height)
+	data := gblob.LittleEndianBlock(layer.Layers[info.Face].Data)
+	texelSize := texelLayout.components * texelLayout.componentSize
+	if len(data) < width*height*texelSize {
+		return nil, fmt.Errorf("expected %d bytes of data but got %d", width*height*texelSize, len(data))
+	}
+
+	hdr := IsHDRTextureFormat(texture.Format)
+	scale := math.Exp2(info.Exposure)
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			texel := [4]float64{0.0, 0.0, 0.0, 1.0}
+			offset := (y*width + x) * texelSize
+			for i := range texelLayout.components {
+				texel[i] = texelLayout.read(data, offset+i*texelLayout.componentSize)
+			}
+			if hdr {
+				for i := range 3 {
+					texel[i] = math.Pow(max(texel[i]*scale, 0.0), 1.0/2.2)
+				}
+			}
+			result.SetNRGBA(x, y, texelColor(texel, info.Channel))
+		}
+	}
+	return result, nil
+}
+
+// TextureFaceCount returns the number of faces that can be displayed for
+// the specified texture.
+func TextureFaceCount(texture asset.Texture) int {
+	if len(texture.MipmapLayers) == 0 {
+		return 0
+	}
+	return len(texture.MipmapLayers[0].Layers)
+}
+
+// IsHDRTextureFormat returns whether the specified format holds floating
+// point data that can exceed the displayable range.
+func IsHDRTextureFormat(format asset.TexelFormat) bool {
+	switch format {
+	case asset.TexelFormatR16F, asset.TexelFormatR32F,
+		asset.TexelFormatRG16F, asset.TexelFormatRG32F,
+		asset.TexelFormatRGB16F, asset.TexelFormatRGB32F,
+		asset.TexelFormatRGBA16F, asset.TexelFormatRGBA32F:
+		return true
+	default:
+		return false
+	}
+}
+
+// TextureFormatName returns a human-readable name of the specified format.
+func TextureFormatName(format asset.TexelFormat) string {
+	if name, ok := texelFormatNames[format]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+var texelFormatNames = map[asset.TexelFormat]string{
+	asset.TexelFormatR8:       "R8",
+	asset.TexelFormatR16:      "R16",
+	asset.TexelFormatR16F:     "R16F",
+	asset.TexelFormatR32F:     "R32F",
+	asset.TexelFormatRG8:      "RG8",
+	asset.TexelFormatRG16:     "RG16",
+	asset.TexelFormatRG16F:    "RG16F",
+	asset.TexelFormatRG32F:    "RG32F",
+	asset.TexelFormatRGB8:     "RGB8",
+	asset.TexelFormatRGB16:    "RGB16",
+	asset.TexelFormatRGB16F:   "RGB16F",
+	asset.TexelFormatRGB32F:   "RGB32F",
+	asset.TexelFormatRGBA8:    "RGBA8",
+	asset.TexelFormatRGBA16:   "RGBA16",
+	asset.TexelFormatRGBA16F:  "RGBA16F",
+	asset.TexelFormatRGBA32F:  "RGBA32F",
+	asset.TexelFormatDepth16F: "Depth16F",
+	asset.TexelFormatDepth32F: "Depth32F",
+}
+
+type texelLayout struct {
+	components    int
+	componentSize int
+	read          func(data gblob.LittleEndianBlock, offset int) float64
+}
+
+var texelLayouts = map[asset.TexelFormat]texelLayout{
+	asset.TexelFormatR8:       {components: 1, componentSize: 1, read: readUnorm8},
+	asset.TexelFormatR16:      {components: 1, componentSize: 2, read: readUnorm16},
+	asset.TexelFormatR16F:     {components: 1, componentSize: 2, read: readFloat16},
+	asset.TexelFormatR32F:     {components: 1, componentSize: 4, read: readFloat32},
+	asset.TexelFormatRG8:      {components: 2, componentSize: 1, read: readUnorm8},
+	asset.TexelFormatRG16:     {components: 2, componentSize: 2, read: readUnorm16},
+	asset.TexelFormatRG16F:    {components: 2, componentSize: 2, read: readFloat16},
+	asset.TexelFormatRG32F:    {components: 2, componentSize: 4, read: readFloat32},
+	asset.TexelFormatRGB8:     {components: 3, componentSize: 1, read: readUnorm8},
+	asset.TexelFormatRGB16:    {components: 3, componentSize: 2, read: readUnorm16},
+	asset.TexelFormatRGB16F:   {components: 3, componentSize: 2, read: readFloat16},
+	asset.TexelFormatRGB32F:   {components: 3, componentSize: 4, read: readFloat32},
+	asset.TexelFormatRGBA8:    {components: 4, componentSize: 1, read: readUnorm8},
+	asset.TexelFormatRGBA16:   {components: 4, componentSize: 2, read: readUnorm16},
+	asset.TexelFormatRGBA16F:  {components: 4, componentSize: 2, read: readFloat16},
+	asset.TexelFormatRGBA32F:  {components: 4, componentSize: 4, read: readFloat32},
+	asset.TexelFormatDepth16F: {components: 1, componentSize: 2, read: readFloat16},
+	asset.TexelFormatDepth32F: {components: 1, componentSize: 4, read: readFloat32},
+}
+
+func readUnorm8(data gblob.LittleEndianBlock, offset int) float64 {
+	return float64(data.Uint8(offset)) / math.MaxUint8
+}
+
+func readUnorm16(data gblob.LittleEndianBlock, offset int) float64 {
+	return float64(data.Uint16(offset)) / math.MaxUint16
+}
+
+func readFloat16(data gblob.LittleEndianBlock, offset int) float64 {
+	return float64(halfToFloat32(data.Uint16(offset)))
+}
+
+func readFloat32(data gblob.LittleEndianBlock, offset int) float64 {
+	return float64(data.Float32(offset))
+}
+
+// halfToFloat32 converts an IEEE 754 half-precision float to a float32.
+func halfToFloat32(half uint16) float32 {
+	sign := uint32(half>>15) << 31
+	exponent := uint32(half>>10) & 0x1F
+	mantissa := uint32(half) & 0x3FF
+	switch {
+	case exponent == 0 && mantissa == 0:
+		return math.Float32frombits(sign)
+	case exponent == 0:
+		// subnormal numbers are normalized
+		for mantissa&0x400 == 0 {
+			mantissa <<= 1
+			exponent--
+		}
+		exponent++
+		mantissa &= 0x3FF
+	case exponent == 0x1F:
+		return math.Float32frombits(sign | 0xFF<<23 | mantissa<<13)
+	}
+	return math.Float32frombits(sign | (exponent+127-15)<<23 | mantissa<<13)
+}
+
+func texelColor(texel [4]float64, channel TextureChannel) color.NRGBA {
+	switch channel {
+	case TextureChannelRed, TextureChannelGreen, TextureChannelBlue, TextureChannelAlpha:
+		value := unitToUint8(texel[channel-TextureChannelRed])
+		return color.NRGBA{R: value, G: value, B: value, A: 255}
+	default:
+		return color.NRGBA{
+			R: unitToUint8(texel[0]),
+			G: unitToUint8(texel[1]),
+			B: unitToUint8(texel[2]),
+			A: unitToUint8(texel[3]),
+		}
+	}
+}
+
+func unitToUint8(value float64) uint8 {
+	return uint8(min(max(value, 0.0), 1.0)*255.0 + 0.5)
+}
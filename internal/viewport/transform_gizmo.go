@@ -0,0 +1,380 @@
+package viewport
+
+import (
+	"math"
+
+	"github.com/mokiat/gomath/dprec"
+	"github.com/mokiat/lacking/game/graphics"
+	"github.com/mokiat/lacking/game/hierarchy"
+)
+
+const (
+	// handleScreenFactor controls the size of the handles relative to
+	// their distance from the camera, so that they keep a constant size
+	// on the screen.
+	handleScreenFactor = 0.15
+
+	// handlePickTolerance is the distance, relative to the handle size,
+	// within which a ray is considered to hit a handle.
+	handlePickTolerance = 0.1
+
+	noAxis = -1
+)
+
+const (
+	TransformModeTranslate TransformMode = iota
+	TransformModeRotate
+	TransformModeScale
+)
+
+// TransformMode determines which kind of handles are shown by a
+// TransformGizmo.
+type TransformMode int
+
+const (
+	TransformSpaceWorld TransformSpace = iota
+	TransformSpaceLocal
+)
+
+// TransformSpace determines whether the handles of a TransformGizmo are
+// aligned to the world axes or to the axes of the target node.
+type TransformSpace int
+
+// Snapping holds the increments that transformations are rounded to.
+// A zero increment disables snapping for that kind of transformation.
+type Snapping struct {
+	Translation float64
+	Rotation    dprec.Angle
+	Scale       float64
+}
+
+// NewTransformGizmo creates a new TransformGizmo that places its handles
+// in the specified scene.
+func NewTransformGizmo(gfxScene *graphics.Scene, commonData *CommonData) *TransformGizmo {
+	gizmo := &TransformGizmo{
+		activeAxis: noAxis,
+	}
+	for axis := range 3 {
+		gizmo.translateHandles[axis] = gfxScene.CreateMesh(graphics.MeshInfo{
+			Definition: commonData.TranslateHandleMeshDefinition(axis),
+		})
+		gizmo.rotateHandles[axis] = gfxScene.CreateMesh(graphics.MeshInfo{
+			Definition: commonData.RotateHandleMeshDefinition(axis),
+		})
+		gizmo.scaleHandles[axis] = gfxScene.CreateMesh(graphics.MeshInfo{
+			Definition: commonData.ScaleHandleMeshDefinition(axis),
+		})
+	}
+	gizmo.refreshHandles()
+	return gizmo
+}
+
+// TransformGizmo allows a node to be translated, rotated and scaled by
+// dragging handles along the three axes.
+type TransformGizmo struct {
+	translateHandles [3]*graphics.Mesh
+	rotateHandles    [3]*graphics.Mesh
+	scaleHandles     [3]*graphics.Mesh
+
+	mode     TransformMode
+	space    TransformSpace
+	snapping Snapping
+	target   *hierarchy.Node
+
+	handleSize float64
+
+	activeAxis  int
+	startMatrix dprec.Mat4
+	startOffset float64
+	startVector dprec.Vec3
+}
+
+func (g *TransformGizmo) Mode() TransformMode {
+	return g.mode
+}
+
+func (g *TransformGizmo) SetMode(mode TransformMode) {
+	g.mode = mode
+	g.refreshHandles()
+}
+
+func (g *TransformGizmo) Space() TransformSpace {
+	return g.space
+}
+
+func (g *TransformGizmo) SetSpace(space TransformSpace) {
+	g.space = space
+}
+
+func (g *TransformGizmo) SetSnapping(snapping Snapping) {
+	g.snapping = snapping
+}
+
+// Target returns the node that is manipulated by the gizmo or nil if the
+// gizmo is hidden.
+func (g *TransformGizmo) Target() *hierarchy.Node {
+	return g.target
+}
+
+// SetTarget changes the node that is manipulated by the gizmo. Passing
+// nil hides the gizmo.
+func (g *TransformGizmo) SetTarget(target *hierarchy.Node) {
+	g.target = target
+	g.activeAxis = noAxis
+	g.refreshHandles()
+}
+
+// Dragging returns whether a handle is currently being dragged.
+func (g *TransformGizmo) Dragging() bool {
+	return g.activeAxis != noAxis
+}
+
+// Update positions the handles around the target, scaling them so that
+// they have the same size on the screen regardless of the camera distance.
+func (g *TransformGizmo) Update(cameraMatrix dprec.Mat4) {
+	if g.target == nil {
+		return
+	}
+	center := g.target.AbsoluteMatrix().Translation()
+	distance := dprec.Vec3Diff(cameraMatrix.Translation(), center).Length()
+	g.handleSize = dprec.Max(distance*handleScreenFactor, 0.001)
+
+	handleMatrix := dprec.Mat4MultiProd(
+		dprec.TranslationMat4(center.X, center.Y, center.Z),
+		g.spaceMatrix(g.target.AbsoluteMatrix()),
+		dprec.ScaleMat4(g.handleSize, g.handleSize, g.handleSize),
+	)
+	for axis := range 3 {
+		g.translateHandles[axis].SetMatrix(handleMatrix)
+		g.rotateHandles[axis].SetMatrix(handleMatrix)
+		g.scaleHandles[axis].SetMatrix(handleMatrix)
+	}
+}
+
+// BeginDrag starts dragging the handle that is hit by the specified ray.
+// It returns false if no handle was hit.
+func (g *TransformGizmo) BeginDrag(ray Ray) bool {
+	if g.target == nil || g.handleSize == 0.0 {
+		return false
+	}
+	g.startMatrix = g.target.AbsoluteMatrix()
+	axis := g.pickAxis(ray)
+	if axis == noAxis {
+		return false
+	}
+	g.activeAxis = axis
+	switch g.mode {
+	case TransformModeTranslate, TransformModeScale:
+		g.startOffset = g.axisOffset(ray, axis)
+	case TransformModeRotate:
+		point, ok := g.planeIntersection(ray, axis)
+		if !ok {
+			g.activeAxis = noAxis
+			return false
+		}
+		g.startVector = dprec.Vec3Diff(point, g.startMatrix.Translation())
+	}
+	return true
+}
+
+// Drag updates the target according to the movement of the handle that
+// is being dragged.
+func (g *TransformGizmo) Drag(ray Ray) {
+	if g.activeAxis == noAxis {
+		return
+	}
+	center := g.startMatrix.Translation()
+	axisDirection := g.axisDirection(g.activeAxis)
+
+	var matrix dprec.Mat4
+	switch g.mode {
+	case TransformModeTranslate:
+		delta := snap(g.axisOffset(ray, g.activeAxis)-g.startOffset, g.snapping.Translation)
+		offset := dprec.Vec3Prod(axisDirection, delta)
+		matrix = dprec.Mat4Prod(
+			dprec.TranslationMat4(offset.X, offset.Y, offset.Z),
+			g.startMatrix,
+		)
+
+	case TransformModeRotate:
+		point, ok := g.planeIntersection(ray, g.activeAxis)
+		if !ok {
+			return
+		}
+		vector := dprec.Vec3Diff(point, center)
+		angle := signedAngle(g.startVector, vector, axisDirection)
+		angle = dprec.Angle(snap(float64(angle), float64(g.snapping.Rotation)))
+		matrix = dprec.Mat4MultiProd(
+			dprec.TranslationMat4(center.X, center.Y, center.Z),
+			dprec.RotationMat4(angle, axisDirection.X, axisDirection.Y, axisDirection.Z),
+			dprec.TranslationMat4(-center.X, -center.Y, -center.Z),
+			g.startMatrix,
+		)
+
+	case TransformModeScale:
+		if math.Abs(g.startOffset) < 0.0001 {
+			return
+		}
+		factor := g.axisOffset(ray, g.activeAxis) / g.startOffset
+		factor = dprec.Max(snap(factor, g.snapping.Scale), 0.001)
+		// NOTE: Scaling is always applied along the axes of the node, since
+		// a non-uniform scale along world axes would introduce shearing.
+		scale := dprec.NewVec3(1.0, 1.0, 1.0)
+		switch g.activeAxis {
+		case 0:
+			scale.X = factor
+		case 1:
+			scale.Y = factor
+		case 2:
+			scale.Z = factor
+		}
+		matrix = dprec.Mat4Prod(
+			g.startMatrix,
+			dprec.ScaleMat4(scale.X, scale.Y, scale.Z),
+		)
+	}
+	g.target.SetAbsoluteMatrix(matrix)
+}
+
+// EndDrag stops dragging the active handle.
+func (g *TransformGizmo) EndDrag() {
+	g.activeAxis = noAxis
+}
+
+// CancelDrag stops dragging the active handle and restores the target
+// to its state from before the drag.
+func (g *TransformGizmo) CancelDrag() {
+	if g.activeAxis == noAxis {
+		return
+	}
+	g.target.SetAbsoluteMatrix(g.startMatrix)
+	g.activeAxis = noAxis
+}
+
+// Delete removes the handles from the scene.
+func (g *TransformGizmo) Delete() {
+	for axis := range 3 {
+		g.translateHandles[axis].Delete()
+		g.rotateHandles[axis].Delete()
+		g.scaleHandles[axis].Delete()
+	}
+}
+
+func (g *TransformGizmo) refreshHandles() {
+	visible := g.target != nil
+	for axis := range 3 {
+		g.translateHandles[axis].SetActive(visible && g.mode == TransformModeTranslate)
+		g.rotateHandles[axis].SetActive(visible && g.mode == TransformModeRotate)
+		g.scaleHandles[axis].SetActive(visible && g.mode == TransformModeScale)
+	}
+}
+
+// spaceMatrix returns the rotation that aligns the handles with the
+// current transform space.
+func (g *TransformGizmo) spaceMatrix(matrix dprec.Mat4) dprec.Mat4 {
+	if g.space == TransformSpaceWorld && g.mode != TransformModeScale {
+		return dprec.IdentityMat4()
+	}
+	return dprec.OrientationMat4(
+		dprec.UnitVec3(matrix.OrientationX()),
+		dprec.UnitVec3(matrix.OrientationY()),
+		dprec.UnitVec3(matrix.OrientationZ()),
+	)
+}
+
+func (g *TransformGizmo) axisDirection(axis int) dprec.Vec3 {
+	space := g.spaceMatrix(g.startMatrix)
+	switch axis {
+	case 0:
+		return space.OrientationX()
+	case 1:
+		return space.OrientationY()
+	default:
+		return space.OrientationZ()
+	}
+}
+
+func (g *TransformGizmo) pickAxis(ray Ray) int {
+	center := g.startMatrix.Translation()
+	tolerance := g.handleSize * handlePickTolerance
+
+	bestAxis := noAxis
+	bestDistance := math.Inf(1)
+	for axis := range 3 {
+		direction := g.axisDirection(axis)
+		var distance float64
+		switch g.mode {
+		case TransformModeTranslate, TransformModeScale:
+			offset := g.axisOffset(ray, axis)
+			if offset < 0.0 || offset > g.handleSize {
+				continue
+			}
+			axisPoint := dprec.Vec3Sum(center, dprec.Vec3Prod(direction, offset))
+			distance = rayPointDistance(ray, axisPoint)
+		case TransformModeRotate:
+			point, ok := g.planeIntersection(ray, axis)
+			if !ok {
+				continue
+			}
+			distance = math.Abs(dprec.Vec3Diff(point, center).Length() - g.handleSize)
+		}
+		if distance < tolerance && distance < bestDistance {
+			bestAxis = axis
+			bestDistance = distance
+		}
+	}
+	return bestAxis
+}
+
+// axisOffset returns the position along the specified axis, relative to
+// the drag origin, that is closest to the ray.
+func (g *TransformGizmo) axisOffset(ray Ray, axis int) float64 {
+	center := g.startMatrix.Translation()
+	direction := g.axisDirection(axis)
+
+	// Closest points between two lines.
+	w := dprec.Vec3Diff(center, ray.Origin)
+	b := dprec.Vec3Dot(direction, ray.Direction)
+	d := dprec.Vec3Dot(direction, w)
+	e := dprec.Vec3Dot(ray.Direction, w)
+	denominator := 1.0 - b*b
+	if denominator < 0.0001 {
+		return 0.0 // ray is parallel to the axis
+	}
+	return (b*e - d) / denominator
+}
+
+// planeIntersection returns the point where the ray hits the plane that
+// passes through the drag origin and is perpendicular to the axis.
+func (g *TransformGizmo) planeIntersection(ray Ray, axis int) (dprec.Vec3, bool) {
+	center := g.startMatrix.Translation()
+	normal := g.axisDirection(axis)
+	denominator := dprec.Vec3Dot(normal, ray.Direction)
+	if math.Abs(denominator) < 0.0001 {
+		return dprec.Vec3{}, false
+	}
+	distance := dprec.Vec3Dot(normal, dprec.Vec3Diff(center, ray.Origin)) / denominator
+	if distance < 0.0 {
+		return dprec.Vec3{}, false
+	}
+	return dprec.Vec3Sum(ray.Origin, dprec.Vec3Prod(ray.Direction, distance)), true
+}
+
+func rayPointDistance(ray Ray, point dprec.Vec3) float64 {
+	offset := dprec.Vec3Diff(point, ray.Origin)
+	projection := dprec.Vec3Dot(offset, ray.Direction)
+	closest := dprec.Vec3Sum(ray.Origin, dprec.Vec3Prod(ray.Direction, projection))
+	return dprec.Vec3Diff(point, closest).Length()
+}
+
+func signedAngle(from, to, axis dprec.Vec3) dprec.Angle {
+	cross := dprec.Vec3Cross(from, to)
+	return dprec.Angle(math.Atan2(dprec.Vec3Dot(cross, axis), dprec.Vec3Dot(from, to)))
+}
+
+func snap(value, increment float64) float64 {
+	if increment <= 0.0 {
+		return value
+	}
+	return math.Round(value/increment) * increment
+}
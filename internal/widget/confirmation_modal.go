@@ -2,6 +2,7 @@ package widget
 
 import (
 	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/theme"
 	"github.com/mokiat/lacking/ui"
 	co "github.com/mokiat/lacking/ui/component"
 	"github.com/mokiat/lacking/ui/layout"
@@ -76,7 +77,7 @@ func (c *confirmationModalComponent) Render() co.Instance {
 					})
 					co.WithData(std.PictureData{
 						Image:      c.icon,
-						ImageColor: opt.V(ui.Black()),
+						ImageColor: opt.V(theme.OnSurface()),
 						Mode:       std.ImageModeFit,
 					})
 				}))
@@ -85,7 +86,7 @@ func (c *confirmationModalComponent) Render() co.Instance {
 					co.WithData(std.LabelData{
 						Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
 						FontSize:  opt.V(float32(20)),
-						FontColor: opt.V(std.OnSurfaceColor),
+						FontColor: opt.V(theme.OnSurface()),
 						Text:      c.text,
 					})
 				}))
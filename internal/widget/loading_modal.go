@@ -2,6 +2,7 @@ package widget
 
 import (
 	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/theme"
 	"github.com/mokiat/lacking/ui"
 	co "github.com/mokiat/lacking/ui/component"
 	"github.com/mokiat/lacking/ui/layout"
@@ -10,16 +11,53 @@ import (
 
 var LoadingModal = co.Define(&loadingModalComponent{})
 
+type LoadingModalData struct {
+	// Text replaces the default loading message, if specified.
+	Text string
+
+	// Detail is an optional secondary message below the text.
+	Detail string
+
+	// Progress is the completed portion of the work in the range
+	// [0.0, 1.0]. No progress bar is displayed if it is not specified.
+	Progress opt.T[float64]
+}
+
+type LoadingModalCallbackData struct {
+	// OnCancel is called when the Cancel button is clicked. No Cancel
+	// button is displayed if it is not specified.
+	OnCancel func()
+}
+
 type loadingModalComponent struct {
 	co.BaseComponent
 
 	icon *ui.Image
+
+	text     string
+	detail   string
+	progress opt.T[float64]
+
+	onCancel func()
 }
 
 func (c *loadingModalComponent) OnCreate() {
 	c.icon = co.OpenImage(c.Scope(), "icons/info.png")
 }
 
+func (c *loadingModalComponent) OnUpsert() {
+	data := co.GetOptionalData(c.Properties(), LoadingModalData{})
+	c.text = data.Text
+	if c.text == "" {
+		c.text = "Loading..."
+	}
+	c.detail = data.Detail
+	c.progress = data.Progress
+
+	callbackData := co.GetOptionalCallbackData(c.Properties(), LoadingModalCallbackData{})
+	c.onCancel = callbackData.OnCancel
+}
+
 func (c *loadingModalComponent) Render() co.Instance {
 	return co.New(std.Modal, func() {
 		co.WithLayoutData(layout.Data{
@@ -56,20 +94,77 @@ func (c *loadingModalComponent) Render() co.Instance {
 					})
 					co.WithData(std.PictureData{
 						Image:      c.icon,
-						ImageColor: opt.V(ui.Black()),
+						ImageColor: opt.V(theme.OnSurface()),
 						Mode:       std.ImageModeFit,
 					})
 				}))
 
-				co.WithChild("text", co.New(std.Label, func() {
-					co.WithData(std.LabelData{
-						Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
-						FontSize:  opt.V(float32(20)),
-						FontColor: opt.V(std.OnSurfaceColor),
-						Text:      "Loading...",
+				co.WithChild("status", co.New(std.Element, func() {
+					co.WithData(std.ElementData{
+						Padding: ui.Spacing{
+							Right: 20,
+						},
+						Layout: layout.Vertical(layout.VerticalSettings{
+							ContentAlignment: layout.HorizontalAlignmentLeft,
+							ContentSpacing:   10,
+						}),
 					})
+
+					co.WithChild("text", co.New(std.Label, func() {
+						co.WithData(std.LabelData{
+							Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
+							FontSize:  opt.V(float32(20)),
+							FontColor: opt.V(theme.OnSurface()),
+							Text:      c.text,
+						})
+					}))
+
+					if c.detail != "" {
+						co.WithChild("detail", co.New(std.Label, func() {
+							co.WithData(std.LabelData{
+								Font:      co.OpenFont(c.Scope(), "ui:///roboto-italic.ttf"),
+								FontSize:  opt.V(float32(16)),
+								FontColor: opt.V(theme.OnSurface()),
+								Text:      c.detail,
+							})
+						}))
+					}
+
+					if c.progress.Specified {
+						co.WithChild("progress", co.New(ProgressBar, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(ProgressBarData{
+								Value: c.progress.Value,
+							})
+						}))
+					}
 				}))
 			}))
+
+			if c.onCancel != nil {
+				co.WithChild("footer", co.New(std.Toolbar, func() {
+					co.WithLayoutData(layout.Data{
+						VerticalAlignment: layout.VerticalAlignmentBottom,
+					})
+					co.WithData(std.ToolbarData{
+						Positioning: std.ToolbarPositioningBottom,
+					})
+
+					co.WithChild("cancel", co.New(std.ToolbarButton, func() {
+						co.WithData(std.ToolbarButtonData{
+							Text: "Cancel",
+						})
+						co.WithLayoutData(layout.Data{
+							HorizontalAlignment: layout.HorizontalAlignmentRight,
+						})
+						co.WithCallbackData(std.ToolbarButtonCallbackData{
+							OnClick: c.onCancel,
+						})
+					}))
+				}))
+			}
 		}))
 	})
 }
@@ -2,6 +2,7 @@ package widget
 
 import (
 	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/theme"
 	"github.com/mokiat/lacking/ui"
 	co "github.com/mokiat/lacking/ui/component"
 	"github.com/mokiat/lacking/ui/layout"
@@ -13,19 +14,37 @@ var NotificationModal = co.Define(&notificationModalComponent{})
 type NotificationModalData struct {
 	Icon *ui.Image
 	Text string
+
+	// ActionText is the label of an optional button that performs a
+	// follow-up action and closes the modal.
+	ActionText string
+}
+
+type NotificationModalCallbackData struct {
+	OnAction func()
 }
 
 type notificationModalComponent struct {
 	co.BaseComponent
 
-	icon *ui.Image
-	text string
+	icon       *ui.Image
+	text       string
+	actionText string
+
+	onAction func()
 }
 
 func (c *notificationModalComponent) OnCreate() {
 	data := co.GetData[NotificationModalData](c.Properties())
 	c.icon = data.Icon
 	c.text = data.Text
+	c.actionText = data.ActionText
+
+	callbackData := co.GetOptionalCallbackData(c.Properties(), NotificationModalCallbackData{})
+	c.onAction = callbackData.OnAction
+	if c.onAction == nil {
+		c.onAction = func() {}
+	}
 }
 
 func (c *notificationModalComponent) Render() co.Instance {
@@ -64,7 +83,7 @@ func (c *notificationModalComponent) Render() co.Instance {
 					})
 					co.WithData(std.PictureData{
 						Image:      c.icon,
-						ImageColor: opt.V(ui.Black()),
+						ImageColor: opt.V(theme.OnSurface()),
 						Mode:       std.ImageModeFit,
 					})
 				}))
@@ -73,7 +92,7 @@ func (c *notificationModalComponent) Render() co.Instance {
 					co.WithData(std.LabelData{
 						Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
 						FontSize:  opt.V(float32(20)),
-						FontColor: opt.V(std.OnSurfaceColor),
+						FontColor: opt.V(theme.OnSurface()),
 						Text:      c.text,
 					})
 				}))
@@ -98,6 +117,20 @@ func (c *notificationModalComponent) Render() co.Instance {
 						OnClick: c.onClose,
 					})
 				}))
+
+				if c.actionText != "" {
+					co.WithChild("action", co.New(std.ToolbarButton, func() {
+						co.WithData(std.ToolbarButtonData{
+							Text: c.actionText,
+						})
+						co.WithLayoutData(layout.Data{
+							HorizontalAlignment: layout.HorizontalAlignmentRight,
+						})
+						co.WithCallbackData(std.ToolbarButtonCallbackData{
+							OnClick: c.handleAction,
+						})
+					}))
+				}
 			}))
 		}))
 	})
@@ -106,3 +139,8 @@ func (c *notificationModalComponent) Render() co.Instance {
 func (c *notificationModalComponent) onClose() {
 	co.CloseOverlay(c.Scope())
 }
+
+func (c *notificationModalComponent) handleAction() {
+	co.CloseOverlay(c.Scope())
+	c.onAction()
+}
@@ -0,0 +1,60 @@
+package widget
+
+import (
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/ui"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+const ProgressBarHeight = 8
+
+// ProgressBar displays a value in the range [0.0, 1.0] as a partially
+// filled track.
+var ProgressBar = co.Define(&progressBarComponent{})
+
+type ProgressBarData struct {
+	Value float64
+}
+
+type progressBarComponent struct {
+	co.BaseComponent
+
+	value float64
+}
+
+func (c *progressBarComponent) OnUpsert() {
+	data := co.GetOptionalData(c.Properties(), ProgressBarData{})
+	c.value = min(max(data.Value, 0.0), 1.0)
+}
+
+func (c *progressBarComponent) Render() co.Instance {
+	return co.New(std.Element, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ElementData{
+			Essence:   c,
+			IdealSize: opt.V(ui.NewSize(ProgressBarHeight, ProgressBarHeight)),
+		})
+	})
+}
+
+func (c *progressBarComponent) OnRender(element *ui.Element, canvas *ui.Canvas) {
+	drawBounds := canvas.DrawBounds(element, false)
+
+	canvas.Reset()
+	canvas.Rectangle(sprec.ZeroVec2(), drawBounds.Size)
+	canvas.Fill(ui.Fill{
+		Color: theme.Outline(),
+	})
+
+	canvas.Reset()
+	canvas.Rectangle(
+		sprec.ZeroVec2(),
+		sprec.NewVec2(float32(c.value)*drawBounds.Width(), drawBounds.Height()),
+	)
+	canvas.Fill(ui.Fill{
+		Color: theme.Primary(),
+	})
+}
@@ -0,0 +1,168 @@
+package widget
+
+import (
+	"strings"
+
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/lacking-studio/internal/keymap"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/ui"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/layout"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+var ShortcutsModal = co.Define(&shortcutsModalComponent{})
+
+type ShortcutsModalData struct {
+	Keymap *keymap.Keymap
+}
+
+type ShortcutsModalCallbackData struct {
+	OnClose func()
+}
+
+type shortcutsModalComponent struct {
+	co.BaseComponent
+
+	keymap *keymap.Keymap
+
+	onCloseCallback func()
+}
+
+func (c *shortcutsModalComponent) OnCreate() {
+	data := co.GetData[ShortcutsModalData](c.Properties())
+	c.keymap = data.Keymap
+
+	callbackData := co.GetOptionalCallbackData(c.Properties(), ShortcutsModalCallbackData{})
+	c.onCloseCallback = callbackData.OnClose
+	if c.onCloseCallback == nil {
+		c.onCloseCallback = func() {}
+	}
+}
+
+func (c *shortcutsModalComponent) Render() co.Instance {
+	return co.New(std.Modal, func() {
+		co.WithLayoutData(layout.Data{
+			Width:            opt.V(500),
+			Height:           opt.V(500),
+			HorizontalCenter: opt.V(0),
+			VerticalCenter:   opt.V(0),
+		})
+
+		co.WithChild("dialog", co.New(std.Element, func() {
+			co.WithData(std.ElementData{
+				Layout: layout.Frame(layout.FrameSettings{
+					ContentSpacing: ui.SymmetricSpacing(0, 20),
+				}),
+			})
+
+			co.WithChild("title", co.New(std.Label, func() {
+				co.WithLayoutData(layout.Data{
+					VerticalAlignment:   layout.VerticalAlignmentTop,
+					HorizontalAlignment: layout.HorizontalAlignmentCenter,
+				})
+				co.WithData(std.LabelData{
+					Font:      co.OpenFont(c.Scope(), "ui:///roboto-bold.ttf"),
+					FontSize:  opt.V(float32(22)),
+					FontColor: opt.V(theme.OnSurface()),
+					Text:      "Keyboard Shortcuts",
+				})
+			}))
+
+			co.WithChild("scroll-pane", co.New(std.ScrollPane, func() {
+				co.WithLayoutData(layout.Data{
+					VerticalAlignment: layout.VerticalAlignmentCenter,
+				})
+				co.WithData(std.ScrollPaneData{
+					DisableHorizontal: true,
+				})
+
+				co.WithChild("list", co.New(std.Element, func() {
+					co.WithLayoutData(layout.Data{
+						Width: opt.V(460),
+					})
+					co.WithData(std.ElementData{
+						Padding: ui.UniformSpacing(10),
+						Layout: layout.Vertical(layout.VerticalSettings{
+							ContentAlignment: layout.HorizontalAlignmentLeft,
+							ContentSpacing:   10,
+						}),
+					})
+
+					for _, entry := range c.keymap.Entries() {
+						co.WithChild(string(entry.Action), co.New(std.Element, func() {
+							co.WithLayoutData(layout.Data{
+								GrowHorizontally: true,
+							})
+							co.WithData(std.ElementData{
+								Layout: layout.Frame(),
+							})
+
+							co.WithChild("description", co.New(std.Label, func() {
+								co.WithLayoutData(layout.Data{
+									HorizontalAlignment: layout.HorizontalAlignmentLeft,
+								})
+								co.WithData(std.LabelData{
+									Font:      co.OpenFont(c.Scope(), "ui:///roboto-regular.ttf"),
+									FontSize:  opt.V(float32(18)),
+									FontColor: opt.V(theme.OnSurface()),
+									Text:      entry.Description,
+								})
+							}))
+
+							co.WithChild("bindings", co.New(std.Label, func() {
+								co.WithLayoutData(layout.Data{
+									HorizontalAlignment: layout.HorizontalAlignmentRight,
+								})
+								co.WithData(std.LabelData{
+									Font:      co.OpenFont(c.Scope(), "ui:///roboto-bold.ttf"),
+									FontSize:  opt.V(float32(18)),
+									FontColor: opt.V(theme.OnSurface()),
+									Text:      c.bindingsText(entry.Bindings),
+								})
+							}))
+						}))
+					}
+				}))
+			}))
+
+			co.WithChild("footer", co.New(std.Toolbar, func() {
+				co.WithLayoutData(layout.Data{
+					VerticalAlignment: layout.VerticalAlignmentBottom,
+				})
+				co.WithData(std.ToolbarData{
+					Positioning: std.ToolbarPositioningBottom,
+				})
+
+				co.WithChild("close", co.New(std.ToolbarButton, func() {
+					co.WithData(std.ToolbarButtonData{
+						Text: "Close",
+					})
+					co.WithLayoutData(layout.Data{
+						HorizontalAlignment: layout.HorizontalAlignmentRight,
+					})
+					co.WithCallbackData(std.ToolbarButtonCallbackData{
+						OnClick: c.onClose,
+					})
+				}))
+			}))
+		}))
+	})
+}
+
+func (c *shortcutsModalComponent) bindingsText(bindings []keymap.Binding) string {
+	if len(bindings) == 0 {
+		return "-"
+	}
+	texts := make([]string, len(bindings))
+	for i, binding := range bindings {
+		texts[i] = binding.String()
+	}
+	return strings.Join(texts, ", ")
+}
+
+func (c *shortcutsModalComponent) onClose() {
+	co.CloseOverlay(c.Scope())
+	c.onCloseCallback()
+}
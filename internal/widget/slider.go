@@ -0,0 +1,123 @@
+package widget
+
+import (
+	"github.com/mokiat/gog/opt"
+	"github.com/mokiat/gomath/sprec"
+	"github.com/mokiat/lacking-studio/internal/theme"
+	"github.com/mokiat/lacking/ui"
+	co "github.com/mokiat/lacking/ui/component"
+	"github.com/mokiat/lacking/ui/std"
+)
+
+const (
+	SliderHeight     = 24
+	SliderTrackSize  = 4
+	SliderHandleSize = 8
+)
+
+// Slider allows a value in the range [0.0, 1.0] to be picked by dragging.
+var Slider = co.Define(&sliderComponent{})
+
+type SliderData struct {
+	Value float64
+}
+
+type SliderCallbackData struct {
+	OnChange func(value float64)
+}
+
+type sliderComponent struct {
+	co.BaseComponent
+
+	value    float64
+	dragging bool
+
+	onChange func(float64)
+}
+
+func (c *sliderComponent) OnUpsert() {
+	data := co.GetOptionalData(c.Properties(), SliderData{})
+	c.value = min(max(data.Value, 0.0), 1.0)
+
+	callbackData := co.GetOptionalCallbackData(c.Properties(), SliderCallbackData{})
+	c.onChange = callbackData.OnChange
+	if c.onChange == nil {
+		c.onChange = func(float64) {}
+	}
+}
+
+func (c *sliderComponent) Render() co.Instance {
+	return co.New(std.Element, func() {
+		co.WithLayoutData(c.Properties().LayoutData())
+		co.WithData(std.ElementData{
+			Essence:   c,
+			IdealSize: opt.V(ui.NewSize(SliderHeight, SliderHeight)),
+		})
+	})
+}
+
+func (c *sliderComponent) OnMouseEvent(element *ui.Element, event ui.MouseEvent) bool {
+	switch event.Action {
+	case ui.MouseActionDown:
+		if event.Button != ui.MouseButtonLeft {
+			return false
+		}
+		c.dragging = true
+		c.changeValue(element, event.X)
+		return true
+	case ui.MouseActionMove:
+		if !c.dragging {
+			return false
+		}
+		c.changeValue(element, event.X)
+		return true
+	case ui.MouseActionUp:
+		if !c.dragging {
+			return false
+		}
+		c.dragging = false
+		return true
+	case ui.MouseActionLeave:
+		c.dragging = false
+		return false
+	default:
+		return false
+	}
+}
+
+func (c *sliderComponent) OnRender(element *ui.Element, canvas *ui.Canvas) {
+	drawBounds := canvas.DrawBounds(element, false)
+	centerY := drawBounds.Height() / 2.0
+	handleX := float32(c.value) * drawBounds.Width()
+
+	canvas.Reset()
+	canvas.Rectangle(
+		sprec.NewVec2(0.0, centerY-SliderTrackSize/2.0),
+		sprec.NewVec2(drawBounds.Width(), SliderTrackSize),
+	)
+	canvas.Fill(ui.Fill{
+		Color: theme.Outline(),
+	})
+
+	canvas.Reset()
+	canvas.Rectangle(
+		sprec.NewVec2(handleX-SliderHandleSize/2.0, 0.0),
+		sprec.NewVec2(SliderHandleSize, drawBounds.Height()),
+	)
+	canvas.Fill(ui.Fill{
+		Color: theme.Primary(),
+	})
+}
+
+func (c *sliderComponent) changeValue(element *ui.Element, x int) {
+	width := element.Bounds().Width
+	if width <= 0 {
+		return
+	}
+	value := min(max(float64(x)/float64(width), 0.0), 1.0)
+	if value != c.value {
+		c.value = value
+		c.onChange(value)
+		element.Invalidate()
+	}
+}
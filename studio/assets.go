@@ -0,0 +1,44 @@
+package studio
+
+import (
+	"fmt"
+
+	"github.com/mokiat/lacking-studio/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// assetsFlags allow the assets of the project config to be overridden,
+// for example to inspect the archive of a release build.
+var assetsFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "storage",
+		Usage: "storage of the assets (fs, archive, http or memory)",
+	},
+	&cli.StringFlag{
+		Name:  "assets",
+		Usage: "path or URL of the assets, depending on the storage",
+	},
+}
+
+// loadProject loads the project config and applies the assets flags, if
+// the command has them.
+func loadProject(ctx *cli.Context, projectDir string) (config.Project, error) {
+	project, err := config.LoadProject(projectDir)
+	if err != nil {
+		return project, fmt.Errorf("error loading project config: %w", err)
+	}
+	if storage := ctx.String("storage"); storage != "" {
+		project.Assets.Storage = config.StorageKind(storage)
+	}
+	if assets := ctx.String("assets"); assets != "" {
+		if project.Assets.Storage == config.StorageHTTP {
+			project.Assets.URL = assets
+		} else {
+			project.Assets.Path = assets
+		}
+	}
+	if err := project.Validate(); err != nil {
+		return project, fmt.Errorf("invalid assets flags: %w", err)
+	}
+	return project, nil
+}
@@ -0,0 +1,79 @@
+package studio
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+
+	"github.com/mokiat/lacking-studio/internal/bundle"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/urfave/cli/v2"
+)
+
+func runBundleApplication(ctx *cli.Context) error {
+	projectDir := cmp.Or(ctx.Args().First(), ".")
+
+	project, err := loadProject(ctx, projectDir)
+	if err != nil {
+		return err
+	}
+	storage, err := project.NewStorage(projectDir)
+	if err != nil {
+		return fmt.Errorf("error creating storage: %w", err)
+	}
+	formatter, err := project.NewFormatter()
+	if err != nil {
+		return err
+	}
+	registry, err := asset.NewRegistry(storage, formatter)
+	if err != nil {
+		return fmt.Errorf("error creating registry: %w", err)
+	}
+
+	resources, err := bundle.Select(registry, bundle.Selection{
+		Patterns: ctx.StringSlice("include"),
+		Models:   ctx.StringSlice("model"),
+	})
+	if err != nil {
+		return fmt.Errorf("error selecting resources: %w", err)
+	}
+	if len(resources) == 0 {
+		return fmt.Errorf("no resources match the selection")
+	}
+
+	output := ctx.String("output")
+	manifest, err := bundle.Write(output, storage, formatter, string(project.Assets.Formatter), resources)
+	if err != nil {
+		return fmt.Errorf("error writing bundle: %w", err)
+	}
+	fmt.Fprintf(os.Stdout, "Bundled %d resources (%d bytes) into %s\n", len(manifest.Resources), manifest.Size(), output)
+	return nil
+}
+
+func runUnbundleApplication(ctx *cli.Context) error {
+	bundlePath := ctx.Args().First()
+	if bundlePath == "" {
+		return fmt.Errorf("missing bundle file")
+	}
+	projectDir := cmp.Or(ctx.Args().Get(1), ".")
+
+	project, err := loadProject(ctx, projectDir)
+	if err != nil {
+		return err
+	}
+	storage, err := project.NewStorage(projectDir)
+	if err != nil {
+		return fmt.Errorf("error creating storage: %w", err)
+	}
+	formatter, err := project.NewFormatter()
+	if err != nil {
+		return err
+	}
+
+	manifest, err := bundle.Extract(bundlePath, storage, formatter, string(project.Assets.Formatter))
+	if err != nil {
+		return fmt.Errorf("error extracting bundle: %w", err)
+	}
+	fmt.Fprintf(os.Stdout, "Extracted %d resources (%d bytes) from %s\n", len(manifest.Resources), manifest.Size(), bundlePath)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	nativeui "github.com/mokiat/lacking-native/ui"
 	"github.com/mokiat/lacking-studio/internal"
 	"github.com/mokiat/lacking-studio/internal/global"
+	"github.com/mokiat/lacking-studio/internal/logs"
 	"github.com/mokiat/lacking-studio/internal/preview/view"
 	"github.com/mokiat/lacking-studio/resources"
 	"github.com/mokiat/lacking/app"
@@ -21,12 +22,21 @@ import (
 func runEditorApplication(ctx *cli.Context) error {
 	projectDir := cmp.Or(ctx.Args().First(), ".")
 
-	registry, err := createRegistry(projectDir)
+	project, err := loadProject(ctx, projectDir)
+	if err != nil {
+		return err
+	}
+
+	registry, overrides, err := project.NewOverrideRegistry(projectDir)
 	if err != nil {
 		return fmt.Errorf("error creating registry: %w", err)
 	}
 
 	globalController := global.NewController(
+		projectDir,
+		project,
+		logs.NewBuffer(),
+		overrides,
 		game.NewController(
 			registry,
 			nativegame.NewShaderCollection(),
@@ -39,7 +49,7 @@ func runEditorApplication(ctx *cli.Context) error {
 		internal.BootstrapApplication(window, globalController, view.Root)
 	})
 
-	cfg := nativeapp.NewConfig("Lacking Studio [Editor Mode]", 1280, 800)
+	cfg := nativeapp.NewConfig("Lacking Studio [Editor Mode]", project.Window.Width, project.Window.Height)
 	cfg.SetMaximized(true)
 	cfg.SetMinSize(1024, 768)
 	cfg.SetVSync(true)
@@ -0,0 +1,81 @@
+package studio
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mokiat/lacking-studio/internal/depgraph"
+	"github.com/mokiat/lacking-studio/internal/storage"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/urfave/cli/v2"
+)
+
+func runGCApplication(ctx *cli.Context) error {
+	projectDir := cmp.Or(ctx.Args().First(), ".")
+	dryRun := ctx.Bool("dry-run")
+
+	project, err := loadProject(ctx, projectDir)
+	if err != nil {
+		return err
+	}
+	rootPatterns := project.Roots
+	if flagRoots := ctx.StringSlice("root"); len(flagRoots) > 0 {
+		rootPatterns = flagRoots
+	}
+	if len(rootPatterns) == 0 {
+		return errors.New("no root models specified (use --root or the roots of the project config)")
+	}
+
+	assetsStorage, err := project.NewStorage(projectDir)
+	if err != nil {
+		return fmt.Errorf("error creating storage: %w", err)
+	}
+	formatter, err := project.NewFormatter()
+	if err != nil {
+		return err
+	}
+	registry, err := asset.NewRegistry(assetsStorage, formatter)
+	if err != nil {
+		return fmt.Errorf("error creating registry: %w", err)
+	}
+
+	// NOTE: A root that matches nothing is most likely a typo and would
+	// otherwise cause the model that it refers to to be deleted.
+	var roots []*asset.Resource
+	for _, pattern := range rootPatterns {
+		matches, err := depgraph.Match(registry.Resources(), pattern)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("root %q does not match any resource", pattern)
+		}
+		roots = append(roots, matches...)
+	}
+
+	unreachable := depgraph.Unreachable(registry.Resources(), roots)
+
+	var reclaimed int64
+	for _, resource := range unreachable {
+		size, err := storage.ContentSize(assetsStorage, resource.ID())
+		if err != nil && !errors.Is(err, asset.ErrNotFound) {
+			return fmt.Errorf("error reading content of %q: %w", resource.Name(), err)
+		}
+		fmt.Fprintf(os.Stdout, "%s\t%s\t%d\n", resource.ID(), resource.Name(), size)
+		if !dryRun {
+			if err := resource.Delete(); err != nil {
+				return fmt.Errorf("error deleting %q: %w", resource.Name(), err)
+			}
+		}
+		reclaimed += size
+	}
+
+	if dryRun {
+		fmt.Fprintf(os.Stdout, "Would delete %d of %d resources (%d bytes)\n", len(unreachable), len(registry.Resources()), reclaimed)
+	} else {
+		fmt.Fprintf(os.Stdout, "Deleted %d resources (%d bytes)\n", len(unreachable), reclaimed)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package studio
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+
+	"github.com/mokiat/lacking-studio/internal/depgraph"
+	"github.com/mokiat/lacking/game/asset"
+	"github.com/urfave/cli/v2"
+)
+
+func runGraphApplication(ctx *cli.Context) error {
+	projectDir := cmp.Or(ctx.Args().First(), ".")
+	format, err := depgraph.ParseFormat(ctx.String("format"))
+	if err != nil {
+		return err
+	}
+
+	project, err := loadProject(ctx, projectDir)
+	if err != nil {
+		return err
+	}
+	storage, err := project.NewStorage(projectDir)
+	if err != nil {
+		return fmt.Errorf("error creating storage: %w", err)
+	}
+	formatter, err := project.NewFormatter()
+	if err != nil {
+		return err
+	}
+	registry, err := asset.NewRegistry(storage, formatter)
+	if err != nil {
+		return fmt.Errorf("error creating registry: %w", err)
+	}
+
+	var graph depgraph.Graph
+	if resourceRef := ctx.String("from"); resourceRef != "" {
+		resource := registry.ResourceByID(resourceRef)
+		if resource == nil {
+			resource = registry.ResourceByName(resourceRef)
+		}
+		if resource == nil {
+			return fmt.Errorf("model %q not found", resourceRef)
+		}
+		graph = depgraph.Reachable(resource)
+	} else {
+		graph = depgraph.New(registry.Resources())
+	}
+	if err := graph.LoadSizes(storage); err != nil {
+		return err
+	}
+
+	text, err := graph.Text(format)
+	if err != nil {
+		return err
+	}
+	if output := ctx.String("output"); output != "" {
+		if err := os.WriteFile(output, []byte(text), 0664); err != nil {
+			return fmt.Errorf("error writing graph file: %w", err)
+		}
+		return nil
+	}
+	_, err = fmt.Fprint(os.Stdout, text)
+	return err
+}
@@ -0,0 +1,44 @@
+package studio
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mokiat/lacking-studio/internal/config"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/urfave/cli/v2"
+)
+
+func runInitApplication(ctx *cli.Context) error {
+	projectDir := cmp.Or(ctx.Args().First(), ".")
+
+	var fileName string
+	switch format := ctx.String("format"); format {
+	case "toml":
+		fileName = config.FileNameTOML
+	case "json":
+		fileName = config.FileNameJSON
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+
+	if existing := config.ProjectFile(projectDir); existing != "" && !ctx.Bool("force") {
+		return fmt.Errorf("project already has config file %q (use --force to overwrite)", existing)
+	}
+
+	project := config.DefaultProject()
+	project.Watch = []string{"*.go", "resources/*"}
+
+	path := filepath.Join(projectDir, fileName)
+	if err := config.SaveProject(path, project); err != nil {
+		return fmt.Errorf("error saving project config: %w", err)
+	}
+	fmt.Fprintf(os.Stdout, "Created %s\n", path)
+
+	if existing := config.ProjectFile(projectDir); existing != path {
+		log.Warn("The new config file is ignored, since %q takes precedence over it", existing)
+	}
+	return nil
+}
@@ -2,27 +2,90 @@ package studio
 
 import (
 	"cmp"
+	"encoding/json"
 	"fmt"
+	"os"
 
-	"github.com/mokiat/lacking/game/asset/dsl"
+	"github.com/mokiat/lacking-studio/internal/config"
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking-studio/internal/pack"
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/mokiat/lacking/game/asset"
 	"github.com/urfave/cli/v2"
 )
 
 func runPackApplication(ctx *cli.Context) error {
 	projectDir := cmp.Or(ctx.Args().First(), ".")
+	modelName := ctx.Args().Get(1)
 
-	var modelNames []string
-	if modelName := ctx.Args().Get(1); modelName != "" {
-		modelNames = append(modelNames, modelName)
+	format := ctx.String("format")
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unsupported format %q", format)
 	}
 
-	registry, err := createRegistry(projectDir)
+	logBuffer := logs.NewBuffer()
+	if err := logBuffer.CaptureStderr(); err != nil {
+		log.Warn("Error capturing logs: %v", err)
+		logBuffer = nil
+	}
+
+	project, err := loadProject(ctx, projectDir)
 	if err != nil {
-		return fmt.Errorf("error creating registry: %w", err)
+		return err
+	}
+
+	// NOTE: A configured pack command usually ends up running this command,
+	// so the DSL is run in-process unless a pack server is configured.
+	var packer model.Packer
+	if project.Pack.Kind == config.PackKindRemote {
+		packer = model.NewRemotePacker(project.Pack.URL)
+	} else {
+		packer = newDSLPacker(project, projectDir, logBuffer)
+	}
+
+	diagnostics, packErr := packer.Pack(ctx.Context, modelName, model.PackOutput{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+
+	if path := os.Getenv(pack.EnvDiagnosticsFile); path != "" {
+		if err := pack.WriteFile(path, diagnostics); err != nil {
+			log.Warn("Error writing diagnostics: %v", err)
+		}
 	}
-	if err := dsl.Run(registry, modelNames); err != nil {
-		return fmt.Errorf("error running DSL: %w", err)
+
+	switch format {
+	case "json":
+		if diagnostics == nil {
+			diagnostics = []pack.Diagnostic{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diagnostics); err != nil {
+			return fmt.Errorf("error encoding diagnostics: %w", err)
+		}
+	default:
+		for _, diagnostic := range diagnostics {
+			fmt.Fprintln(os.Stderr, diagnostic.String())
+		}
+		if logBuffer != nil {
+			// Make sure the output reaches the terminal before exiting.
+			logBuffer.Sync()
+		}
 	}
 
+	if pack.HasErrors(diagnostics) {
+		return cli.Exit("", 1)
+	}
+	if packErr != nil {
+		return fmt.Errorf("error packing assets: %w", packErr)
+	}
 	return nil
 }
+
+func newDSLPacker(project config.Project, projectDir string, logBuffer *logs.Buffer) *model.DSLPacker {
+	return model.NewDSLPacker(projectDir, func() (*asset.Registry, error) {
+		return project.NewRegistry(projectDir)
+	}, logBuffer)
+}
@@ -0,0 +1,48 @@
+package studio
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking-studio/internal/pack"
+	"github.com/mokiat/lacking-studio/internal/preview/model"
+	"github.com/mokiat/lacking/debug/log"
+	"github.com/urfave/cli/v2"
+)
+
+func runPackServerApplication(ctx *cli.Context) error {
+	projectDir := cmp.Or(ctx.Args().First(), ".")
+
+	logBuffer := logs.NewBuffer()
+	if err := logBuffer.CaptureStderr(); err != nil {
+		log.Warn("Error capturing logs: %v", err)
+		logBuffer = nil
+	}
+
+	project, err := loadProject(ctx, projectDir)
+	if err != nil {
+		return err
+	}
+
+	packer := newDSLPacker(project, projectDir, logBuffer)
+	handler := pack.NewHandler(func(ctx context.Context, modelName string, output io.Writer) ([]pack.Diagnostic, error) {
+		return packer.Pack(ctx, modelName, model.PackOutput{
+			Stdout:   os.Stdout,
+			Stderr:   os.Stderr,
+			Progress: output,
+		})
+	})
+
+	address := ctx.String("address")
+	log.Info("Serving pack requests on %s", address)
+	if err := http.ListenAndServe(address, handler); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving pack requests: %w", err)
+	}
+	return nil
+}
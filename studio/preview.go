@@ -9,9 +9,11 @@ import (
 	nativeui "github.com/mokiat/lacking-native/ui"
 	"github.com/mokiat/lacking-studio/internal"
 	"github.com/mokiat/lacking-studio/internal/global"
+	"github.com/mokiat/lacking-studio/internal/logs"
 	"github.com/mokiat/lacking-studio/internal/preview/view"
 	"github.com/mokiat/lacking-studio/resources"
 	"github.com/mokiat/lacking/app"
+	"github.com/mokiat/lacking/debug/log"
 	"github.com/mokiat/lacking/game"
 	"github.com/mokiat/lacking/ui"
 	"github.com/mokiat/lacking/util/resource"
@@ -21,12 +23,26 @@ import (
 func runPreviewApplication(ctx *cli.Context) error {
 	projectDir := cmp.Or(ctx.Args().First(), ".")
 
-	registry, err := createRegistry(projectDir)
+	logBuffer := logs.NewBuffer()
+	if err := logBuffer.CaptureStderr(); err != nil {
+		log.Warn("Error capturing logs: %v", err)
+	}
+
+	project, err := loadProject(ctx, projectDir)
+	if err != nil {
+		return err
+	}
+
+	registry, overrides, err := project.NewOverrideRegistry(projectDir)
 	if err != nil {
 		return fmt.Errorf("error creating registry: %w", err)
 	}
 
 	globalController := global.NewController(
+		projectDir,
+		project,
+		logBuffer,
+		overrides,
 		game.NewController(
 			registry,
 			nativegame.NewShaderCollection(),
@@ -39,7 +55,7 @@ func runPreviewApplication(ctx *cli.Context) error {
 		internal.BootstrapApplication(window, globalController, view.Root)
 	})
 
-	cfg := nativeapp.NewConfig("Lacking Studio [Preview Mode]", 1280, 800)
+	cfg := nativeapp.NewConfig("Lacking Studio [Preview Mode]", project.Window.Width, project.Window.Height)
 	cfg.SetMaximized(true)
 	cfg.SetMinSize(1024, 768)
 	cfg.SetVSync(true)
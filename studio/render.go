@@ -0,0 +1,100 @@
+package studio
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/mokiat/gomath/dprec"
+	nativeapp "github.com/mokiat/lacking-native/app"
+	nativegame "github.com/mokiat/lacking-native/game"
+	"github.com/mokiat/lacking-studio/internal/capture"
+	"github.com/mokiat/lacking-studio/internal/global"
+	"github.com/mokiat/lacking-studio/internal/logs"
+	"github.com/mokiat/lacking-studio/resources"
+	"github.com/mokiat/lacking/app"
+	"github.com/mokiat/lacking/game"
+	"github.com/mokiat/lacking/util/resource"
+	"github.com/urfave/cli/v2"
+)
+
+func runRenderApplication(ctx *cli.Context) error {
+	projectDir := cmp.Or(ctx.Args().First(), ".")
+
+	resourceRef := ctx.Args().Get(1)
+	if resourceRef == "" {
+		return fmt.Errorf("missing model id or name")
+	}
+
+	project, err := loadProject(ctx, projectDir)
+	if err != nil {
+		return err
+	}
+
+	registry, err := project.NewRegistry(projectDir)
+	if err != nil {
+		return fmt.Errorf("error creating registry: %w", err)
+	}
+
+	modelResource := registry.ResourceByID(resourceRef)
+	if modelResource == nil {
+		modelResource = registry.ResourceByName(resourceRef)
+	}
+	if modelResource == nil {
+		return fmt.Errorf("model %q not found", resourceRef)
+	}
+
+	width, height := ctx.Uint("width"), ctx.Uint("height")
+	if width == 0 || height == 0 {
+		return fmt.Errorf("invalid resolution %dx%d", width, height)
+	}
+
+	globalController := global.NewController(
+		projectDir,
+		project,
+		logs.NewBuffer(),
+		nil, // content is not edited while rendering
+		game.NewController(
+			registry,
+			nativegame.NewShaderCollection(),
+			nativegame.NewShaderBuilder(),
+		),
+	)
+
+	captureController := capture.NewController(globalController, capture.Settings{
+		Resource:   modelResource,
+		OutputFile: cmp.Or(ctx.String("output"), modelResource.Name()+".png"),
+
+		Width:  uint32(width),
+		Height: uint32(height),
+
+		Yaw:      dprec.Degrees(ctx.Float64("yaw")),
+		Pitch:    dprec.Degrees(ctx.Float64("pitch")),
+		Zoom:     ctx.Float64("zoom"),
+		Exposure: float32(ctx.Float64("exposure")),
+
+		ShowGrid:             !ctx.Bool("no-grid"),
+		ShowAmbientLight:     true,
+		ShowDirectionalLight: true,
+		ShowSky:              true,
+	})
+
+	locator := resource.NewFSLocator(resources.FS)
+
+	// NOTE: The image is rendered to an offscreen framebuffer of the requested
+	// resolution, so the window is kept small and closes once done.
+	cfg := nativeapp.NewConfig("Lacking Studio [Render Mode]", 320, 200)
+	cfg.SetVSync(false)
+	cfg.SetIcon("icons/favicon.png")
+	cfg.SetLocator(locator)
+	cfg.SetAudioEnabled(false)
+	if err := nativeapp.Run(cfg, app.NewLayeredController(
+		globalController,
+		captureController,
+	)); err != nil {
+		return err
+	}
+	if err := captureController.Err(); err != nil {
+		return fmt.Errorf("error rendering model: %w", err)
+	}
+	return nil
+}
@@ -12,25 +12,193 @@ func Run() error {
 		Usage:       "run the studio application",
 		Description: "Runs the Studio application for the Lacking game engine.",
 		Commands: []*cli.Command{
+			{
+				Name:      "init",
+				Usage:     "Creates a config file for the project",
+				Args:      true,
+				ArgsUsage: "[project dir]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "format of the config file (toml or json)",
+						Value: "toml",
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "overwrites an existing config file",
+					},
+				},
+				Action: runInitApplication,
+			},
 			{
 				Name:      "pack",
 				Usage:     "Packs the assets of the project",
 				Args:      true,
 				ArgsUsage: "[project dir] [model name]",
-				Action:    runPackApplication,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "format of the reported problems (text or json)",
+						Value: "text",
+					},
+				},
+				Action: runPackApplication,
+			},
+			{
+				Name:      "pack-server",
+				Usage:     "Serves pack requests of remote studios",
+				Args:      true,
+				ArgsUsage: "[project dir]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "address",
+						Usage: "address to listen on",
+						Value: "localhost:7400",
+					},
+				},
+				Action: runPackServerApplication,
+			},
+			{
+				Name:      "bundle",
+				Usage:     "Packages assets of the project into an archive",
+				Args:      true,
+				ArgsUsage: "[project dir]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "path to the bundle file (.zip, .tar.gz or .tgz)",
+						Value:   "bundle.tar.gz",
+					},
+					&cli.StringSliceFlag{
+						Name:  "include",
+						Usage: "includes resources whose name matches the pattern",
+					},
+					&cli.StringSliceFlag{
+						Name:  "model",
+						Usage: "includes the resource with the specified name",
+					},
+					assetsFlags[0],
+					assetsFlags[1],
+				},
+				Action: runBundleApplication,
+			},
+			{
+				Name:      "unbundle",
+				Usage:     "Adds the assets of a bundle to the project",
+				Args:      true,
+				ArgsUsage: "<bundle file> [project dir]",
+				Flags:     assetsFlags,
+				Action:    runUnbundleApplication,
+			},
+			{
+				Name:      "gc",
+				Usage:     "Deletes resources that no root model depends on",
+				Args:      true,
+				ArgsUsage: "[project dir]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "root",
+						Usage: "name pattern of a root model (overrides the roots of the project config)",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "lists the resources without deleting them",
+					},
+					assetsFlags[0],
+					assetsFlags[1],
+				},
+				Action: runGCApplication,
+			},
+			{
+				Name:      "graph",
+				Usage:     "Prints the dependency graph of the resources",
+				Args:      true,
+				ArgsUsage: "[project dir]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "format of the graph (dot, json or mermaid)",
+						Value: "dot",
+					},
+					&cli.StringFlag{
+						Name:  "from",
+						Usage: "id or name of a model to show only what it depends on",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "path to the output file (defaults to standard output)",
+					},
+					assetsFlags[0],
+					assetsFlags[1],
+				},
+				Action: runGraphApplication,
 			},
 			{
 				Name:      "preview",
 				Usage:     "Runs the studio in preview mode",
 				Args:      true,
 				ArgsUsage: "[project dir]",
+				Flags:     assetsFlags,
 				Action:    runPreviewApplication,
 			},
+			{
+				Name:      "render",
+				Usage:     "Renders a model of the project to a PNG image",
+				Args:      true,
+				ArgsUsage: "[project dir] [model id or name]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "path to the output PNG file (defaults to <model name>.png)",
+					},
+					&cli.UintFlag{
+						Name:  "width",
+						Usage: "width of the image in pixels",
+						Value: 1280,
+					},
+					&cli.UintFlag{
+						Name:  "height",
+						Usage: "height of the image in pixels",
+						Value: 800,
+					},
+					&cli.Float64Flag{
+						Name:  "yaw",
+						Usage: "camera yaw in degrees",
+						Value: 15.0,
+					},
+					&cli.Float64Flag{
+						Name:  "pitch",
+						Usage: "camera pitch in degrees",
+						Value: 30.0,
+					},
+					&cli.Float64Flag{
+						Name:  "zoom",
+						Usage: "camera zoom as a power of two distance",
+						Value: 3.0,
+					},
+					&cli.Float64Flag{
+						Name:  "exposure",
+						Usage: "camera exposure",
+						Value: 1.0,
+					},
+					&cli.BoolFlag{
+						Name:  "no-grid",
+						Usage: "hides the grid",
+					},
+					assetsFlags[0],
+					assetsFlags[1],
+				},
+				Action: runRenderApplication,
+			},
 			{
 				Name:      "editor",
 				Usage:     "Runs the studio in editing mode",
 				Args:      true,
 				ArgsUsage: "[project dir]",
+				Flags:     assetsFlags,
 				Action:    runEditorApplication,
 			},
 		},